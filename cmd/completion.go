@@ -28,6 +28,21 @@ var completionCmdExample = `1) Using Bash
 	# macOS
 		` + os.Args[0] + ` completion zsh | tee /Users/<your current user>/.zsh/completion/_cec
 
+3) Fish
+
+	# Add to current fish session:
+		` + os.Args[0] + ` completion fish | source
+
+	# Load completions for each session:
+		` + os.Args[0] + ` completion fish > ~/.config/fish/completions/cec.fish
+
+4) PowerShell
+
+	# Add to current PowerShell session:
+		` + os.Args[0] + ` completion powershell | Out-String | Invoke-Expression
+
+	# Load completions for each session, add the output of the above command to your PowerShell profile.
+
 
 #### You must insure the 'bash-completion' library is installed:
 	
@@ -49,19 +64,25 @@ DESCRIPTION
 
   Install a completion helper to the Cells Client.
 
-  This command configures an additional plugin to provide suggestions when hitting the 'tab' key.`,
+  This command configures an additional plugin to provide suggestions when hitting the 'tab' key.
+
+  Remote paths are completed by querying the server: the listings are cached on disk for a few seconds,
+  so that hitting the 'tab' key several times does not flood the server with requests.
+  For the scp command, prefix remote paths with 'cells//' to trigger the remote completion.`,
 	Example: completionCmdExample,
 	Args:    cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		cmd.Help()
 	},
-	ValidArgs: []string{"zsh", "bash"},
+	ValidArgs: []string{"zsh", "bash", "fish", "powershell"},
 }
 
 func init() {
 	RootCmd.AddCommand(completionCmd)
 	completionCmd.AddCommand(bashCompletionCmd)
 	completionCmd.AddCommand(zshCompletionCmd)
+	completionCmd.AddCommand(fishCompletionCmd)
+	completionCmd.AddCommand(powershellCompletionCmd)
 }
 
 var bashCompletionCmd = &cobra.Command{
@@ -78,6 +99,20 @@ var zshCompletionCmd = &cobra.Command{
 	},
 }
 
+var fishCompletionCmd = &cobra.Command{
+	Use: "fish",
+	Run: func(cmd *cobra.Command, args []string) {
+		_ = RootCmd.GenFishCompletion(os.Stdout, true)
+	},
+}
+
+var powershellCompletionCmd = &cobra.Command{
+	Use: "powershell",
+	Run: func(cmd *cobra.Command, args []string) {
+		_ = RootCmd.GenPowerShellCompletionWithDesc(os.Stdout)
+	},
+}
+
 // Reads the bash autocomplete file and prints it to stdout
 func bashAutocomplete() {
 	RootCmd.GenBashCompletion(os.Stdout)
@@ -87,78 +122,17 @@ func zshAutocomplete() {
 	RootCmd.GenZshCompletion(os.Stdout)
 }
 
+// bashCompletionFunc provides the completions that are not yet implemented with a ValidArgsFunction.
+// It is only used by the bash script, remote paths are completed by the completeRemotePaths function.
 var bashCompletionFunc = `__` + os.Args[0] + `_custom_func() {
   case ${last_command} in
-  ` + os.Args[0] + `_mv | ` + os.Args[0] + `_cp | ` + os.Args[0] + `_rm | ` + os.Args[0] + `_ls)
-    _path_completion
-    return
-    ;;
 	` + os.Args[0] + `_storage_resync-ds)
     _datasources_completion
     return
     ;;
-  ` + os.Args[0] + `_scp)
-    _scp_path_completion
-    return
-    ;;
   *) ;;
   esac
 }
-_path_completion() {
-  local lsopts cur dir
-  cur="${COMP_WORDS[COMP_CWORD]}"
-  dir="$(dirname "$cur" 2>/dev/null)"
-
-  currentlength=${#cur}
-  last_char=${cur:currentlength-1:1}
-
-  if [[ $last_char == "/" ]] && [[ currentlength -gt 2 ]]; then
-    dir=$cur
-  elif [[ -z $dir ]]; then
-    dir="/"
-  elif [[ $dir == "." ]]; then
-    dir="/"
-  fi
-
-  IFS=$'\n'
-  lsopts="$(` + os.Args[0] + ` ls --raw $dir)"
-
-  COMPREPLY=($(compgen -W "${lsopts[@]}" -- "$cur"))
-  compopt -o nospace
-  compopt -o filenames
-}
-
-_scp_path_completion() {
-  local lsopts cur dir
-  cur="${COMP_WORDS[COMP_CWORD]}"
-	
-  if [[ $cur != cells//* ]]; then
-    return
-  fi
-
-  prefix="cells//"
-  cur=${cur#$prefix}
-
-  dir="$(dirname "$cur" 2>/dev/null)"
-  currentlength=${#cur}
-  last_char=${cur:currentlength-1:1}
-
-  if [[ $last_char == "/" ]] && [[ currentlength -gt 2 ]]; then
-      dir=$cur
-  elif [[ -z $dir ]]; then
-      dir="/"
-  elif [[ $dir == "." ]]; then
-      dir="/"
-  fi
-
-  IFS=$'\n'
-  lsopts="$(` + os.Args[0] + ` ls --raw $dir)"
-
-  COMPREPLY=($(compgen -P "$prefix" -W "${lsopts[@]}" -- "$cur"))
-  #COMPREPLY=(${COMPREPLY[@]/#/"${prefix}"})
-  compopt -o nospace
-  compopt -o filenames
-}
 
 _datasources_completion() {
   local dsopts cur
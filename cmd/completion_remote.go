@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pydio/cells-client/v4/rest"
+)
+
+// completionCacheTTL is the duration during which a listing is reused to complete remote paths.
+const completionCacheTTL = 30 * time.Second
+
+type completionEntry struct {
+	Name  string `json:"name"`
+	IsDir bool   `json:"isDir"`
+}
+
+// completeRemotePaths is a cobra ValidArgsFunction that completes remote paths by listing the content of the parent folder.
+// For the scp command, only the arguments with the 'cells//' or 'cells://' prefix are remote, local files are completed otherwise.
+func completeRemotePaths(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	prefix := ""
+	if cmd.Name() == "scp" {
+		for _, p := range []string{standardPrefix, completionPrefix} {
+			if strings.HasPrefix(toComplete, p) {
+				prefix = p
+			}
+		}
+		if prefix == "" {
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+	}
+	if sdkClient == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	typed := strings.TrimPrefix(toComplete, prefix)
+	dir, base := "", typed
+	if i := strings.LastIndex(typed, "/"); i >= 0 {
+		dir, base = typed[:i+1], typed[i+1:]
+	}
+
+	entries, err := listForCompletion(ctx, strings.Trim(dir, "/"))
+	if err != nil {
+		cobra.CompDebugln("could not list "+dir+": "+err.Error(), false)
+		return nil, cobra.ShellCompDirectiveError
+	}
+	var candidates []string
+	for _, e := range entries {
+		if !strings.HasPrefix(e.Name, base) {
+			continue
+		}
+		candidate := prefix + dir + e.Name
+		if e.IsDir {
+			candidate += "/"
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates, cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+}
+
+// listForCompletion returns the children of a remote folder, using a short-lived cache on the disk
+// as the completion is run in a new process each time the user hits the tab key.
+func listForCompletion(ctx context.Context, folder string) ([]*completionEntry, error) {
+	cacheFile := completionCacheFile(folder)
+	if cacheFile != "" {
+		if info, e := os.Stat(cacheFile); e == nil && time.Since(info.ModTime()) < completionCacheTTL {
+			if data, e := os.ReadFile(cacheFile); e == nil {
+				var entries []*completionEntry
+				if json.Unmarshal(data, &entries) == nil {
+					return entries, nil
+				}
+			}
+		}
+	}
+
+	pattern := "/*"
+	if folder != "" {
+		pattern = path.Join(folder, "*")
+	}
+	nodes, err := sdkClient.GetAllBulkMeta(ctx, pattern)
+	if err != nil {
+		return nil, err
+	}
+	var entries []*completionEntry
+	for _, n := range nodes {
+		name := path.Base(n.Path)
+		if name == "" || strings.HasPrefix(name, ".") {
+			// Skip the technical files, like .pydio
+			continue
+		}
+		entries = append(entries, &completionEntry{Name: name, IsDir: rest.IsFolder(n)})
+	}
+
+	if cacheFile != "" {
+		if data, e := json.Marshal(entries); e == nil && os.MkdirAll(filepath.Dir(cacheFile), 0700) == nil {
+			_ = os.WriteFile(cacheFile, data, 0600)
+		}
+	}
+	return entries, nil
+}
+
+// completionCacheFile returns the path of the cache file for the passed folder of the current account,
+// or an empty string if no cache directory is available.
+func completionCacheFile(folder string) string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	conf := sdkClient.GetConfig()
+	h := sha1.Sum([]byte(conf.Url + "\n" + conf.User + "\n" + folder))
+	return filepath.Join(cacheDir, "cells-client", "completion", hex.EncodeToString(h[:]))
+}
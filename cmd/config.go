@@ -218,7 +218,7 @@ DESCRIPTION
 
 		if err := rest.CheckKeyring(); err != nil {
 			fmt.Println(pui.IconWarn + " " + rest.NoKeyringMsg)
-			os.Exit(1)
+			exit(1)
 		} else {
 			fmt.Println(pui.IconGood + " Keyring seems to be here and working.")
 		}
@@ -3,7 +3,6 @@ package cmd
 import (
 	"errors"
 	"fmt"
-	"log"
 	"os"
 	"sort"
 
@@ -46,9 +45,9 @@ If no keyring is defined in the client machine, all information is stored in *cl
 		n, _, err := s.Run()
 		if err != nil {
 			if errors.Is(err, promptui.ErrInterrupt) {
-				log.Fatal("operation aborted by user")
+				rest.Log.Fatal("operation aborted by user")
 			}
-			log.Fatal(err)
+			rest.Log.Fatal(err)
 		}
 
 		switch n {
@@ -59,7 +58,7 @@ If no keyring is defined in the client machine, all information is stored in *cl
 		case 2:
 			configureClientAuthCmd.Run(cmd, args)
 		default:
-			log.Fatal("no authentication method was selected")
+			rest.Log.Fatal("no authentication method was selected")
 		}
 	},
 }
@@ -96,9 +95,9 @@ Please update your scripts to be ready.
 		n, _, err := s.Run()
 		if err != nil {
 			if errors.Is(err, promptui.ErrInterrupt) {
-				log.Fatal("operation aborted by user")
+				rest.Log.Fatal("operation aborted by user")
 			}
-			log.Fatal(err)
+			rest.Log.Fatal(err)
 		}
 
 		switch n {
@@ -109,7 +108,7 @@ Please update your scripts to be ready.
 		case 2:
 			configureClientAuthCmd.Run(cmd, args)
 		default:
-			log.Fatal("no authentication method was selected")
+			rest.Log.Fatal("no authentication method was selected")
 		}
 	},
 }
@@ -212,11 +211,11 @@ func relog(oldConf *rest.CecConfig) error {
 	case cellsSdk.AuthTypeClientAuth:
 		err = interactiveClientAuth(oldConf)
 	default:
-		log.Fatalf("Cannot relog for authentication type: %s", oldConf.AuthType)
+		rest.Log.Fatalf("Cannot relog for authentication type: %s", oldConf.AuthType)
 	}
 	err = persistConfig(oldConf)
 	if err != nil {
-		log.Fatal(err.Error())
+		rest.Log.Fatal(err.Error())
 	}
 	return err
 }
@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
 	"net/url"
 
 	"github.com/manifoldco/promptui"
@@ -46,13 +45,13 @@ USAGE
 		}
 		if err != nil {
 			if errors.Is(err, promptui.ErrInterrupt) {
-				log.Fatalf("operation aborted by user")
+				rest.Log.Fatalf("operation aborted by user")
 			}
-			log.Fatalf(err.Error())
+			rest.Log.Fatalf(err.Error())
 		}
 		err = persistConfig(newConf)
 		if err != nil {
-			log.Fatal(err.Error())
+			rest.Log.Fatal(err.Error())
 		}
 	},
 }
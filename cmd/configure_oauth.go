@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
 	"net"
 	"net/http"
 	"net/url"
@@ -80,13 +79,13 @@ USAGE
 		}
 		if err != nil {
 			if errors.Is(err, promptui.ErrInterrupt) {
-				log.Fatal("operation aborted by user")
+				rest.Log.Fatal("operation aborted by user")
 			}
-			log.Fatal(err.Error())
+			rest.Log.Fatal(err.Error())
 		}
 		err = persistConfig(newConf)
 		if err != nil {
-			log.Fatal(err.Error())
+			rest.Log.Fatal(err.Error())
 		}
 	},
 }
@@ -177,7 +176,7 @@ func oAuthInteractive(newConf *rest.CecConfig) error {
 
 	directUrl, err := sdkRest.OAuthPrepareUrl(common.AppName, state, newConf.Url, callbackUrl)
 	if err != nil {
-		log.Fatal(err)
+		rest.Log.Fatal(err)
 	}
 	if openBrowser {
 		go open.Run(directUrl)
@@ -193,7 +192,7 @@ func oAuthInteractive(newConf *rest.CecConfig) error {
 		}()
 		srv.ListenAndServe()
 		if h.err != nil {
-			log.Fatal("Could not correctly connect", h.err)
+			rest.Log.Fatal("Could not correctly connect", h.err)
 		}
 		returnCode = h.code
 	} else {
@@ -206,13 +205,13 @@ func oAuthInteractive(newConf *rest.CecConfig) error {
 		}
 		returnCode, err = pr.Run()
 		if err != nil {
-			log.Fatal("Could not read code!")
+			rest.Log.Fatal("Could not read code!")
 		}
 	}
 
 	fmt.Println(promptui.IconGood + " Now exchanging the code for a valid IdToken")
 	if err := sdkRest.OAuthExchangeCode(newConf.SdkConfig, common.AppName, returnCode, callbackUrl); err != nil {
-		log.Fatal(err)
+		rest.Log.Fatal(err)
 	}
 	fmt.Printf("%s Successfully Received Token. It will be refreshed at %v\n", promptui.IconGood, time.Unix(int64(newConf.TokenExpiresAt), 0))
 	return nil
@@ -3,7 +3,6 @@ package cmd
 import (
 	"errors"
 	"fmt"
-	"log"
 	"net/url"
 	"strings"
 
@@ -43,13 +42,13 @@ DESCRIPTION
 		} else {
 			err = interractiveTokenAuth(newConf)
 			if err != nil {
-				log.Fatalf(err.Error())
+				rest.Log.Fatalf(err.Error())
 			}
 		}
 
 		err = persistConfig(newConf)
 		if err != nil {
-			log.Fatalf(err.Error())
+			rest.Log.Fatalf(err.Error())
 		}
 	},
 }
@@ -60,18 +59,18 @@ func interractiveTokenAuth(newConf *rest.CecConfig) error {
 	newConf.Url, err = p.Run()
 	if err != nil {
 		if errors.Is(err, promptui.ErrInterrupt) {
-			log.Fatalf("operation aborted by user")
+			rest.Log.Fatalf("operation aborted by user")
 		}
-		log.Fatalf("%s URL is not valid %s", promptui.IconBad, err.Error())
+		rest.Log.Fatalf("%s URL is not valid %s", promptui.IconBad, err.Error())
 	}
 	newConf.Url, err = rest.CleanURL(newConf.Url)
 	if err != nil {
-		log.Fatalf("%s %s", promptui.IconBad, err.Error())
+		rest.Log.Fatalf("%s %s", promptui.IconBad, err.Error())
 	}
 
 	u, e := url.Parse(newConf.Url)
 	if e != nil {
-		log.Fatal("", err)
+		rest.Log.Fatal("", err)
 	}
 	if u.Scheme == "https" {
 		// PROMPT SKIP VERIFY
@@ -91,9 +90,9 @@ func interractiveTokenAuth(newConf *rest.CecConfig) error {
 	newConf.IdToken, err = p.Run()
 	if err != nil {
 		if errors.Is(err, promptui.ErrInterrupt) {
-			log.Fatalf("operation aborted by user")
+			rest.Log.Fatalf("operation aborted by user")
 		}
-		log.Fatalf(err.Error())
+		rest.Log.Fatalf(err.Error())
 	}
 	return nil
 }
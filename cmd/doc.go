@@ -1,12 +1,12 @@
 package cmd
 
 import (
-	"log"
-
 	"github.com/pydio/go/docs"
 	"github.com/spf13/cobra"
 
 	"github.com/pydio/cells-client/v4/common"
+
+	"github.com/pydio/cells-client/v4/rest"
 )
 
 var docPath string
@@ -27,13 +27,13 @@ DESCRIPTION
 	Run: func(cmd *cobra.Command, args []string) {
 
 		if docPath == "" {
-			log.Fatal("Please provide a path to store output files")
+			rest.Log.Fatal("Please provide a path to store output files")
 		} else {
 
 			docs.PydioDocsGeneratedBy = common.PackageLabel + " v" + common.Version
 			err := docs.GenMarkdownTree(RootCmd, docPath)
 			if err != nil {
-				log.Fatal(err)
+				rest.Log.Fatal(err)
 			}
 		}
 
@@ -0,0 +1,661 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pydio/cells-sdk-go/v4/client/tree_service"
+	"github.com/pydio/cells-sdk-go/v4/client/user_meta_service"
+	"github.com/pydio/cells-sdk-go/v4/models"
+
+	"github.com/pydio/cells-client/v4/rest"
+)
+
+const (
+	batchStatusOK      = "ok"
+	batchStatusFailed  = "failed"
+	batchStatusSkipped = "skipped"
+)
+
+var (
+	batchFile            string
+	batchConcurrency     int
+	batchContinueOnError bool
+	batchForce           bool
+	batchFormat          string
+)
+
+var batchCmd = &cobra.Command{
+	Use:   "batch",
+	Short: "Run a list of file operations from a manifest",
+	Long: `
+DESCRIPTION
+
+  Execute the file operations that are listed in a manifest file, e.g. generated from a spreadsheet.
+
+  The manifest is either a JSON Lines file, with one JSON object per line, or a CSV file (with the .csv extension)
+  whose first line gives the names of the columns. Both formats use the same fields:
+   - op: the operation, one of mv, cp, rm, mkdir, meta-set, upload or download
+   - path: the remote node to process, or the local source for an upload
+   - target: the destination of mv, cp, upload and download operations
+   - namespace and value: the metadata to set with meta-set. An empty value deletes the metadata
+   - permanently: for rm, skip the recycle bin
+
+  Operations behave like the corresponding commands:
+   - mv: when the target ends with a '/', the node is moved into this existing folder, otherwise it is renamed
+   - cp: the node is copied into the target folder
+   - mkdir: missing parent folders are also created
+   - meta-set: the value is stored as is, without the checks that are performed by the 'meta set' command
+   - upload and download: the target is the parent folder, like with scp.
+     Existing items are only overwritten with the --force flag
+
+  Consecutive operations of the same type are run concurrently and grouped into a single request when the API
+  allows it, e.g. all moves to the same folder are done with a single job. Operations of different types
+  are run in the order of the manifest, so that you can for instance create a folder and then upload files in it.
+
+  The result of each line of the manifest is printed as soon as it is known.
+  By default, the batch stops after the first group of operations with errors, and the remaining lines are skipped:
+  use the --continue-on-error flag to process all lines. The command exits with status 1 if an operation has failed.
+
+EXAMPLES
+
+  # ops.jsonl
+  {"op":"mkdir","path":"common-files/archives/2023"}
+  {"op":"mv","path":"common-files/report.pdf","target":"common-files/archives/2023/"}
+  {"op":"meta-set","path":"common-files/archives/2023/report.pdf","namespace":"usermeta-tags","value":"archived"}
+  {"op":"upload","path":"./scans","target":"common-files/archives/2023"}
+  {"op":"rm","path":"common-files/tmp"}
+
+  $ ` + os.Args[0] + ` batch -f ops.jsonl --continue-on-error
+
+  # ops.csv
+  op,path,target
+  cp,common-files/template.docx,personal-files/
+  download,common-files/archives,./backup
+
+  $ ` + os.Args[0] + ` batch -f ops.csv --format json
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		if batchFile == "" {
+			rest.Log.Fatalln("Please provide the path to the manifest file with the --file flag")
+		}
+		if batchFormat != "text" && batchFormat != "json" {
+			rest.Log.Fatalf("Invalid output format %s, it must be either text or json\n", batchFormat)
+		}
+		if batchConcurrency < 1 {
+			batchConcurrency = 1
+		}
+
+		ops, err := readBatchManifest(batchFile)
+		if err != nil {
+			rest.Log.Fatalln(err)
+		}
+		if len(ops) == 0 {
+			rest.Log.Fatalf("No operation found in %s\n", batchFile)
+		}
+
+		r := &batchRunner{ctx: ctx, sem: make(chan struct{}, batchConcurrency)}
+		for _, stage := range batchStages(ops) {
+			if r.failed > 0 && !batchContinueOnError {
+				for _, o := range stage {
+					r.report(o, batchStatusSkipped, nil)
+				}
+				continue
+			}
+			r.runStage(stage)
+		}
+
+		if batchFormat == "text" {
+			fmt.Printf("%d operation(s) succeeded, %d failed and %d skipped\n", r.ok, r.failed, r.skipped)
+		}
+		if r.failed > 0 {
+			exit(1)
+		}
+	},
+}
+
+func init() {
+	flags := batchCmd.Flags()
+	flags.StringVarP(&batchFile, "file", "f", "", "Path to the manifest file, in JSON Lines or CSV format")
+	flags.IntVarP(&batchConcurrency, "concurrency", "c", 4, "Maximum number of requests or transfers that are run in parallel")
+	flags.BoolVar(&batchContinueOnError, "continue-on-error", false, "Process all operations, even after an error")
+	flags.BoolVar(&batchForce, "force", false, "Overwrite existing items when uploading or downloading")
+	flags.StringVar(&batchFormat, "format", "text", "Output format of the results: text|json")
+	RootCmd.AddCommand(batchCmd)
+}
+
+// batchOp is a single operation of the manifest.
+type batchOp struct {
+	Line        int             `json:"-"`
+	Op          string          `json:"op"`
+	Path        string          `json:"path"`
+	Target      string          `json:"target,omitempty"`
+	Namespace   string          `json:"namespace,omitempty"`
+	Value       json.RawMessage `json:"value,omitempty"`
+	Permanently bool            `json:"permanently,omitempty"`
+}
+
+// batchResult is printed for each line of the manifest.
+type batchResult struct {
+	Line   int    `json:"line"`
+	Op     string `json:"op"`
+	Path   string `json:"path"`
+	Target string `json:"target,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+func readBatchManifest(manifestPath string) ([]*batchOp, error) {
+	file, err := os.Open(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not open manifest: %s", err.Error())
+	}
+	defer file.Close()
+
+	var ops []*batchOp
+	if strings.ToLower(filepath.Ext(manifestPath)) == ".csv" {
+		ops, err = readCsvOps(file)
+	} else {
+		ops, err = readJsonOps(file)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range ops {
+		if err = o.validate(); err != nil {
+			return nil, fmt.Errorf("invalid operation at line %d: %s", o.Line, err.Error())
+		}
+	}
+	return ops, nil
+}
+
+func readJsonOps(reader io.Reader) ([]*batchOp, error) {
+	var ops []*batchOp
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		txt := strings.TrimSpace(scanner.Text())
+		if txt == "" || strings.HasPrefix(txt, "#") {
+			continue
+		}
+		o := &batchOp{}
+		if err := json.Unmarshal([]byte(txt), o); err != nil {
+			return nil, fmt.Errorf("could not parse line %d: %s", line, err.Error())
+		}
+		o.Line = line
+		ops = append(ops, o)
+	}
+	return ops, scanner.Err()
+}
+
+func readCsvOps(reader io.Reader) ([]*batchOp, error) {
+	r := csv.NewReader(reader)
+	r.FieldsPerRecord = -1
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("could not read CSV header: %s", err.Error())
+	}
+	for i, h := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(h))
+		switch header[i] {
+		case "op", "path", "target", "namespace", "value", "permanently":
+		default:
+			return nil, fmt.Errorf("unknown column %s in CSV header", h)
+		}
+	}
+
+	var ops []*batchOp
+	line := 1
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("could not parse CSV: %s", err.Error())
+		}
+		line++
+		o := &batchOp{Line: line}
+		for i, v := range record {
+			if i >= len(header) {
+				break
+			}
+			switch header[i] {
+			case "op":
+				o.Op = strings.TrimSpace(v)
+			case "path":
+				o.Path = v
+			case "target":
+				o.Target = v
+			case "namespace":
+				o.Namespace = v
+			case "value":
+				if v != "" {
+					o.Value, _ = json.Marshal(v)
+				}
+			case "permanently":
+				if v != "" {
+					if o.Permanently, err = strconv.ParseBool(v); err != nil {
+						return nil, fmt.Errorf("invalid permanently value at line %d: %s", line, v)
+					}
+				}
+			}
+		}
+		if o.Op == "" && o.Path == "" {
+			continue
+		}
+		ops = append(ops, o)
+	}
+	return ops, nil
+}
+
+func (o *batchOp) validate() error {
+	if o.Path == "" {
+		return fmt.Errorf("path is required")
+	}
+	if o.Op != "upload" {
+		o.Path = strings.Trim(trimRemotePrefix(o.Path), "/")
+	}
+	switch o.Op {
+	case "mv", "cp", "upload", "download":
+		if o.Target == "" {
+			return fmt.Errorf("target is required for %s", o.Op)
+		}
+		if o.Op != "download" {
+			folder := strings.HasSuffix(o.Target, "/")
+			o.Target = strings.Trim(trimRemotePrefix(o.Target), "/")
+			if folder && o.Op == "mv" {
+				o.Target += "/"
+			}
+		}
+	case "meta-set":
+		if o.Namespace == "" {
+			return fmt.Errorf("namespace is required for meta-set")
+		}
+	case "rm", "mkdir":
+	default:
+		return fmt.Errorf("unknown operation '%s'", o.Op)
+	}
+	return nil
+}
+
+// jsonValue returns the value to store in the metadata: strings and JSON objects are kept as is,
+// other scalar values are stored as strings like with the meta set command.
+func (o *batchOp) jsonValue() string {
+	v := strings.TrimSpace(string(o.Value))
+	if v == "" || v == "null" {
+		return emptyJson
+	}
+	if strings.HasPrefix(v, "\"") || strings.HasPrefix(v, "{") || strings.HasPrefix(v, "[") {
+		return v
+	}
+	return strconv.Quote(v)
+}
+
+// batchStages splits the operations in groups of consecutive operations of the same type.
+func batchStages(ops []*batchOp) [][]*batchOp {
+	var stages [][]*batchOp
+	for i, o := range ops {
+		if i == 0 || ops[i-1].Op != o.Op {
+			stages = append(stages, nil)
+		}
+		stages[len(stages)-1] = append(stages[len(stages)-1], o)
+	}
+	return stages
+}
+
+// batchTask performs one or more operations of the manifest with a single call.
+type batchTask struct {
+	ops []*batchOp
+	run func() error
+}
+
+// batchOpErrors is returned by the tasks that only partially failed: it gives the errors of the failed operations,
+// the other operations of the task have succeeded.
+type batchOpErrors map[*batchOp]error
+
+func (e batchOpErrors) Error() string {
+	return fmt.Sprintf("%d operation(s) failed", len(e))
+}
+
+type batchRunner struct {
+	ctx context.Context
+	sem chan struct{}
+
+	sync.Mutex
+	ok, failed, skipped int
+}
+
+// runStage runs the tasks of a stage with bounded concurrency. Once an error has occurred,
+// the tasks that are not yet started are skipped, unless we continue on errors.
+func (r *batchRunner) runStage(stage []*batchOp) {
+	wg := &sync.WaitGroup{}
+	for _, task := range r.tasks(stage) {
+		r.sem <- struct{}{}
+		wg.Add(1)
+		go func(t *batchTask) {
+			defer func() {
+				<-r.sem
+				wg.Done()
+			}()
+			r.Lock()
+			skip := r.failed > 0 && !batchContinueOnError
+			r.Unlock()
+			if skip {
+				for _, o := range t.ops {
+					r.report(o, batchStatusSkipped, nil)
+				}
+				return
+			}
+			err := t.run()
+			opErrs, partial := err.(batchOpErrors)
+			for _, o := range t.ops {
+				switch {
+				case partial && opErrs[o] != nil:
+					r.report(o, batchStatusFailed, opErrs[o])
+				case err != nil && !partial:
+					r.report(o, batchStatusFailed, err)
+				default:
+					r.report(o, batchStatusOK, nil)
+				}
+			}
+		}(task)
+	}
+	wg.Wait()
+}
+
+func (r *batchRunner) report(o *batchOp, status string, err error) {
+	r.Lock()
+	defer r.Unlock()
+	res := &batchResult{Line: o.Line, Op: o.Op, Path: o.Path, Target: o.Target, Status: status}
+	switch status {
+	case batchStatusOK:
+		r.ok++
+	case batchStatusFailed:
+		r.failed++
+		res.Error = err.Error()
+	case batchStatusSkipped:
+		r.skipped++
+	}
+
+	if batchFormat == "json" {
+		data, _ := json.Marshal(res)
+		fmt.Println(string(data))
+		return
+	}
+	msg := fmt.Sprintf("[%s] line %d: %s %s", strings.ToUpper(status), res.Line, res.Op, res.Path)
+	if res.Target != "" {
+		msg += " -> " + res.Target
+	}
+	if res.Error != "" {
+		msg += ": " + res.Error
+	}
+	fmt.Println(msg)
+}
+
+// tasks prepares the calls for a stage, grouping operations when the API allows it.
+func (r *batchRunner) tasks(stage []*batchOp) []*batchTask {
+	ctx := r.ctx
+	var tasks []*batchTask
+	switch stage[0].Op {
+	case "mv", "cp":
+		// Group moves and copies by target folder, renames are done one by one
+		byTarget := make(map[string][]*batchOp)
+		var targets []string
+		for _, o := range stage {
+			if o.Op == "mv" && !strings.HasSuffix(o.Target, "/") {
+				tasks = append(tasks, &batchTask{ops: []*batchOp{o}, run: r.jobRunner(o.Op, []*batchOp{o}, o.Target)})
+				continue
+			}
+			if _, ok := byTarget[o.Target]; !ok {
+				targets = append(targets, o.Target)
+			}
+			byTarget[o.Target] = append(byTarget[o.Target], o)
+		}
+		for _, t := range targets {
+			tasks = append(tasks, &batchTask{ops: byTarget[t], run: r.jobRunner(stage[0].Op, byTarget[t], t)})
+		}
+
+	case "rm":
+		// One call for the items that go to the recycle bin and one for the permanently deleted items
+		for _, permanently := range []bool{false, true} {
+			var group []*batchOp
+			var paths []string
+			for _, o := range stage {
+				if o.Permanently == permanently {
+					group = append(group, o)
+					paths = append(paths, o.Path)
+				}
+			}
+			if len(group) == 0 {
+				continue
+			}
+			perm := permanently
+			tasks = append(tasks, &batchTask{ops: group, run: func() error {
+				jobIDs, err := sdkClient.DeleteNodes(ctx, paths, perm)
+				if err != nil {
+					return err
+				}
+				for _, id := range jobIDs {
+					if err = sdkClient.MonitorJob(ctx, id); err != nil {
+						return err
+					}
+				}
+				return nil
+			}})
+		}
+
+	case "mkdir":
+		tasks = append(tasks, &batchTask{ops: stage, run: func() error {
+			return batchMkdir(ctx, stage)
+		}})
+
+	case "meta-set":
+		tasks = append(tasks, &batchTask{ops: stage, run: func() error {
+			return r.batchMetaSet(stage)
+		}})
+
+	case "upload", "download":
+		for _, o := range stage {
+			op := o
+			tasks = append(tasks, &batchTask{ops: []*batchOp{op}, run: func() error {
+				return batchTransfer(ctx, op)
+			}})
+		}
+	}
+	return tasks
+}
+
+// jobRunner returns a function that moves or copies all the passed nodes to the target with a single job.
+func (r *batchRunner) jobRunner(op string, ops []*batchOp, target string) func() error {
+	return func() error {
+		var sources []string
+		for _, o := range ops {
+			sources = append(sources, o.Path)
+		}
+		var jobID string
+		var err error
+		if op == "cp" {
+			jobID, err = sdkClient.CopyJob(r.ctx, rest.CopyParams(sources, target))
+		} else {
+			jobID, err = sdkClient.MoveJob(r.ctx, rest.MoveParams(sources, target))
+		}
+		if err != nil {
+			return fmt.Errorf("could not run job: %s", err.Error())
+		}
+		return sdkClient.MonitorJob(r.ctx, jobID)
+	}
+}
+
+// batchMkdir creates all the folders of the stage and their missing parents with a single call.
+func batchMkdir(ctx context.Context, ops []*batchOp) error {
+	known := make(map[string]bool)
+	var candidates []string
+	for _, o := range ops {
+		parts := strings.Split(o.Path, "/")
+		if len(parts) < 2 {
+			return fmt.Errorf("cannot create %s: the path must at least contain a workspace segment", o.Path)
+		}
+		crt := parts[0]
+		for _, p := range parts[1:] {
+			crt = path.Join(crt, p)
+			if !known[crt] {
+				known[crt] = true
+				candidates = append(candidates, crt)
+			}
+		}
+	}
+	existing, err := sdkClient.StatNodes(ctx, candidates)
+	if err != nil {
+		return err
+	}
+	var paths []string
+	for _, p := range candidates {
+		if _, ok := existing[p]; !ok {
+			paths = append(paths, p)
+		}
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+	sort.Strings(paths)
+	var dirs []*models.TreeNode
+	for _, p := range paths {
+		dirs = append(dirs, &models.TreeNode{Path: p, Type: models.NewTreeNodeType(models.TreeNodeTypeCOLLECTION)})
+	}
+	_, err = sdkClient.GetApiClient().TreeService.CreateNodes(&tree_service.CreateNodesParams{
+		Body:    &models.RestCreateNodesRequest{Nodes: dirs},
+		Context: ctx,
+	})
+	sdkClient.InvalidateMeta(paths...)
+	return err
+}
+
+// batchMetaSet resolves the nodes concurrently, then updates all the metadata with a single call.
+// Operations on missing nodes fail without preventing the update of the other nodes.
+func (r *batchRunner) batchMetaSet(ops []*batchOp) error {
+	metas := make([]*models.IdmUserMeta, len(ops))
+	errs := make([]error, len(ops))
+	wg := &sync.WaitGroup{}
+	limit := make(chan struct{}, batchConcurrency)
+	for i, o := range ops {
+		wg.Add(1)
+		limit <- struct{}{}
+		go func(i int, o *batchOp) {
+			defer func() {
+				<-limit
+				wg.Done()
+			}()
+			node, exists := sdkClient.StatNode(r.ctx, o.Path)
+			if !exists {
+				errs[i] = fmt.Errorf("no folder/file found at %s (line %d)", o.Path, o.Line)
+				return
+			}
+			metas[i] = &models.IdmUserMeta{Namespace: o.Namespace, NodeUUID: node.UUID, JSONValue: o.jsonValue()}
+		}(i, o)
+	}
+	wg.Wait()
+	failures := make(batchOpErrors)
+	var resolved []*models.IdmUserMeta
+	for i, e := range errs {
+		if e != nil {
+			failures[ops[i]] = e
+		} else {
+			resolved = append(resolved, metas[i])
+		}
+	}
+	if len(resolved) == 0 {
+		return failures
+	}
+
+	// Use PUT with an empty value for DELETE, see the meta set command
+	opPut := models.UpdateUserMetaRequestUserMetaOpPUT
+	_, err := sdkClient.GetApiClient().UserMetaService.UpdateUserMeta(&user_meta_service.UpdateUserMetaParams{
+		Body:    &models.IdmUpdateUserMetaRequest{MetaDatas: resolved, Operation: &opPut},
+		Context: r.ctx,
+	})
+	for _, o := range ops {
+		sdkClient.InvalidateMeta(o.Path)
+	}
+	if err != nil {
+		return err
+	} else if len(failures) > 0 {
+		return failures
+	}
+	return nil
+}
+
+// batchTransfer uploads or downloads a file or a folder in the target folder, without progress bars.
+func batchTransfer(ctx context.Context, o *batchOp) error {
+	isSrcLocal := o.Op == "upload"
+	var srcPath, targetPath string
+	var needMerge bool
+	var err error
+	if isSrcLocal {
+		if srcPath, err = filepath.Abs(o.Path); err != nil {
+			return err
+		}
+		if _, err = os.Stat(srcPath); err != nil {
+			return err
+		}
+		targetPath = o.Target
+		if needMerge, err = preProcessRemoteTarget(ctx, sdkClient, filepath.Base(srcPath), targetPath, batchForce); err != nil {
+			return err
+		}
+	} else {
+		srcPath = o.Path
+		if _, ok := sdkClient.StatNode(ctx, srcPath); !ok {
+			return fmt.Errorf("cannot find %s on remote server", srcPath)
+		}
+		if targetPath, err = filepath.Abs(o.Target); err != nil {
+			return err
+		}
+		if needMerge, err = preProcessLocalTarget(path.Base(srcPath), targetPath, batchForce); err != nil {
+			return err
+		}
+	}
+
+	srcNode, err := rest.NewCrawler(ctx, sdkClient, srcPath, isSrcLocal)
+	if err != nil {
+		return err
+	}
+	targetNode := rest.NewTarget(sdkClient, targetPath, !isSrcLocal, srcNode.IsDir, batchForce)
+	var tf *rest.CrawlNode
+	if needMerge {
+		tf = targetNode
+	}
+	t, c, d, err := srcNode.Walk(ctx, tf)
+	if err != nil {
+		return err
+	}
+	if err = targetNode.DeleteForMerge(ctx, d, nil); err != nil {
+		return err
+	}
+	if err = targetNode.CreateFolders(ctx, targetNode, c, nil); err != nil {
+		return err
+	}
+	if errs := targetNode.TransferAll(ctx, t, nil); len(errs) > 0 {
+		var msgs []string
+		for _, e := range errs {
+			msgs = append(msgs, e.Error())
+		}
+		return fmt.Errorf("%d transfer(s) failed: %s", len(errs), strings.Join(msgs, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	// Silently import convey to ease implementation
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestReadBatchOps(t *testing.T) {
+	Convey("Test parsing of batch manifests", t, func() {
+		Convey("JSON Lines manifests skip empty lines and comments", func() {
+			ops, err := readJsonOps(strings.NewReader(`# Reorganize the reports
+{"op": "mkdir", "path": "cells://common-files/archive"}
+
+{"op": "meta-set", "path": "common-files/report.pdf", "namespace": "usermeta-status", "value": "done"}
+`))
+			So(err, ShouldBeNil)
+			So(ops, ShouldHaveLength, 2)
+			So(ops[0].Line, ShouldEqual, 2)
+			So(ops[0].Op, ShouldEqual, "mkdir")
+			So(ops[1].Line, ShouldEqual, 4)
+			So(ops[1].jsonValue(), ShouldEqual, `"done"`)
+
+			_, err = readJsonOps(strings.NewReader(`{"op": "rm", "path":`))
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("CSV manifests map the columns of the header", func() {
+			ops, err := readCsvOps(strings.NewReader(`Op,Path,Target,Permanently
+mv,common-files/a.txt,common-files/archive/,
+rm,common-files/b.txt,,true
+,,,
+`))
+			So(err, ShouldBeNil)
+			So(ops, ShouldHaveLength, 2)
+			So(ops[0].Line, ShouldEqual, 2)
+			So(ops[0].Target, ShouldEqual, "common-files/archive/")
+			So(ops[1].Line, ShouldEqual, 3)
+			So(ops[1].Permanently, ShouldBeTrue)
+
+			_, err = readCsvOps(strings.NewReader("op,path,size\n"))
+			So(err, ShouldNotBeNil)
+			_, err = readCsvOps(strings.NewReader("op,path,permanently\nrm,a.txt,maybe\n"))
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Operations are validated and their paths normalized", func() {
+			o := &batchOp{Op: "mv", Path: "cells://common-files/a.txt", Target: "cells://common-files/archive/"}
+			So(o.validate(), ShouldBeNil)
+			So(o.Path, ShouldEqual, "common-files/a.txt")
+			So(o.Target, ShouldEqual, "common-files/archive/")
+
+			o = &batchOp{Op: "cp", Path: "common-files/a.txt", Target: "common-files/archive/"}
+			So(o.validate(), ShouldBeNil)
+			So(o.Target, ShouldEqual, "common-files/archive")
+
+			So((&batchOp{Op: "upload", Path: "./a.txt"}).validate(), ShouldNotBeNil)
+			So((&batchOp{Op: "meta-set", Path: "common-files/a.txt"}).validate(), ShouldNotBeNil)
+			So((&batchOp{Op: "chmod", Path: "common-files/a.txt"}).validate(), ShouldNotBeNil)
+			So((&batchOp{Op: "rm"}).validate(), ShouldNotBeNil)
+		})
+
+		Convey("Metadata values are stored as JSON", func() {
+			So((&batchOp{}).jsonValue(), ShouldEqual, emptyJson)
+			So((&batchOp{Value: []byte(`{"a": 1}`)}).jsonValue(), ShouldEqual, `{"a": 1}`)
+			So((&batchOp{Value: []byte(`42`)}).jsonValue(), ShouldEqual, `"42"`)
+		})
+	})
+}
+
+func TestBatchStages(t *testing.T) {
+	Convey("Test grouping of consecutive operations", t, func() {
+		var ops []*batchOp
+		for _, op := range []string{"mkdir", "mkdir", "mv", "rm", "rm", "mv"} {
+			ops = append(ops, &batchOp{Op: op})
+		}
+		stages := batchStages(ops)
+		So(stages, ShouldHaveLength, 4)
+		So(stages[0], ShouldHaveLength, 2)
+		So(stages[1][0].Op, ShouldEqual, "mv")
+		So(stages[2], ShouldHaveLength, 2)
+		So(stages[3][0], ShouldEqual, ops[5])
+
+		So(batchStages(nil), ShouldBeEmpty)
+	})
+}
@@ -0,0 +1,405 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/pydio/cells-sdk-go/v4/models"
+
+	"github.com/pydio/cells-client/v4/rest"
+)
+
+var (
+	cellsDescription string
+	cellsMembers     []string
+	cellsRoots       []string
+	cellsFormat      string
+	cellsRmForce     bool
+)
+
+var cellsCmd = &cobra.Command{
+	Use:   "cells",
+	Short: "Manage Cells (shared collaborative rooms)",
+	Long: `
+DESCRIPTION
+
+  A Cell is a collaborative space that is shared between some users and/or groups.
+  It can be created empty or with one or more existing folders or files as roots.
+  See the help of respective sub-commands for further details.
+
+MEMBERS
+
+  Members are defined with the following syntax: <type>:<identifier>:<rights>, where:
+   - type is either 'user' or 'group'
+   - identifier is the login of a user, or the label or the full path (e.g. /org/team) of a group
+   - rights is one of r (read only), w (write only) or rw (read and write)
+`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cm *cobra.Command, args []string) {
+		_ = cm.Usage()
+	},
+}
+
+var cellsCreate = &cobra.Command{
+	Use:   "create",
+	Short: "Create a new Cell",
+	Long: `
+DESCRIPTION
+
+  Create a new Cell with the given label. The current user is always added as a member with read and write rights.
+  If no root is defined, the server creates an empty folder for the Cell.
+
+EXAMPLES
+
+  # Create an empty Cell shared with alice and the ops group
+  $ ` + os.Args[0] + ` cells create "Project X" --member user:alice:rw --member group:ops:r
+
+  # Share an existing folder
+  $ ` + os.Args[0] + ` cells create "Project Y" --member user:bob:rw --root cells://common-files/projects/y
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		cell := &models.RestCell{
+			Label:                   args[0],
+			Description:             cellsDescription,
+			ACLs:                    map[string]models.RestCellAcl{},
+			PoliciesContextEditable: true,
+		}
+
+		for _, r := range cellsRoots {
+			p := trimRemotePrefix(r)
+			node, exists := sdkClient.StatNode(ctx, p)
+			if !exists {
+				rest.Log.Fatalf("no node found at %s, cannot use it as root of the Cell", p)
+			}
+			cell.RootNodes = append(cell.RootNodes, &models.TreeNode{UUID: node.UUID})
+		}
+
+		if err := addCellMembers(ctx, cell, cellsMembers); err != nil {
+			rest.Log.Fatal(err)
+		}
+		if me := sdkClient.GetConfig().User; me != "" {
+			if u, err := sdkClient.FindUser(ctx, me); err == nil {
+				if _, ok := cell.ACLs[u.UUID]; !ok {
+					actions, _ := rest.CellAclActions("rw")
+					cell.ACLs[u.UUID] = models.RestCellAcl{RoleID: u.UUID, IsUserRole: true, User: u, Actions: actions}
+				}
+			}
+		}
+
+		created, err := sdkClient.PutCell(ctx, cell, len(cell.RootNodes) == 0)
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		fmt.Printf("Cell %s has been created with UUID %s\n", created.Label, created.UUID)
+	},
+}
+
+var cellsLs = &cobra.Command{
+	Use:   "ls",
+	Short: "List the Cells of the current user",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cells, err := sdkClient.ListCells(cmd.Context())
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		switch cellsFormat {
+		case "json":
+			data, _ := json.MarshalIndent(cells, "", "  ")
+			fmt.Printf("%s\n", data)
+		case "table":
+			if len(cells) == 0 {
+				fmt.Println("No cell found.")
+				return
+			}
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"UUID", "Label", "Description", "Members"})
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetAutoWrapText(false)
+			for _, c := range cells {
+				table.Append([]string{c.UUID, c.Label, c.Description, fmt.Sprintf("%d", len(c.ACLs))})
+			}
+			table.Render()
+		default:
+			cmd.Println("invalid output format, it must be either json or table")
+		}
+	},
+}
+
+var cellsRm = &cobra.Command{
+	Use:   "rm",
+	Short: "Remove a Cell",
+	Long: `
+DESCRIPTION
+
+  Remove a Cell, designated by its UUID or by its label.
+  Members lose their access, but the content of the Cell root folders is not deleted.
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		cell, err := resolveCell(ctx, args[0])
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		if !cellsRmForce {
+			fmt.Printf("About to remove Cell %s (%s) that has %d members\n", cell.Label, cell.UUID, len(cell.ACLs))
+			p := promptui.Select{Label: "Are you sure", Items: []string{"No", "Yes"}}
+			if _, resp, e := p.Run(); resp != "Yes" || e != nil {
+				cmd.Println(promptui.IconBad, "Aborted by user")
+				return
+			}
+		}
+		if err = sdkClient.DeleteCell(ctx, cell.UUID); err != nil {
+			rest.Log.Fatal(err)
+		}
+		fmt.Printf("Cell %s has been removed\n", cell.Label)
+	},
+}
+
+var cellsMembersCmd = &cobra.Command{
+	Use:   "members",
+	Short: "Manage the members of a Cell",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cm *cobra.Command, args []string) {
+		_ = cm.Usage()
+	},
+}
+
+var cellsMembersLs = &cobra.Command{
+	Use:   "ls",
+	Short: "List the members of a Cell",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cell, err := resolveCell(cmd.Context(), args[0])
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		var lines [][]string
+		for _, acl := range cell.ACLs {
+			kind, name := cellMemberLabel(acl)
+			lines = append(lines, []string{kind, name, rest.CellAclRights(acl.Actions)})
+		}
+		sort.Slice(lines, func(i, j int) bool { return lines[i][0]+lines[i][1] < lines[j][0]+lines[j][1] })
+
+		fmt.Printf("Cell %s has %d members:\n", cell.Label, len(lines))
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Type", "Name", "Rights"})
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+		table.AppendBulk(lines)
+		table.Render()
+	},
+}
+
+var cellsMembersAdd = &cobra.Command{
+	Use:   "add",
+	Short: "Add members to a Cell or update their rights",
+	Long: `
+DESCRIPTION
+
+  Add one or more members to an existing Cell. If a member already exists, its rights are updated.
+
+EXAMPLE
+
+  $ ` + os.Args[0] + ` cells members add "Project X" user:carol:rw group:/partners/acme:r
+`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		cell, err := resolveCell(ctx, args[0])
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		if cell.ACLs == nil {
+			cell.ACLs = map[string]models.RestCellAcl{}
+		}
+		if err = addCellMembers(ctx, cell, args[1:]); err != nil {
+			rest.Log.Fatal(err)
+		}
+		if _, err = sdkClient.PutCell(ctx, cell, false); err != nil {
+			rest.Log.Fatal(err)
+		}
+		fmt.Printf("Cell %s now has %d members\n", cell.Label, len(cell.ACLs))
+	},
+}
+
+var cellsMembersRm = &cobra.Command{
+	Use:   "rm",
+	Short: "Remove members from a Cell",
+	Long: `
+DESCRIPTION
+
+  Remove one or more members from an existing Cell. Members are defined with <type>:<identifier>, the rights are ignored.
+
+EXAMPLE
+
+  $ ` + os.Args[0] + ` cells members rm "Project X" user:carol group:ops
+`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		cell, err := resolveCell(ctx, args[0])
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		for _, m := range args[1:] {
+			kind, identifier, _, err := parseCellMember(m, false)
+			if err != nil {
+				rest.Log.Fatal(err)
+			}
+			roleId, err := memberRoleId(ctx, kind, identifier)
+			if err != nil {
+				rest.Log.Fatal(err)
+			}
+			if _, ok := cell.ACLs[roleId]; !ok {
+				rest.Log.Fatalf("%s is not a member of Cell %s", m, cell.Label)
+			}
+			delete(cell.ACLs, roleId)
+		}
+		if _, err = sdkClient.PutCell(ctx, cell, false); err != nil {
+			rest.Log.Fatal(err)
+		}
+		fmt.Printf("Cell %s now has %d members\n", cell.Label, len(cell.ACLs))
+	},
+}
+
+func init() {
+	createFlags := cellsCreate.PersistentFlags()
+	createFlags.StringVar(&cellsDescription, "description", "", "Description of the Cell")
+	createFlags.StringArrayVar(&cellsMembers, "member", []string{}, "Member of the Cell, e.g. user:alice:rw, can be used multiple times")
+	createFlags.StringArrayVar(&cellsRoots, "root", []string{}, "Existing remote file or folder to share in the Cell, can be used multiple times")
+
+	cellsLs.PersistentFlags().StringVar(&cellsFormat, "format", "table", "Output format table|json")
+	cellsRm.Flags().BoolVarP(&cellsRmForce, "force", "f", false, "Do not ask for user approval")
+
+	cellsMembersCmd.AddCommand(cellsMembersLs)
+	cellsMembersCmd.AddCommand(cellsMembersAdd)
+	cellsMembersCmd.AddCommand(cellsMembersRm)
+
+	cellsCmd.AddCommand(cellsCreate)
+	cellsCmd.AddCommand(cellsLs)
+	cellsCmd.AddCommand(cellsRm)
+	cellsCmd.AddCommand(cellsMembersCmd)
+	RootCmd.AddCommand(cellsCmd)
+}
+
+// resolveCell finds a Cell of the current user by its UUID or its label.
+func resolveCell(ctx context.Context, uuidOrLabel string) (*models.RestCell, error) {
+	if uuidRegexp.MatchString(uuidOrLabel) {
+		if c, err := sdkClient.GetCell(ctx, uuidOrLabel); err == nil {
+			return c, nil
+		}
+	}
+	cells, err := sdkClient.ListCells(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var found []*models.RestCell
+	for _, c := range cells {
+		if c.Label == uuidOrLabel {
+			found = append(found, c)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("no cell found with UUID or label %s", uuidOrLabel)
+	case 1:
+		// Retrieve full info
+		return sdkClient.GetCell(ctx, found[0].UUID)
+	default:
+		return nil, fmt.Errorf("found %d cells with label %s, please use their UUID", len(found), uuidOrLabel)
+	}
+}
+
+// parseCellMember splits a member definition of the form type:identifier[:rights].
+func parseCellMember(member string, withRights bool) (kind, identifier, rights string, err error) {
+	parts := strings.Split(member, ":")
+	if withRights {
+		if len(parts) < 3 {
+			err = fmt.Errorf("invalid member %s, expected format is <user|group>:<identifier>:<r|w|rw>", member)
+			return
+		}
+		rights = parts[len(parts)-1]
+		parts = parts[:len(parts)-1]
+	} else if len(parts) < 2 {
+		err = fmt.Errorf("invalid member %s, expected format is <user|group>:<identifier>", member)
+		return
+	}
+	kind = parts[0]
+	identifier = strings.Join(parts[1:], ":")
+	if kind != "user" && kind != "group" {
+		err = fmt.Errorf("invalid member type %s, it must be either user or group", kind)
+	}
+	return
+}
+
+// memberRoleId retrieves the ID of the role that is used in the ACLs for the passed member.
+func memberRoleId(ctx context.Context, kind, identifier string) (string, error) {
+	if kind == "user" {
+		u, err := sdkClient.FindUser(ctx, identifier)
+		if err != nil {
+			return "", err
+		}
+		return u.UUID, nil
+	}
+	g, err := sdkClient.FindGroup(ctx, identifier)
+	if err != nil {
+		return "", err
+	}
+	return g.UUID, nil
+}
+
+func addCellMembers(ctx context.Context, cell *models.RestCell, members []string) error {
+	for _, m := range members {
+		kind, identifier, rights, err := parseCellMember(m, true)
+		if err != nil {
+			return err
+		}
+		actions, err := rest.CellAclActions(rights)
+		if err != nil {
+			return err
+		}
+		acl := models.RestCellAcl{Actions: actions}
+		if kind == "user" {
+			u, err := sdkClient.FindUser(ctx, identifier)
+			if err != nil {
+				return err
+			}
+			acl.RoleID = u.UUID
+			acl.IsUserRole = true
+			acl.User = u
+		} else {
+			g, err := sdkClient.FindGroup(ctx, identifier)
+			if err != nil {
+				return err
+			}
+			acl.RoleID = g.UUID
+			acl.Group = g
+		}
+		cell.ACLs[acl.RoleID] = acl
+	}
+	return nil
+}
+
+func cellMemberLabel(acl models.RestCellAcl) (string, string) {
+	switch {
+	case acl.User != nil:
+		return "User", acl.User.Login
+	case acl.Group != nil:
+		return "Group", rest.GroupFullPath(acl.Group)
+	case acl.Role != nil:
+		return "Role", acl.Role.Label
+	default:
+		return "Role", acl.RoleID
+	}
+}
@@ -1,12 +1,9 @@
 package cmd
 
 import (
-	"fmt"
 	"os"
 	"path"
-	"strings"
 
-	"github.com/manifoldco/promptui"
 	"github.com/spf13/cobra"
 
 	"github.com/pydio/cells-sdk-go/v4/models"
@@ -14,6 +11,8 @@ import (
 	"github.com/pydio/cells-client/v4/rest"
 )
 
+var cpDryRun bool
+
 var cpCmd = &cobra.Command{
 	Use:   "cp",
 	Short: "Copy files from A to B within your remote server",
@@ -23,15 +22,6 @@ DESCRIPTION
   Copy files from one location to another *within* a *single* Pydio Cells instance. 
   To copy files from the client machine to your server (and vice versa), rather see the '` + os.Args[0] + ` scp' command.
 
-WILD-CHARS
-
-  Since version 2, we only support the '*' wild char as a standalone token of the source path, that is:
-    - '` + os.Args[0] + ` cp common-files/test/* common-files/target' is legit and will copy 
-	  all files and folder found in test folder on your server to the target folder
-	- '` + os.Args[0] + ` cp common-files/test/*.jpg ...' or '` + os.Args[0] + ` cp common-files/test/ima* ...'  
-	  will *not* work as some might expect: the system looks for a single file named respectively '*.jpg' or 'ima*'
-	  and will probably fail to find it (unless if a file with this name exists on your server...)
-
 EXAMPLE
 
   # Copy file "test.txt" from workspace root inside target "folder-a":
@@ -41,27 +31,26 @@ EXAMPLE
   ` + os.Args[0] + ` cp common-files/test.txt personal-files/folder-b
 
   # Copy the full content of a folder inside another
-  ` + os.Args[0] + ` cp common-files/test/* common-files/folder-c
-`,
-	Args: cobra.MinimumNArgs(2),
+  ` + os.Args[0] + ` cp 'common-files/test/*' common-files/folder-c
+
+  # Copy all PDF files of a folder and its sub-folders, and a single file, inside another folder
+  ` + os.Args[0] + ` cp 'common-files/test/**/*.pdf' common-files/notes.txt common-files/folder-d
+` + globHelp,
+	Args:              cobra.MinimumNArgs(2),
+	ValidArgsFunction: completeRemotePaths,
 	Run: func(cmd *cobra.Command, args []string) {
-		fromPath := args[0]
-		toPath := args[1]
+		fromPaths := args[:len(args)-1]
+		toPath := args[len(args)-1]
 		targetParent := true
 
-		// Pre-process source path
-		var sourceNodes []string
-		if path.Base(fromPath) == "*" {
-			nodes, err := sdkClient.ListNodesPath(cmd.Context(), fromPath)
-			if err != nil {
-				rest.Log.Fatalf("Preparing grouped copy, could not list all nodes under %s, cause: %s", path.Dir(fromPath), err.Error())
-			}
-			sourceNodes = nodes
-		} else if strings.HasSuffix(path.Base(fromPath), "*") {
-			fmt.Println(promptui.IconWarn + " We currently only support the '*' wild char without suffix, see help for further details")
-			sourceNodes = []string{fromPath}
-		} else {
-			sourceNodes = []string{fromPath}
+		// Pre-process source paths
+		var hasGlob bool
+		for _, p := range fromPaths {
+			hasGlob = hasGlob || sdkClient.IsPattern(cmd.Context(), trimRemotePrefix(p))
+		}
+		sourceNodes := expandRemotePaths(cmd.Context(), fromPaths, true)
+		if len(sourceNodes) == 0 {
+			rest.Log.Fatalln("Nothing to copy")
 		}
 
 		// Pre-process target path
@@ -84,10 +73,19 @@ EXAMPLE
 			} else if *targetNode.Type != models.TreeNodeTypeCOLLECTION {
 				rest.Log.Fatalf("Parent target location %s exists on server but is not a folder. It cannot be used as a copy target location.", parPath)
 			}
+			if hasGlob || len(sourceNodes) > 1 {
+				rest.Log.Fatalf("Target location %s must be an existing folder when copying several nodes.", toPath)
+			}
 			// parent exists and is a folder => we assume we have been passed a full target path including target file name.
 			targetParent = false
 		}
 
+		if cpDryRun {
+			printNodesSummary(cmd.Context(), "copied to "+toPath, sourceNodes)
+			printOverwrites(cmd.Context(), sourceNodes, toPath, targetParent)
+			return
+		}
+
 		// Prepare and launch effective copy
 		params := rest.BuildParams(sourceNodes, toPath, targetParent)
 		jobID, err := sdkClient.CopyJob(cmd.Context(), params)
@@ -103,5 +101,6 @@ EXAMPLE
 }
 
 func init() {
+	cpCmd.Flags().BoolVar(&cpDryRun, "dry-run", false, "Only show a summary of the nodes that would be copied and of the existing nodes they would overwrite")
 	RootCmd.AddCommand(cpCmd)
 }
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pydio/cells-client/v4/rest"
+)
+
+var (
+	diffChecksum bool
+	diffFormat   string
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare two folders, locally or on the server",
+	Long: `
+DESCRIPTION
+
+  Recursively compare two folders and list the differences, from the first to the second one.
+  Each folder can either be on your client machine or on the server: prefix remote paths with 'cells://' (or 'cells//').
+
+  Reported differences are:
+   + added: the item only exists in the second folder
+   - removed: the item only exists in the first folder
+   ~ modified: a file exists on both sides but with a different content
+   ! type-changed: the item is a file on one side and a folder on the other side
+
+  When a whole folder is added or removed, its children are not listed.
+  Like with the synchronization, hidden items (whose name starts with a dot) and system files such as
+  Thumbs.db or desktop.ini are ignored on both sides.
+
+  By default, files are considered as modified when their size or modification time differ.
+  As the server sets the modification time when a file is uploaded, you should rather use the --checksum flag
+  to compare local and remote files: modification times are then ignored and files with the same size
+  are compared using the same block hash algorithm as the one used by the server.
+
+EXIT STATUS
+
+  Like the standard diff tool, the command exits with 0 if no difference has been found, 1 if some differences
+  have been found and 2 in case of error: this can be used to gate a CI pipeline.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` diff --checksum ./dist cells://common-files/release
+  --- ./dist
+  +++ cells://common-files/release
+  + docs/changelog.md
+  - assets/logo-old.png
+  ~ app.js (size 10230 -> 10412)
+
+  $ ` + os.Args[0] + ` diff --format json ./dist cells://common-files/release
+`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		if diffFormat != "text" && diffFormat != "json" {
+			diffFatal(fmt.Errorf("invalid output format %s, it must be either text or json", diffFormat))
+		}
+		if diffFormat == "json" { // Keep the standard output parsable
+			rest.LogToStderr(true)
+			defer rest.LogToStderr(false)
+		}
+
+		var crawlers []*rest.CrawlNode
+		for _, arg := range args {
+			isLocal := !strings.HasPrefix(arg, standardPrefix) && !strings.HasPrefix(arg, completionPrefix)
+			p := strings.Trim(trimRemotePrefix(arg), "/")
+			if isLocal {
+				var err error
+				if p, err = filepath.Abs(arg); err != nil {
+					diffFatal(fmt.Errorf("%s is not a valid path: %s", arg, err.Error()))
+				}
+			}
+			c, err := rest.NewCrawler(ctx, sdkClient, p, isLocal)
+			if err != nil {
+				diffFatal(err)
+			}
+			if !c.IsDir {
+				diffFatal(fmt.Errorf("%s is not a folder, only folders can be compared", arg))
+			}
+			crawlers = append(crawlers, c)
+		}
+
+		entries, err := rest.Diff(ctx, crawlers[0], crawlers[1], diffChecksum)
+		if err != nil {
+			diffFatal(err)
+		}
+
+		switch diffFormat {
+		case "json":
+			printDiffJson(os.Stdout, args[0], args[1], entries)
+		default:
+			if len(entries) > 0 {
+				fmt.Printf("--- %s\n+++ %s\n", args[0], args[1])
+			}
+			for _, e := range entries {
+				name := e.Path
+				if e.IsDir {
+					name += "/"
+				}
+				switch e.Type {
+				case rest.DiffAdded:
+					fmt.Printf("+ %s\n", name)
+				case rest.DiffRemoved:
+					fmt.Printf("- %s\n", name)
+				case rest.DiffModified:
+					fmt.Printf("~ %s (%s)\n", name, e.Reason)
+				case rest.DiffTypeChanged:
+					fmt.Printf("! %s (%s)\n", name, e.Reason)
+				}
+			}
+		}
+
+		if len(entries) > 0 {
+			exit(1)
+		}
+	},
+}
+
+func init() {
+	flags := diffCmd.PersistentFlags()
+	flags.BoolVarP(&diffChecksum, "checksum", "c", false, "Compare the content of files with the same size rather than their modification time")
+	flags.StringVar(&diffFormat, "format", "text", "Output format text|json")
+	RootCmd.AddCommand(diffCmd)
+}
+
+// printDiffJson writes the differences found between from and to as a single JSON document.
+func printDiffJson(w io.Writer, from, to string, entries []*rest.DiffEntry) {
+	if entries == nil {
+		entries = []*rest.DiffEntry{}
+	}
+	data, _ := json.MarshalIndent(map[string]interface{}{
+		"from":    from,
+		"to":      to,
+		"entries": entries,
+	}, "", "  ")
+	_, _ = fmt.Fprintf(w, "%s\n", data)
+}
+
+// diffFatal prints the error and exits with status 2, as 1 means that differences have been found.
+func diffFatal(err error) {
+	_, _ = fmt.Fprintln(os.Stderr, "Error:", err.Error())
+	exit(2)
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+
+	"github.com/pydio/cells-client/v4/rest"
+
+	// Silently import convey to ease implementation
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDiffJsonOutput(t *testing.T) {
+	Convey("Test that the JSON output of diff is not mixed with logs", t, func() {
+		r, w, err := os.Pipe()
+		So(err, ShouldBeNil)
+		stdout := os.Stdout
+		os.Stdout = w
+		defer func() { os.Stdout = stdout }()
+
+		rest.SetLogger(zapcore.InfoLevel)
+		rest.LogToStderr(true)
+		defer rest.LogToStderr(false)
+
+		// Like the walkers during the diff
+		rest.Log.Infoln("Walking local tree to prepare upload")
+		printDiffJson(os.Stdout, "./dist", "cells://common-files/release", []*rest.DiffEntry{
+			{Path: "docs/changelog.md", Type: rest.DiffAdded},
+		})
+		So(w.Close(), ShouldBeNil)
+
+		data, err := io.ReadAll(r)
+		So(err, ShouldBeNil)
+		var result struct {
+			From    string            `json:"from"`
+			Entries []*rest.DiffEntry `json:"entries"`
+		}
+		So(json.Unmarshal(data, &result), ShouldBeNil)
+		So(result.From, ShouldEqual, "./dist")
+		So(result.Entries, ShouldHaveLength, 1)
+	})
+}
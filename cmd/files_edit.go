@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+
+	"github.com/pydio/cells-sdk-go/v4/models"
+
+	"github.com/pydio/cells-client/v4/rest"
+)
+
+const (
+	editChoiceOverwrite = "Overwrite the remote file with my version"
+	editChoiceCopy      = "Upload my version as a copy next to the remote file"
+	editChoiceDiscard   = "Discard my changes"
+)
+
+var editCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit a remote file with your local editor",
+	Long: `
+DESCRIPTION
+
+  Download a remote file to a temporary location, open it with your editor and upload it back when you are done.
+
+  The editor is defined by the VISUAL or EDITOR environment variables, it defaults to vi (notepad on Windows).
+  Nothing is uploaded if you have not modified the file.
+
+  If the remote file has been modified by someone else while you were editing it, you can choose to:
+   - overwrite the remote file with your version,
+   - upload your version as a copy, next to the remote file,
+   - discard your changes.
+
+  The temporary file is always removed, even if the command is interrupted.
+
+EXAMPLE
+
+  $ ` + os.Args[0] + ` edit cells://common-files/config/app.yaml
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// Errors are only reported once the temporary folder has been removed
+		if err := editRemoteFile(cmd.Context(), strings.Trim(trimRemotePrefix(args[0]), "/")); err != nil {
+			rest.Log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(editCmd)
+}
+
+// editRemoteFile downloads the remote file to a temporary folder, opens it with the editor of the current user
+// and uploads it back if it has been modified. The temporary folder is always removed before returning.
+func editRemoteFile(ctx context.Context, p string) error {
+	node, exists := sdkClient.StatNode(ctx, p)
+	if !exists {
+		return fmt.Errorf("no file found at %s", p)
+	}
+	if node.Type != nil && *node.Type == models.TreeNodeTypeCOLLECTION {
+		return fmt.Errorf("%s is a folder, only files can be edited", p)
+	}
+	if sdkClient.IsLockedByOther(node) {
+		return fmt.Errorf("%s is locked by %s, it cannot be modified", p, rest.LockOwner(node))
+	}
+
+	tmpDir, err := os.MkdirTemp("", "cec-edit-")
+	if err != nil {
+		return fmt.Errorf("could not create temporary folder: %s", err.Error())
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// Interruptions cancel the context, so that the command stops and cleans up. While the editor runs,
+	// it is in charge of Ctrl+C (e.g. to cancel a command in vi) and a termination is only handled once
+	// it has returned: signals are still caught rather than ignored, so that the editor does not inherit
+	// an ignored SIGINT.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var editing, terminated atomic.Bool
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+	go func() {
+		for {
+			select {
+			case sig := <-sigs:
+				if !editing.Load() {
+					cancel()
+					return
+				}
+				if sig != os.Interrupt {
+					terminated.Store(true)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	errInterrupted := fmt.Errorf("edition has been interrupted, nothing has been uploaded")
+
+	// Keep the file name, so that editors can adapt their behaviour to the extension
+	localPath := filepath.Join(tmpDir, path.Base(p))
+	original, err := downloadForEdit(ctx, p, localPath)
+	if ctx.Err() != nil {
+		return errInterrupted
+	} else if err != nil {
+		return err
+	}
+
+	editing.Store(true)
+	err = runEditor(localPath)
+	editing.Store(false)
+	if terminated.Load() || ctx.Err() != nil {
+		return errInterrupted
+	} else if err != nil {
+		return fmt.Errorf("editor exited with an error, nothing has been uploaded: %s", err.Error())
+	}
+
+	edited, err := os.ReadFile(localPath)
+	if err != nil {
+		return err
+	}
+	if sha256.Sum256(edited) == original {
+		fmt.Println("File has not been modified, nothing to upload")
+		return nil
+	}
+
+	target := p
+	sdkClient.InvalidateMeta(p) // Always get the latest version of the node
+	if current, ok := sdkClient.StatNode(ctx, p); ok && remoteHasChanged(node, current) {
+		fmt.Printf("%s has been modified on the server since you opened it.\n", p)
+		s := promptui.Select{
+			Label: "What do you want to do",
+			Items: []string{editChoiceOverwrite, editChoiceCopy, editChoiceDiscard},
+		}
+		_, choice, e := s.Run()
+		if e != nil {
+			choice = editChoiceDiscard
+		}
+		switch choice {
+		case editChoiceOverwrite:
+			if sdkClient.IsLockedByOther(current) {
+				return fmt.Errorf("%s has been locked by %s in the meantime, it cannot be modified", p, rest.LockOwner(current))
+			}
+		case editChoiceCopy:
+			target = conflictCopyPath(p)
+		default:
+			fmt.Println(promptui.IconBad, "Changes have been discarded")
+			return nil
+		}
+	}
+
+	if _, err = sdkClient.PutFile(ctx, target, bytes.NewReader(edited), false); err != nil {
+		if ctx.Err() != nil {
+			return errInterrupted
+		}
+		return err
+	}
+	fmt.Printf("%s has been uploaded\n", target)
+	return nil
+}
+
+// downloadForEdit writes the remote file to the local path and returns the checksum of its content.
+func downloadForEdit(ctx context.Context, remotePath, localPath string) ([sha256.Size]byte, error) {
+	var sum [sha256.Size]byte
+	reader, _, err := sdkClient.GetFile(ctx, remotePath)
+	if err != nil {
+		return sum, fmt.Errorf("could not download %s: %s", remotePath, err.Error())
+	}
+	if closer, ok := reader.(io.Closer); ok {
+		defer closer.Close()
+	}
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return sum, fmt.Errorf("could not download %s: %s", remotePath, err.Error())
+	}
+	if err = os.WriteFile(localPath, content, 0600); err != nil {
+		return sum, err
+	}
+	return sha256.Sum256(content), nil
+}
+
+// runEditor opens the passed file with the editor of the current user and waits until it is closed.
+func runEditor(filePath string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+	// The editor variable might also contain some arguments, e.g. "code --wait"
+	parts := strings.Fields(editor)
+	c := exec.Command(parts[0], append(parts[1:], filePath)...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	return c.Run()
+}
+
+// remoteHasChanged compares the internal hash and the modification time of two states of the same node.
+func remoteHasChanged(before, after *models.TreeNode) bool {
+	if h1, h2 := fromMetaStore(before, "x-cells-hash"), fromMetaStore(after, "x-cells-hash"); h1 != "" && h2 != "" {
+		return h1 != h2
+	}
+	return before.MTime != after.MTime || before.Size != after.Size
+}
+
+// conflictCopyPath computes a new path next to the passed one, e.g. folder/app-conflict-20240131-153000.yaml.
+func conflictCopyPath(remotePath string) string {
+	ext := path.Ext(remotePath)
+	base := strings.TrimSuffix(remotePath, ext)
+	return fmt.Sprintf("%s-conflict-%s%s", base, time.Now().Format("20060102-150405"), ext)
+}
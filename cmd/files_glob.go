@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/pydio/cells-client/v4/rest"
+)
+
+const globHelp = `
+WILDCARDS
+
+  Remote paths support the following glob patterns, that are expanded on the server side:
+   - '*' matches any sequence of characters in a file or folder name
+   - '?' matches a single character
+   - '[abc]' or '[a-z]' matches one character in the class
+   - '{a,b}' matches either a or b
+   - '**' matches any number of nested folders, e.g. 'common-files/**/*.jpg'
+
+  Do not forget to quote the patterns so that they are not expanded by your local shell.
+  A path that exists as is is never expanded, so that names like 'report [v1].pdf' can be used directly.
+  Wildcards never match the 'recycle_bin' folder: name it explicitly if you really want to target its content.
+  Use the --dry-run flag to only show a summary of the nodes that match the patterns.
+`
+
+// expandRemotePaths resolves the glob patterns among the passed remote paths, the other paths are only checked for existence.
+// A path that contains glob characters but exists as is, e.g. "report [v1].pdf", is not considered as a pattern.
+// Missing paths and patterns without any match are logged and ignored. When pruneNested is true,
+// paths that have an ancestor in the result are removed, so that the same node is not processed twice.
+func expandRemotePaths(ctx context.Context, args []string, pruneNested bool) []string {
+	known := make(map[string]bool)
+	var result []string
+	for _, arg := range args {
+		p := trimRemotePrefix(arg)
+		var matches []string
+		if sdkClient.IsPattern(ctx, p) {
+			var err error
+			matches, err = sdkClient.ExpandGlob(ctx, p)
+			if err != nil {
+				rest.Log.Fatalf("could not expand %s: %s", arg, err.Error())
+			}
+			if len(matches) == 0 {
+				rest.Log.Warnf("No node matches %s", arg)
+			}
+		} else if _, exists := sdkClient.StatNode(ctx, p); exists {
+			matches = []string{p}
+		} else {
+			rest.Log.Warnf("Node %s not found: it is ignored", arg)
+		}
+		for _, m := range matches {
+			if !known[m] {
+				known[m] = true
+				result = append(result, m)
+			}
+		}
+	}
+	if pruneNested {
+		result = rest.RemoveNested(result)
+	}
+	return result
+}
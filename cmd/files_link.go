@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/pydio/cells-sdk-go/v4/models"
+
+	"github.com/pydio/cells-client/v4/rest"
+)
+
+var linkPassword string
+
+var linkCmd = &cobra.Command{
+	Use:   "link",
+	Short: "Anonymously access the content of public links",
+	Long: `
+DESCRIPTION
+
+  Download from and upload to the public links of a Cells server, without having an account on this server.
+  These commands do not require any configuration: they only rely on the public URL of the link.
+
+  If the link is protected by a password, pass it with the --link-password flag or enter it when prompted.
+`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cm *cobra.Command, args []string) {
+		_ = cm.Usage()
+	},
+}
+
+var linkLs = &cobra.Command{
+	Use:   "ls",
+	Short: "List the content of a public link",
+	Long: `
+DESCRIPTION
+
+  List the files and folders that are shared via a public link.
+
+EXAMPLE
+
+  $ ` + os.Args[0] + ` link ls https://files.example.com/public/479cc5dbdf8b
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		client, root := connectToPublicLink(ctx, args[0])
+		defer client.Teardown()
+
+		nodes, err := publicLinkContent(ctx, client, root)
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Type", "Name", "Size", "Modified"})
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+		for _, n := range nodes {
+			t := "File"
+			if n.Type != nil && *n.Type == models.TreeNodeTypeCOLLECTION {
+				t = "Folder"
+			}
+			table.Append([]string{t, path.Base(n.Path), sizeToHuman(n.Size), stampToDate(n.MTime)})
+		}
+		table.Render()
+	},
+}
+
+var linkGet = &cobra.Command{
+	Use:   "get",
+	Short: "Download the content of a public link",
+	Long: `
+DESCRIPTION
+
+  Download the files and folders that are shared via a public link to a local folder, recursively.
+  If no destination is given, the content is downloaded in the current folder.
+  As with the scp command, the download aborts if an item with the same name already exists locally,
+  unless the force flag is set.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` link get https://files.example.com/public/479cc5dbdf8b ./inbox
+  $ ` + os.Args[0] + ` link get --link-password secret --force https://files.example.com/public/delivery-acme
+`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		dest := "."
+		if len(args) > 1 {
+			dest = args[1]
+		}
+		targetPath, err := filepath.Abs(dest)
+		if err != nil {
+			rest.Log.Fatalf("%s is not a valid destination: %s", dest, err)
+		}
+
+		client, root := connectToPublicLink(ctx, args[0])
+		defer client.Teardown()
+
+		nodes, err := publicLinkContent(ctx, client, root)
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		if len(nodes) == 0 {
+			rest.Log.Infoln("Nothing to download, the link is empty")
+			return
+		}
+		if _, e := os.Stat(targetPath); e != nil && os.IsNotExist(e) {
+			if e = os.Mkdir(targetPath, 0755); e != nil {
+				rest.Log.Fatalf("could not create %s: %s", targetPath, e)
+			}
+		}
+
+		for _, n := range nodes {
+			srcPath := strings.Trim(n.Path, "/")
+			needMerge, e := preProcessLocalTarget(path.Base(srcPath), targetPath, scpForce)
+			if e != nil {
+				rest.Log.Fatalln(e)
+			}
+			rest.Log.Infof("Downloading %s to %s", path.Base(srcPath), targetPath)
+			transferTree(ctx, client, srcPath, targetPath, false, needMerge)
+		}
+	},
+}
+
+var linkPut = &cobra.Command{
+	Use:   "put",
+	Short: "Upload files to a public link",
+	Long: `
+DESCRIPTION
+
+  Upload local files and folders to a public link. The link must share a folder and allow uploads.
+  As with the scp command, the upload aborts if an item with the same name already exists in the link,
+  unless the force flag is set.
+
+EXAMPLE
+
+  $ ` + os.Args[0] + ` link put https://files.example.com/public/inbox-acme ./report.pdf ./attachments
+`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		client, root := connectToPublicLink(ctx, args[0])
+		defer client.Teardown()
+
+		rootNode, ok := client.StatNode(ctx, root)
+		if !ok {
+			rest.Log.Fatalf("could not retrieve the content of the link")
+		}
+		if rootNode.Type == nil || *rootNode.Type != models.TreeNodeTypeCOLLECTION {
+			rest.Log.Fatalf("this link shares a single file, uploads are not possible")
+		}
+
+		for _, local := range args[1:] {
+			srcPath, err := filepath.Abs(local)
+			if err != nil {
+				rest.Log.Fatalf("%s is not a valid source: %s", local, err)
+			}
+			if _, err = os.Stat(srcPath); err != nil {
+				rest.Log.Fatalln(err)
+			}
+			needMerge, err := preProcessRemoteTarget(ctx, client, filepath.Base(srcPath), root, scpForce)
+			if err != nil {
+				rest.Log.Fatalln(err)
+			}
+			rest.Log.Infof("Uploading %s", srcPath)
+			transferTree(ctx, client, srcPath, root, true, needMerge)
+		}
+	},
+}
+
+func init() {
+	for _, c := range []*cobra.Command{linkGet, linkPut} {
+		flags := c.Flags()
+		flags.BoolVarP(&scpForce, "force", "f", false, "*DANGER* turns overwrite mode on: existing items with the same name are merged or replaced")
+		flags.BoolVarP(&scpNoProgress, "no-progress", "n", false, "Do not show progress bar")
+		flags.BoolVarP(&scpQuiet, "quiet", "q", false, "Reduce refresh frequency of the progress bars, e.g when running cec in a bash script")
+	}
+
+	linkCmd.PersistentFlags().StringVar(&linkPassword, "link-password", "", "Password of the public link, if it is protected")
+	linkCmd.AddCommand(linkLs)
+	linkCmd.AddCommand(linkGet)
+	linkCmd.AddCommand(linkPut)
+	RootCmd.AddCommand(linkCmd)
+}
+
+// connectToPublicLink logs in as the hidden user of the public link and returns a dedicated client
+// with the path of the shared content. It exits on error.
+func connectToPublicLink(ctx context.Context, publicURL string) (*rest.SdkClient, string) {
+	link, err := rest.ResolvePublicLink(ctx, publicURL, skipVerify)
+	if err != nil {
+		rest.Log.Fatal(err)
+	}
+
+	pwd := linkPassword
+	if link.PasswordRequired && pwd == "" {
+		p := promptui.Prompt{Label: "This link is protected, please enter its password", Mask: '*'}
+		if pwd, err = p.Run(); err != nil {
+			rest.Log.Fatalf("operation aborted by user")
+		}
+	}
+
+	client, err := rest.NewPublicLinkClient(ctx, link, pwd, skipVerify)
+	if err != nil {
+		rest.Log.Fatal(err)
+	}
+	root, err := client.PublicLinkRoot(ctx)
+	if err != nil {
+		rest.Log.Fatal(err)
+	}
+	return client, root
+}
+
+// publicLinkContent returns the shared node itself when the link is on a single file, or its children otherwise.
+func publicLinkContent(ctx context.Context, client *rest.SdkClient, root string) ([]*models.TreeNode, error) {
+	rootNode, ok := client.StatNode(ctx, root)
+	if !ok {
+		return nil, fmt.Errorf("could not retrieve the content of the link")
+	}
+	if rootNode.Type == nil || *rootNode.Type != models.TreeNodeTypeCOLLECTION {
+		return []*models.TreeNode{rootNode}, nil
+	}
+	return client.GetAllBulkMeta(ctx, root+"/*")
+}
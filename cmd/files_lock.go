@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pydio/cells-sdk-go/v4/models"
+
+	"github.com/pydio/cells-client/v4/rest"
+)
+
+var unlockForce bool
+
+var lockCmd = &cobra.Command{
+	Use:   "lock",
+	Short: "Lock files to prevent other users from modifying them",
+	Long: `
+DESCRIPTION
+
+  Lock one or more files on the server: until they are unlocked, other users cannot modify them.
+  Use 'ls -d' to see who holds a lock on a file.
+
+EXAMPLE
+
+  $ ` + os.Args[0] + ` lock cells://common-files/cad/engine.dwg
+  common-files/cad/engine.dwg is now locked
+`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		for _, arg := range args {
+			node := statLockableFile(cmd, arg)
+			if owner := rest.LockOwner(node); owner != "" {
+				if owner == sdkClient.GetConfig().User {
+					fmt.Printf("%s is already locked by you\n", node.Path)
+					continue
+				}
+				rest.Log.Fatalf("%s is already locked by %s", node.Path, owner)
+			}
+			if err := sdkClient.LockNode(ctx, node); err != nil {
+				rest.Log.Fatal(err)
+			}
+			fmt.Printf("%s is now locked\n", node.Path)
+		}
+	},
+}
+
+var unlockCmd = &cobra.Command{
+	Use:   "unlock",
+	Short: "Unlock files",
+	Long: `
+DESCRIPTION
+
+  Release the lock on one or more files.
+  Releasing a lock that is held by another user requires the force flag, and sufficient permissions on the server.
+
+EXAMPLE
+
+  $ ` + os.Args[0] + ` unlock cells://common-files/cad/engine.dwg
+  common-files/cad/engine.dwg is now unlocked
+`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		for _, arg := range args {
+			node := statLockableFile(cmd, arg)
+			owner := rest.LockOwner(node)
+			if owner == "" {
+				fmt.Printf("%s is not locked\n", node.Path)
+				continue
+			}
+			if sdkClient.IsLockedByOther(node) && !unlockForce {
+				rest.Log.Fatalf("%s is locked by %s, use the --force flag to release the lock anyway", node.Path, owner)
+			}
+			if err := sdkClient.UnlockNode(ctx, node); err != nil {
+				rest.Log.Fatal(err)
+			}
+			fmt.Printf("%s is now unlocked\n", node.Path)
+		}
+	},
+}
+
+func init() {
+	unlockCmd.Flags().BoolVarP(&unlockForce, "force", "f", false, "Release locks that are held by other users")
+
+	RootCmd.AddCommand(lockCmd)
+	RootCmd.AddCommand(unlockCmd)
+}
+
+// statLockableFile retrieves the node at the passed remote path and exits if it is not a file.
+func statLockableFile(cmd *cobra.Command, remotePath string) *models.TreeNode {
+	p := strings.Trim(trimRemotePrefix(remotePath), "/")
+	node, exists := sdkClient.StatNode(cmd.Context(), p)
+	if !exists {
+		rest.Log.Fatalf("no file found at %s", p)
+	}
+	if node.Type != nil && *node.Type == models.TreeNodeTypeCOLLECTION {
+		rest.Log.Fatalf("%s is a folder, only files can be locked", p)
+	}
+	return node
+}
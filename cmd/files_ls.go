@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"log"
 	"os"
 	"path"
 	"strconv"
@@ -16,6 +15,8 @@ import (
 
 	"github.com/pydio/cells-sdk-go/v4/client/meta_service"
 	"github.com/pydio/cells-sdk-go/v4/models"
+
+	"github.com/pydio/cells-client/v4/rest"
 )
 
 var lsCmdExample = ` 1/ Listing the content of a folder
@@ -38,13 +39,13 @@ var lsCmdExample = ` 1/ Listing the content of a folder
   
   $ ` + os.Args[0] + ` ls -d common-files/Test/Garden.jpeg
   Found 1 node at common-files/Test/Garden.jpeg:
-  +------+--------------------------------------+-------------+---------+----------------+----------------------------------+
-  | TYPE |                 UUID                 |    NAME     |  SIZE   |    MODIFIED    |          INTERNAL HASH           |
-  +------+--------------------------------------+-------------+---------+----------------+----------------------------------+
-  | File | e50c9d8a-a84c-4b32-908a-408927657810 | Garden.jpeg | 442 KiB | 52 minutes ago | a6676657eb373c7f3e3c4e01be817fac |
-  +------+--------------------------------------+-------------+---------+----------------+----------------------------------+
+  +------+--------------------------------------+-------------+---------+----------------+----------------------------------+-----------+
+  | TYPE |                 UUID                 |    NAME     |  SIZE   |    MODIFIED    |          INTERNAL HASH           | LOCKED BY |
+  +------+--------------------------------------+-------------+---------+----------------+----------------------------------+-----------+
+  | File | e50c9d8a-a84c-4b32-908a-408927657810 | Garden.jpeg | 442 KiB | 52 minutes ago | a6676657eb373c7f3e3c4e01be817fac | alice     |
+  +------+--------------------------------------+-------------+---------+----------------+----------------------------------+-----------+
  
-  Will show the metadata for this node (uuid, size, modification date and internal hash)
+  Will show the metadata for this node (uuid, size, modification date, internal hash and owner of the lock if any)
   
  3/ Only listing files and folders, one per line.
   
@@ -92,6 +93,7 @@ const (
 	metaSizeBytes = "SizeBytes"
 	metaTimestamp = "TimeStamp"
 	medaDate      = "Date"
+	metaLockedBy  = "LockedBy"
 )
 
 // Store options
@@ -127,6 +129,7 @@ SYNTAX
    - Type: File, Folder or Workspace
    - Uuid: the unique ID of the corresponding node in the Cells Server
    - Hash: in case of a file, the internal hash computed by the server 
+   - LockedBy: in case of a file, the login of the user that holds a lock on it, if any
    - Name: name of the item
    - Path: the path from the root of the server
    - HumanSize: a human-friendly formatted size
@@ -140,6 +143,7 @@ EXAMPLES
 
 ` + lsCmdExample + `
 `,
+	ValidArgsFunction: completeRemotePaths,
 	Run: func(cmd *cobra.Command, args []string) {
 
 		// Retrieve requested display type and check it is valid
@@ -185,7 +189,7 @@ EXAMPLES
 			} else {
 				cmd.Printf("Could not list files at %s, cause: %s\n", p, err.Error())
 			}
-			os.Exit(1)
+			exit(1)
 		}
 		if len(result.Payload.Nodes) == 0 {
 			// Nothing to list: should never happen, we always have at least the current path.
@@ -263,7 +267,7 @@ EXAMPLES
 						fromMetaStore(node, "ws_permissions"),
 					})
 				} else {
-					table.Append([]string{t, node.UUID, currName, sizeToHuman(node.Size), stampToDate(node.MTime), iHash})
+					table.Append([]string{t, node.UUID, currName, sizeToHuman(node.Size), stampToDate(node.MTime), iHash, rest.LockOwner(node)})
 				}
 			case raw:
 				if node.Type != nil && *node.Type == models.TreeNodeTypeCOLLECTION {
@@ -284,10 +288,11 @@ EXAMPLES
 					metaTimestamp: node.MTime,
 					medaDate:      stampToDate(node.MTime),
 					metaHash:      iHash,
+					metaLockedBy:  rest.LockOwner(node),
 				}
 
 				if err = parsedTemplate.Execute(os.Stdout, values); err != nil {
-					log.Fatalln("could not execute template", err)
+					rest.Log.Fatalln("could not execute template", err)
 				}
 				fmt.Println("") // explicit carriage return
 
@@ -309,7 +314,7 @@ EXAMPLES
 			if wsLevel {
 				table.SetHeader([]string{"Type", "Uuid", "Name", "Label", "Description", "Permissions"})
 			} else {
-				table.SetHeader([]string{"Type", "Uuid", "Name", "Size", "Modified", "Internal Hash"})
+				table.SetHeader([]string{"Type", "Uuid", "Name", "Size", "Modified", "Internal Hash", "Locked By"})
 			}
 			table.Render()
 		case raw, goTemplate: // Nothing to add: we just want the raw values that we already displayed while looping
@@ -345,12 +350,12 @@ func sanityCheck() string {
 		// for go templates, also validate the passed template
 		tmpl, err := template.New("lsNode").Parse(lsFormat)
 		if err != nil {
-			log.Fatalln("failed to parse template:", err)
+			rest.Log.Fatalln("failed to parse template:", err)
 		}
 		parsedTemplate = tmpl
 	}
 	if nb > 1 {
-		log.Fatal("Please use at most *one* modifier flag")
+		rest.Log.Fatal("Please use at most *one* modifier flag")
 	}
 	return displayType
 }
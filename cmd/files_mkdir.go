@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"log"
 	"os"
 	"path"
 	"strings"
@@ -44,12 +43,12 @@ EXAMPLES
 	Run: func(cmd *cobra.Command, args []string) {
 
 		if len(args) < 1 {
-			log.Fatal(fmt.Errorf("please provide the target path"))
+			rest.Log.Fatal(fmt.Errorf("please provide the target path"))
 		}
 		dir := args[0]
 		parts := strings.Split(dir, "/")
 		if len(parts) < 2 {
-			log.Fatal("Please provide at least a workspace segment in the path")
+			rest.Log.Fatal("Please provide at least a workspace segment in the path")
 		}
 
 		// Connect to the Pydio API via the sdkConfig
@@ -60,15 +59,34 @@ EXAMPLES
 		var crt = parts[0]
 		ctx := cmd.Context()
 
+		exists := func(p string) bool {
+			_, e := apiClient.TreeService.HeadNode(&tree_service.HeadNodeParams{Node: p, Context: ctx})
+			return e == nil
+		}
+		if sdkClient.MetaCacheEnabled() {
+			// Stat the workspace and all ancestors at once
+			ancestors := []string{crt}
+			for i := 1; i < len(parts)-1; i++ {
+				ancestors = append(ancestors, path.Join(ancestors[i-1], parts[i]))
+			}
+			existing, err := sdkClient.StatNodes(ctx, ancestors)
+			if err != nil {
+				rest.Log.Fatalf("could not check existing folders: %s", err.Error())
+			}
+			exists = func(p string) bool {
+				_, ok := existing[p]
+				return ok
+			}
+		}
+
 		// Checking existence of parent workspace
-		if _, e := apiClient.TreeService.HeadNode(&tree_service.HeadNodeParams{Node: crt, Context: ctx}); e != nil {
-			log.Fatalf("could not find workspace %s. Please specify a parent workspace that exists", crt)
+		if !exists(crt) {
+			rest.Log.Fatalf("could not find workspace %s. Please specify a parent workspace that exists", crt)
 		}
 
 		for i := 1; i < len(parts)-1; i++ {
 			crt = path.Join(crt, parts[i])
-			_, e := apiClient.TreeService.HeadNode(&tree_service.HeadNodeParams{Node: crt, Context: ctx})
-			if e != nil {
+			if !exists(crt) {
 				if createAncestors {
 					dirs = append(dirs, &models.TreeNode{
 						Path: crt,
@@ -76,7 +94,7 @@ EXAMPLES
 					})
 					paths = append(paths, crt)
 				} else {
-					log.Fatalf("Could not find folder at %s, double check and correct your path or use the '-p' flag if you want to force the creation of missing ancestors.", crt)
+					rest.Log.Fatalf("Could not find folder at %s, double check and correct your path or use the '-p' flag if you want to force the creation of missing ancestors.", crt)
 				}
 			}
 		}
@@ -99,8 +117,9 @@ EXAMPLES
 			},
 			Context: ctx,
 		})
+		sdkClient.InvalidateMeta(paths...)
 		if err != nil {
-			log.Fatal("error while calling CreateNodes:", err)
+			rest.Log.Fatal("error while calling CreateNodes:", err)
 		}
 		// Wait that it is indexed
 		e := rest.RetryCallback(func() error {
@@ -112,7 +131,7 @@ EXAMPLES
 		}, 10, 2*time.Second)
 
 		if e != nil {
-			log.Fatal(e)
+			rest.Log.Fatal(e)
 		}
 		fmt.Printf("SUCCESS: dir %s created and indexed\n", dir)
 
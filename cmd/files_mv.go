@@ -1,15 +1,18 @@
 package cmd
 
 import (
-	"log"
 	"os"
-	"path"
+	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/pydio/cells-sdk-go/v4/models"
+
 	"github.com/pydio/cells-client/v4/rest"
 )
 
+var mvDryRun bool
+
 // filesMvCmd represents the filesMv command
 var filesMvCmd = &cobra.Command{
 	Use:   "mv",
@@ -30,44 +33,57 @@ EXAMPLES
   ` + os.Args[0] + ` mv common-files/picture.jpg common-files/p2.jpg
 
   Move all nodes recursively:
-  ` + os.Args[0] + ` mv common-files/photos/* personal-files/photos/
-`,
-	Args: cobra.MinimumNArgs(2),
+  ` + os.Args[0] + ` mv 'common-files/photos/*' personal-files/photos/
+
+  Move several nodes at once, the target must then be an existing folder:
+  ` + os.Args[0] + ` mv 'common-files/photos/*.{jpg,png}' common-files/notes.txt personal-files/archives/
+` + globHelp,
+	Args:              cobra.MinimumNArgs(2),
+	ValidArgsFunction: completeRemotePaths,
 	Run: func(cmd *cobra.Command, args []string) {
 
-		source := args[0]
-		target := args[1]
+		sources := args[:len(args)-1]
+		target := args[len(args)-1]
 
 		ctx := cmd.Context()
 
-		var sourceNodes []string
-		if path.Base(source) == "*" {
-			nodes, err := sdkClient.ListNodesPath(ctx, source)
-			if err != nil {
-				log.Println("could not list the nodes path", err)
-			}
-			sourceNodes = nodes
-		} else {
-			_, exists := sdkClient.StatNode(ctx, source)
-			if !exists {
-				log.Fatalf("This node does not exist: [%v]\n", source)
+		var hasGlob bool
+		for _, s := range sources {
+			hasGlob = hasGlob || sdkClient.IsPattern(ctx, trimRemotePrefix(s))
+		}
+		sourceNodes := expandRemotePaths(ctx, sources, true)
+		if len(sourceNodes) == 0 {
+			rest.Log.Fatalln("Nothing to move")
+		}
+
+		if hasGlob || len(sourceNodes) > 1 {
+			// Several sources: the target must be an existing folder
+			if t, exists := sdkClient.StatNode(ctx, target); !exists || *t.Type != models.TreeNodeTypeCOLLECTION {
+				rest.Log.Fatalf("Target %s must be an existing folder when moving several nodes\n", target)
 			}
-			sourceNodes = []string{source}
+			target = strings.TrimRight(target, "/") + "/"
+		}
+
+		if mvDryRun {
+			printNodesSummary(ctx, "moved to "+target, sourceNodes)
+			printOverwrites(ctx, sourceNodes, target, strings.HasSuffix(target, "/"))
+			return
 		}
 
 		params := rest.MoveParams(sourceNodes, target)
 		jobID, err := sdkClient.MoveJob(ctx, params)
 		if err != nil {
-			log.Fatalln("Could not run job:", err.Error())
+			rest.Log.Fatalln("Could not run job:", err.Error())
 		}
 
 		err = sdkClient.MonitorJob(ctx, jobID)
 		if err != nil {
-			log.Fatalln("Could not monitor job:", err.Error())
+			rest.Log.Fatalln("Could not monitor job:", err.Error())
 		}
 	},
 }
 
 func init() {
+	filesMvCmd.Flags().BoolVar(&mvDryRun, "dry-run", false, "Only show a summary of the nodes that would be moved and of the existing nodes they would overwrite")
 	RootCmd.AddCommand(filesMvCmd)
 }
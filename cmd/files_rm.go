@@ -3,10 +3,8 @@ package cmd
 import (
 	"os"
 	"path"
-	"strings"
 	"sync"
 
-	"github.com/manifoldco/promptui"
 	"github.com/spf13/cobra"
 
 	"github.com/pydio/cells-client/v4/rest"
@@ -15,6 +13,7 @@ import (
 var (
 	rmPermanently  bool
 	rmForce        bool
+	rmDryRun       bool
 	rmWildcardChar = "%"
 )
 
@@ -40,8 +39,12 @@ EXAMPLES
   # Remove a single file:
   ` + os.Args[0] + ` rm common-files/target.txt
 
-  # Remove recursively inside a folder, the wildcard is '%':
-  ` + os.Args[0] + ` rm common-files/folder/%
+  # Remove all children of a folder:
+  ` + os.Args[0] + ` rm 'common-files/folder/*'
+
+  # Remove all jpg and png images in a folder and its sub-folders, after checking which files match:
+  ` + os.Args[0] + ` rm --dry-run 'common-files/folder/**/*.{jpg,png}'
+  ` + os.Args[0] + ` rm 'common-files/folder/**/*.{jpg,png}'
 
   # Remove a folder and all its children (even if it is not empty)
   ` + os.Args[0] + ` rm common-files/folder
@@ -49,7 +52,8 @@ EXAMPLES
   # Remove multiple files
   ` + os.Args[0] + ` rm common-files/file-1.txt common-files/file-2.txt
 
-  # You can force the deletion with the '--force' flag (to avoid the Yes or No)
+  # You can force the deletion with the '--force' flag (to avoid the Yes or No).
+  # It is required when the command is not run in an interactive terminal, e.g. in a script
   ` + os.Args[0] + ` rm -f common-files/file-1.txt
 
   # Skip the recycle and permanently remove a file
@@ -58,45 +62,29 @@ EXAMPLES
   # DANGER: directly and permanently remove a folder and all its children
   ` + os.Args[0] + ` rm -pf common-files/folder
 
-`,
-	Args: cobra.MinimumNArgs(1),
+  For backward compatibility, a '%' as last segment of a path still means all children of the parent folder.
+` + globHelp,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeRemotePaths,
 	Run: func(cmd *cobra.Command, args []string) {
 
-		// Ask for user approval before deleting
-		if !rmForce {
-			p := promptui.Select{Label: "Are you sure", Items: []string{"No", "Yes"}}
-			if _, resp, e := p.Run(); resp == "No" && e == nil {
-				cmd.Println(promptui.IconBad, "Aborted by user")
-				return
-			}
-		}
 		ctx := cmd.Context()
-		targetNodes := make([]string, 0)
+		var patterns []string
 		for _, arg := range args {
-			_, exists := sdkClient.StatNode(ctx, strings.TrimRight(arg, rmWildcardChar))
-			if !exists {
-				rest.Log.Warnf("Node %s not found: it could not be deleted", arg)
-				continue
+			if path.Base(arg) == rmWildcardChar { // Legacy wildcard
+				arg = path.Join(path.Dir(arg), "*")
 			}
-			if path.Base(arg) == rmWildcardChar {
-				dir, _ := path.Split(arg)
-				newArg := path.Join(dir, "*")
-				nodes, err := sdkClient.ListNodesPath(ctx, newArg)
-
-				// Remove recycle_bin from targetedNodes
-				for i, c := range nodes {
-					if path.Base(c) == "recycle_bin" {
-						nodes = append(nodes[:i], nodes[i+1:]...)
-					}
-				}
+			patterns = append(patterns, arg)
+		}
+		targetNodes := expandRemotePaths(ctx, patterns, true)
 
-				if err != nil {
-					rest.Log.Fatalf("Could not list nodes inside %s, aborting. Cause: %s\n", path.Dir(arg), err.Error())
-				}
-				targetNodes = append(targetNodes, nodes...)
-			} else {
-				targetNodes = append(targetNodes, arg)
-			}
+		action := "moved to the recycle bin"
+		if rmPermanently {
+			action = "permanently deleted"
+		}
+		if rmDryRun {
+			printNodesSummary(ctx, action, targetNodes)
+			return
 		}
 
 		if len(targetNodes) <= 0 {
@@ -104,6 +92,14 @@ EXAMPLES
 			return
 		}
 
+		// Show what will be deleted and ask for user approval
+		if !rmForce {
+			printNodesSummary(ctx, action, targetNodes)
+		}
+		if !confirmOrAbort(rmForce) {
+			return
+		}
+
 		jobUUID, err := sdkClient.DeleteNodes(ctx, targetNodes, rmPermanently)
 		if err != nil {
 			rest.Log.Fatalf("could not delete nodes, cause: %s\n", err)
@@ -142,4 +138,5 @@ func init() {
 	RootCmd.AddCommand(rmCmd)
 	rmCmd.Flags().BoolVarP(&rmForce, "force", "f", false, "Do not ask for user approval")
 	rmCmd.Flags().BoolVarP(&rmPermanently, "permanently", "p", false, "Skip recycle bin and directly permanently delete the target files. Warning: this is not un-doable")
+	rmCmd.Flags().BoolVar(&rmDryRun, "dry-run", false, "Only show a summary of the nodes that would be deleted")
 }
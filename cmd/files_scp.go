@@ -9,6 +9,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/dustin/go-humanize"
+	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
@@ -40,6 +42,7 @@ var (
 	scpVeryVerbose   bool
 	scpMaxBackoffStr string
 	scpS3DebugFlags  string
+	scpDryRun        bool
 )
 
 const scpHelp = `
@@ -63,6 +66,14 @@ DESCRIPTION
     - If both are folders: each child of 'new' is copied into 'old'. If an item with the same name already exists on the target side, the rules are applied recursively.
 
   WARNING: This could lead to data loss on the target side. Use with caution.
+  Use the "dry-run" flag to first check which items would be overwritten or deleted.
+
+  When downloading, the remote source path can contain wildcards, e.g. 'cells://common-files/reports/**/*.pdf':
+  all matching files and folders are then downloaded in the target folder, that must already exist.
+  See the help of the rm command for the supported patterns, and do not forget to quote the remote path.
+
+  Remote files that are locked by another user are never overwritten during an upload, unless the "force-unlock" flag is also set:
+  in such case, the lock is released once the transfer has finished. With the "dry-run" flag, the locks are only listed.
 
   Depending on your use-case, you might want to use the 'scp' command in interactive mode, with a progress bar, or with log messages, especially when launching from a script.
 
@@ -113,7 +124,8 @@ EXAMPLES
 
   Copying cells//common-files/my-folder to /home/pydio/tests	
 `,
-	Args: cobra.MinimumNArgs(2),
+	Args:              cobra.MinimumNArgs(2),
+	ValidArgsFunction: completeRemotePaths,
 	Run: func(cmd *cobra.Command, args []string) {
 		rest.DryRun = false // Debug option
 		ctx := cmd.Context()
@@ -136,6 +148,8 @@ EXAMPLES
 		rest.UploadSkipMD5 = viper.GetBool("skip-md5")
 		rest.UploadSwitchMultipart = viper.GetInt64("multipart-threshold")
 		rest.TransferRetryMaxAttempts = viper.GetInt("retry-max-attempts")
+		rest.ForceUnlock = viper.GetBool("force-unlock")
+		scpDryRun = viper.GetBool("dry-run")
 
 		// Keep backward retro-compatibility until v5 for old flags
 		if viper.GetBool("no_progress") {
@@ -238,6 +252,10 @@ EXAMPLES
 			rest.Log.Infof("Uploading %s to %s", srcPath, standardPrefix+targetPath)
 		} else { // Download
 			srcPath = strings.TrimPrefix(from, scpCurrentPrefix)
+			if sdkClient.IsPattern(ctx, srcPath) {
+				downloadMatches(ctx, srcPath, to)
+				return
+			}
 			if _, ok := sdkClient.StatNode(ctx, srcPath); !ok {
 				rest.Log.Fatalf("cannot find %s on remote server", srcPath)
 			}
@@ -252,76 +270,7 @@ EXAMPLES
 			rest.Log.Infof("Downloading %s to %s", standardPrefix+srcPath, targetPath)
 		}
 
-		// Now create source and target crawlers
-		srcNode, e := rest.NewCrawler(ctx, sdkClient, srcPath, isSrcLocal)
-		if e != nil {
-			rest.Log.Fatalln(e)
-		}
-
-		targetNode := rest.NewTarget(sdkClient, targetPath, !isSrcLocal, srcNode.IsDir, scpForce)
-		if e != nil {
-			rest.Log.Fatalln(e)
-		}
-
-		// Walk the full source tree to prepare a list of nodes to create
-		var tf *rest.CrawlNode
-		if needMerge {
-			tf = targetNode
-		}
-		t, c, d, e := srcNode.Walk(cmd.Context(), tf)
-		if e != nil {
-			rest.Log.Fatal(e)
-		}
-
-		if len(t) == 1 && len(c) == 0 && len(d) == 0 {
-			// we just transfer one file, no log at this point
-		} else {
-			rest.Log.Infof("After walking the tree, found %d nodes to delete, %d to create and %d to transfer", len(d), len(c), len(t))
-		}
-
-		var pool *rest.BarsPool = nil
-		if !scpNoProgress {
-			refreshInterval := time.Millisecond * 10 // this is the default
-			if scpQuiet {
-				refreshInterval = time.Millisecond * 3000
-			}
-			pool = rest.NewBarsPool(len(t)+len(c)+len(d) > 1, len(t)+len(c)+len(d), refreshInterval)
-			pool.Start()
-		}
-
-		// Delete necessary items
-		e = targetNode.DeleteForMerge(ctx, d, pool)
-		if e != nil {
-			if pool != nil { // Force stop of the pool that stays blocked otherwise
-				pool.Stop()
-			}
-			rest.Log.Fatal(e)
-		}
-
-		// CREATE FOLDERS
-		e = targetNode.CreateFolders(ctx, targetNode, c, pool)
-		if e != nil {
-			if pool != nil { // Force stop of the pool that stays blocked otherwise
-				pool.Stop()
-			}
-			rest.Log.Fatal(e)
-		}
-
-		// UPLOAD / DOWNLOAD FILES
-		if scpNoProgress && len(t) > 1 {
-			rest.Log.Infof("Now transferring files")
-		}
-
-		errs := targetNode.TransferAll(ctx, t, pool)
-		if len(errs) > 0 {
-			rest.Log.Infof("\nTransfer aborted after %d errors:", len(errs))
-			for i, currErr := range errs {
-				rest.Log.Infof("\t#%d: %s\n", i+1, currErr)
-			}
-			os.Exit(1)
-		} else if scpNoProgress && len(t) > 1 {
-			rest.Log.Infoln("Transfer terminated")
-		}
+		transferTree(ctx, sdkClient, srcPath, targetPath, isSrcLocal, needMerge)
 	},
 }
 
@@ -329,6 +278,8 @@ func init() {
 	flags := scpFiles.PersistentFlags()
 
 	flags.BoolP("force", "f", false, "*DANGER* turns overwrite mode on: for a given item in the source tree, if a file or folder with same name already exists on the target side, it is merged or replaced.")
+	flags.Bool("dry-run", false, "Only show which items would be transferred, created, overwritten or deleted, without modifying anything")
+	flags.Bool("force-unlock", false, "In force mode, also overwrite remote files that are locked by other users, releasing their lock after the transfer")
 	flags.BoolP("no-progress", "n", false, "Do not show progress bar. You can then fine tune the log level")
 	flags.BoolP("verbose", "v", false, "Alias for an opinionated debug configuration to investigate problematic uploads")
 	flags.BoolP("very-verbose", "w", false, "Alias that turns most of the debug options on when investigating problematic uploads")
@@ -446,3 +397,155 @@ func preProcessLocalTarget(srcName, targetPath string, force bool) (bool, error)
 		return false, fmt.Errorf("a file or folder named '%s' already exists on your machine at '%s', we cannot proceed", srcName, toPath)
 	}
 }
+
+// trimRemotePrefix removes the optional 'cells://' (or 'cells//') prefix from a remote path.
+func trimRemotePrefix(remotePath string) string {
+	if strings.HasPrefix(remotePath, standardPrefix) {
+		return strings.TrimPrefix(remotePath, standardPrefix)
+	}
+	return strings.TrimPrefix(remotePath, completionPrefix)
+}
+
+// transferTree walks the source tree and transfers it to the target, using the scp flags that are currently set.
+// It exits with an error status after listing the errors if some transfers have failed.
+func transferTree(ctx context.Context, client *rest.SdkClient, srcPath, targetPath string, isSrcLocal, needMerge bool) {
+	// Now create source and target crawlers
+	srcNode, e := rest.NewCrawler(ctx, client, srcPath, isSrcLocal)
+	if e != nil {
+		rest.Log.Fatalln(e)
+	}
+
+	targetNode := rest.NewTarget(client, targetPath, !isSrcLocal, srcNode.IsDir, scpForce)
+	if e != nil {
+		rest.Log.Fatalln(e)
+	}
+
+	// Walk the full source tree to prepare a list of nodes to create
+	var tf *rest.CrawlNode
+	if needMerge {
+		tf = targetNode
+	}
+	t, c, d, e := srcNode.Walk(ctx, tf)
+	if e != nil {
+		rest.Log.Fatal(e)
+	}
+
+	if scpDryRun {
+		printTransferSummary(targetPath, isSrcLocal, t, c, d)
+		if e = targetNode.ReleaseLocks(ctx, t, true); e != nil {
+			rest.Log.Fatal(e)
+		}
+		return
+	}
+
+	if len(t) == 1 && len(c) == 0 && len(d) == 0 {
+		// we just transfer one file, no log at this point
+	} else {
+		rest.Log.Infof("After walking the tree, found %d nodes to delete, %d to create and %d to transfer", len(d), len(c), len(t))
+	}
+
+	var pool *rest.BarsPool = nil
+	if !scpNoProgress {
+		refreshInterval := time.Millisecond * 10 // this is the default
+		if scpQuiet {
+			refreshInterval = time.Millisecond * 3000
+		}
+		pool = rest.NewBarsPool(len(t)+len(c)+len(d) > 1, len(t)+len(c)+len(d), refreshInterval)
+		pool.Start()
+	}
+
+	// Delete necessary items
+	e = targetNode.DeleteForMerge(ctx, d, pool)
+	if e != nil {
+		if pool != nil { // Force stop of the pool that stays blocked otherwise
+			pool.Stop()
+		}
+		rest.Log.Fatal(e)
+	}
+
+	// CREATE FOLDERS
+	e = targetNode.CreateFolders(ctx, targetNode, c, pool)
+	if e != nil {
+		if pool != nil { // Force stop of the pool that stays blocked otherwise
+			pool.Stop()
+		}
+		rest.Log.Fatal(e)
+	}
+
+	// UPLOAD / DOWNLOAD FILES
+	if scpNoProgress && len(t) > 1 {
+		rest.Log.Infof("Now transferring files")
+	}
+
+	errs := targetNode.TransferAll(ctx, t, pool)
+	if len(errs) > 0 {
+		rest.Log.Infof("\nTransfer aborted after %d errors:", len(errs))
+		for i, currErr := range errs {
+			rest.Log.Infof("\t#%d: %s\n", i+1, currErr)
+		}
+		exit(1)
+	} else if scpNoProgress && len(t) > 1 {
+		rest.Log.Infoln("Transfer terminated")
+	}
+
+	// Locks of overwritten files are only released once the transfer has succeeded
+	if e = targetNode.ReleaseLocks(ctx, t, false); e != nil {
+		rest.Log.Fatal(e)
+	}
+}
+
+// downloadMatches downloads all the remote nodes that match the passed pattern in the local target folder.
+func downloadMatches(ctx context.Context, pattern, to string) {
+	targetPath, err := filepath.Abs(to)
+	if err != nil {
+		rest.Log.Fatalf("%s is not a valid destination: %s", to, err)
+	}
+	if info, e := os.Stat(targetPath); e != nil || !info.IsDir() {
+		rest.Log.Fatalf("%s must be an existing folder when downloading several items", targetPath)
+	}
+	matches := expandRemotePaths(ctx, []string{pattern}, true)
+	for _, srcPath := range matches {
+		needMerge, e := preProcessLocalTarget(path.Base(srcPath), targetPath, scpForce)
+		if e != nil {
+			rest.Log.Fatalln(e)
+		}
+		rest.Log.Infof("Downloading %s to %s", standardPrefix+srcPath, targetPath)
+		transferTree(ctx, sdkClient, srcPath, targetPath, false, needMerge)
+	}
+}
+
+// printTransferSummary lists what would be done on the target side by a transfer, without modifying anything.
+func printTransferSummary(targetPath string, isSrcLocal bool, toTransfer, toCreate, toDelete []*rest.CrawlNode) {
+	join := filepath.Join
+	if isSrcLocal { // Target is remote
+		join = path.Join
+	}
+	var overwrites int
+	var total uint64
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Action", "Path", "Size"})
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+	for _, n := range toDelete {
+		table.Append([]string{"Delete", n.FullPath, "-"})
+	}
+	for _, n := range toCreate {
+		table.Append([]string{"Create folder", join(targetPath, n.RelPath), "-"})
+	}
+	for _, n := range toTransfer {
+		action := "Transfer"
+		if n.Overwrites {
+			action = "Overwrite"
+			overwrites++
+		}
+		total += uint64(n.Size)
+		table.Append([]string{action, join(targetPath, n.RelPath), humanize.IBytes(uint64(n.Size))})
+	}
+
+	fmt.Printf("[Dry run] %d file(s) would be transferred (%s), %d of them overwriting existing files, "+
+		"%d folder(s) would be created and %d item(s) would be deleted on the target side.\n",
+		len(toTransfer), humanize.IBytes(total), overwrites, len(toCreate), len(toDelete))
+	if len(toTransfer)+len(toCreate)+len(toDelete) > 0 {
+		table.Render()
+	}
+}
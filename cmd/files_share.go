@@ -1,23 +1,49 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
-	"log"
 	"os"
 	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/pydio/cells-sdk-go/v4/models"
 
 	"github.com/pydio/cells-client/v4/rest"
 )
 
+var (
+	shareLabel        string
+	shareDescription  string
+	sharePassword     string
+	shareExpire       string
+	shareMaxDownloads int64
+	sharePermissions  []string
+	shareCustomHash   string
+	shareFormat       string
+)
+
+var uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
 var shareNode = &cobra.Command{
 	Use:   "share",
-	Short: "Share a single file or folder",
+	Short: "Manage public links on files and folders",
 	Long: `
 DESCRIPTION
 
-  Create a public link that adds public access to the passed path on the server.
+  Create and manage public links that give public access to files and folders of the server.
+  See the help of respective sub-commands for further details.
+
+  For backward compatibility, calling this command directly with a path creates a link,
+  as the 'create' sub-command does: it accepts the same options.
 
 EXAMPLES
 
@@ -28,8 +54,42 @@ EXAMPLES
 `,
 	Args: cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
+		shareCreate.Run(cmd, args)
+	},
+}
 
-		p := args[0]
+var shareCreate = &cobra.Command{
+	Use:   "create",
+	Short: "Create a public link on a single file or folder",
+	Long: `
+DESCRIPTION
+
+  Create a public link that adds public access to the passed path on the server.
+
+  You can fine tune the link with the following flags:
+   - password: visitors must enter this password to access the link
+   - expire: the link stops working after this date. Use either a date (2006-01-02 or "2006-01-02 15:04:05")
+     or a duration starting from now (e.g. 72h or 7d)
+   - max-downloads: the link stops working after this number of downloads
+   - permissions: a comma separated list of preview, download and upload (upload is only valid for folders).
+     Default is preview,download
+   - hash: a custom, human-readable, hash to be used in the link URL instead of the generated one
+
+EXAMPLES
+
+  1/ Create a link with a technical ID
+  $ ` + os.Args[0] + ` share create common-files/MyPublicImage.jpg
+  Public link created at https://pydio.example.com/public/479cc5dbdf8b
+
+  2/ Create a password protected link to drop files in a folder, valid for 7 days
+  $ ` + os.Args[0] + ` share create common-files/inbox --password secret --expire 7d --permissions upload --hash inbox-acme
+  Public link created at https://pydio.example.com/public/inbox-acme
+`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeRemotePaths,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		p := trimRemotePrefix(args[0])
 		ctx := cmd.Context()
 		node, exists := sdkClient.StatNode(ctx, p)
 
@@ -39,16 +99,192 @@ EXAMPLES
 			return
 		}
 
-		l, err := sdkClient.CreateSimpleFolderLink(ctx, node.UUID, path.Base(p))
+		options, err := shareOptionsFromFlags(cmd)
 		if err != nil {
-			log.Fatal(err)
+			rest.Log.Fatal(err)
+		}
+		if options.Label == "" {
+			options.Label = path.Base(p)
+		}
+
+		l, err := sdkClient.CreateShareLink(ctx, node, options)
+		if err != nil {
+			rest.Log.Fatal(err)
 		}
 
+		if shareFormat == "json" {
+			printLinksAsJson([]*models.RestShareLink{l})
+			return
+		}
 		cmd.Println("Public link created at " + rest.StandardizeLink(sdkClient.GetConfig(), l.LinkURL))
 		fmt.Println("") // Add a line to reduce glitches in the terminal
 	},
 }
 
 func init() {
+	flags := shareCreate.PersistentFlags()
+	addShareOptionFlags(flags)
+	flags.StringVar(&shareFormat, "format", "table", "Output format table|json")
+	// Also accept the options when the link is directly created with the parent command
+	legacyFlags := shareNode.Flags()
+	addShareOptionFlags(legacyFlags)
+	legacyFlags.StringVar(&shareFormat, "format", "table", "Output format table|json")
+
+	shareNode.AddCommand(shareCreate)
 	RootCmd.AddCommand(shareNode)
 }
+
+// addShareOptionFlags registers the flags that are common to the link creation and update.
+func addShareOptionFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&shareLabel, "label", "", "Label of the link, defaults to the name of the shared node")
+	flags.StringVar(&shareDescription, "description", "", "Description of the link")
+	flags.StringVar(&sharePassword, "password", "", "Protect the link with this password")
+	flags.StringVar(&shareExpire, "expire", "", "Expiration date (2006-01-02 or \"2006-01-02 15:04:05\") or duration from now (e.g. 72h or 7d)")
+	flags.Int64Var(&shareMaxDownloads, "max-downloads", 0, "Maximum number of downloads, 0 means no limit")
+	flags.StringSliceVar(&sharePermissions, "permissions", []string{}, "Comma separated list of permissions: preview, download and/or upload")
+	flags.StringVar(&shareCustomHash, "hash", "", "Custom hash to be used in the link URL")
+}
+
+// shareOptionsFromFlags builds the link options from the flags that have been parsed for the passed command.
+func shareOptionsFromFlags(cmd *cobra.Command) (*rest.ShareLinkOptions, error) {
+	options := &rest.ShareLinkOptions{
+		Label:       shareLabel,
+		Description: shareDescription,
+		Password:    sharePassword,
+		CustomHash:  shareCustomHash,
+	}
+	// 0 is a valid value, that removes the limit
+	if cmd.Flags().Changed("max-downloads") {
+		if shareMaxDownloads < 0 {
+			return nil, fmt.Errorf("invalid maximum number of downloads %d", shareMaxDownloads)
+		}
+		options.MaxDownloads = &shareMaxDownloads
+	}
+	for _, p := range sharePermissions {
+		options.Permissions = append(options.Permissions, strings.ToLower(strings.TrimSpace(p)))
+	}
+	if shareExpire != "" {
+		expireAt, err := parseExpiration(shareExpire)
+		if err != nil {
+			return nil, err
+		}
+		options.ExpireAt = expireAt
+	}
+	return options, nil
+}
+
+// parseExpiration accepts either an absolute date or a duration starting from now.
+func parseExpiration(value string) (time.Time, error) {
+	if d, err := parseLongDuration(value); err == nil {
+		return time.Now().Add(d), nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid expiration %s, use a date (2006-01-02 or \"2006-01-02 15:04:05\") or a duration (e.g. 72h or 7d)", value)
+}
+
+// parseLongDuration extends the standard Go duration format with a 'd' suffix for days.
+func parseLongDuration(value string) (time.Duration, error) {
+	if strings.HasSuffix(value, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %s", value)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(value)
+}
+
+// resolveShareLink finds a link either by its UUID or by the path of the shared node.
+func resolveShareLink(ctx context.Context, linkUuidOrPath string) (*models.RestShareLink, error) {
+	if uuidRegexp.MatchString(linkUuidOrPath) {
+		if l, err := sdkClient.GetShareLink(ctx, linkUuidOrPath); err == nil {
+			return l, nil
+		}
+	}
+
+	p := trimRemotePrefix(linkUuidOrPath)
+	node, exists := sdkClient.StatNode(ctx, p)
+	if !exists {
+		return nil, fmt.Errorf("no link with UUID %s and no node found at %s", linkUuidOrPath, p)
+	}
+	links, err := sdkClient.FindShareLinksForNode(ctx, node.UUID)
+	if err != nil {
+		return nil, err
+	}
+	switch len(links) {
+	case 0:
+		return nil, fmt.Errorf("no public link found on %s", p)
+	case 1:
+		// Retrieve full info
+		return sdkClient.GetShareLink(ctx, links[0].UUID)
+	default:
+		var ids []string
+		for _, l := range links {
+			ids = append(ids, l.UUID)
+		}
+		return nil, fmt.Errorf("found %d links on %s, please use one of their UUID: %s", len(links), p, strings.Join(ids, ", "))
+	}
+}
+
+func printLinksAsJson(links []*models.RestShareLink) {
+	for _, l := range links {
+		l.LinkURL = rest.StandardizeLink(sdkClient.GetConfig(), l.LinkURL)
+	}
+	data, _ := json.MarshalIndent(links, "", "  ")
+	fmt.Printf("%s\n", data)
+}
+
+func printLinkDetails(l *models.RestShareLink) {
+	var perms []string
+	for _, p := range l.Permissions {
+		if p != nil {
+			perms = append(perms, strings.ToLower(string(*p)))
+		}
+	}
+	var roots []string
+	for _, n := range l.RootNodes {
+		if n.Path != "" {
+			roots = append(roots, n.Path)
+		} else {
+			roots = append(roots, n.UUID)
+		}
+	}
+	downloads := "-"
+	if l.MaxDownloads != "" && l.MaxDownloads != "0" {
+		downloads = fmt.Sprintf("%s / %s", rest.ValueOr(l.CurrentDownloads, "0"), l.MaxDownloads)
+	} else if l.CurrentDownloads != "" {
+		downloads = l.CurrentDownloads
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+	table.AppendBulk([][]string{
+		{"UUID", l.UUID},
+		{"Label", l.Label},
+		{"Description", l.Description},
+		{"URL", rest.StandardizeLink(sdkClient.GetConfig(), l.LinkURL)},
+		{"Hash", l.LinkHash},
+		{"Shared nodes", strings.Join(roots, ", ")},
+		{"Permissions", strings.Join(perms, ", ")},
+		{"Password", strconv.FormatBool(l.PasswordRequired)},
+		{"Expires", stampToAbsoluteDate(l.AccessEnd)},
+		{"Downloads", downloads},
+	})
+	table.Render()
+}
+
+// stampToAbsoluteDate formats a string unix timestamp as a date, or returns "-" when not set.
+func stampToAbsoluteDate(stamp string) string {
+	if stamp == "" || stamp == "0" {
+		return "-"
+	}
+	if i, e := strconv.ParseInt(stamp, 10, 64); e == nil {
+		return time.Unix(i, 0).Format("2006-01-02 15:04:05")
+	}
+	return "-"
+}
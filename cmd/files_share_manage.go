@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/pydio/cells-sdk-go/v4/models"
+
+	"github.com/pydio/cells-client/v4/rest"
+)
+
+var (
+	shareUpdateNoPassword bool
+	shareRmForce          bool
+)
+
+var shareLs = &cobra.Command{
+	Use:   "ls",
+	Short: "List the public links of the current user",
+	Long: `
+DESCRIPTION
+
+  List all public links that have been created by the current user.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` share ls
+  $ ` + os.Args[0] + ` share ls --format json
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		resources, err := sdkClient.ListShareLinks(cmd.Context())
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+
+		var links []*models.RestShareLink
+		var paths []string
+		for _, r := range resources {
+			if r.Link == nil {
+				continue
+			}
+			links = append(links, r.Link)
+			if r.Node != nil {
+				paths = append(paths, r.Node.Path)
+			} else {
+				paths = append(paths, "")
+			}
+		}
+
+		switch shareFormat {
+		case "json":
+			printLinksAsJson(links)
+		case "table":
+			if len(links) == 0 {
+				fmt.Println("No public link found.")
+				return
+			}
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"UUID", "Label", "Path", "URL", "Expires"})
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetAutoWrapText(false)
+			for i, l := range links {
+				table.Append([]string{
+					l.UUID,
+					l.Label,
+					paths[i],
+					rest.StandardizeLink(sdkClient.GetConfig(), l.LinkURL),
+					stampToAbsoluteDate(l.AccessEnd),
+				})
+			}
+			table.Render()
+		default:
+			cmd.Println("invalid output format, it must be either json or table")
+		}
+	},
+}
+
+var shareShow = &cobra.Command{
+	Use:   "show",
+	Short: "Show the details of a public link",
+	Long: `
+DESCRIPTION
+
+  Show the details of a public link, that is designated by its UUID or by the path of the shared node.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` share show 3f4b1a2e-71a2-4d0c-9c1f-5d4b2a8e1c6f
+  $ ` + os.Args[0] + ` share show common-files/MyPublicImage.jpg --format json
+`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeRemotePaths,
+	Run: func(cmd *cobra.Command, args []string) {
+		l, err := resolveShareLink(cmd.Context(), args[0])
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		switch shareFormat {
+		case "json":
+			printLinksAsJson([]*models.RestShareLink{l})
+		case "table":
+			printLinkDetails(l)
+		default:
+			cmd.Println("invalid output format, it must be either json or table")
+		}
+	},
+}
+
+var shareUpdate = &cobra.Command{
+	Use:   "update",
+	Short: "Update an existing public link",
+	Long: `
+DESCRIPTION
+
+  Update a public link, that is designated by its UUID or by the path of the shared node.
+  Only the options that are explicitly passed are modified.
+  See '` + os.Args[0] + ` share create --help' for the details about known options.
+
+EXAMPLES
+
+  # Extend validity of the link on a file and limit the number of downloads
+  $ ` + os.Args[0] + ` share update common-files/MyPublicImage.jpg --expire 2030-12-31 --max-downloads 10
+
+  # Remove the limit of downloads
+  $ ` + os.Args[0] + ` share update common-files/MyPublicImage.jpg --max-downloads 0
+
+  # Remove the password protection
+  $ ` + os.Args[0] + ` share update 3f4b1a2e-71a2-4d0c-9c1f-5d4b2a8e1c6f --no-password
+`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeRemotePaths,
+	Run: func(cmd *cobra.Command, args []string) {
+		if shareUpdateNoPassword && sharePassword != "" {
+			rest.Log.Fatal("--password and --no-password flags cannot be used together")
+		}
+		ctx := cmd.Context()
+		l, err := resolveShareLink(ctx, args[0])
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		options, err := shareOptionsFromFlags(cmd)
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		updated, err := sdkClient.UpdateShareLink(ctx, l, options, shareUpdateNoPassword)
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		switch shareFormat {
+		case "json":
+			printLinksAsJson([]*models.RestShareLink{updated})
+		default:
+			printLinkDetails(updated)
+		}
+	},
+}
+
+var shareRm = &cobra.Command{
+	Use:   "rm",
+	Short: "Remove a public link",
+	Long: `
+DESCRIPTION
+
+  Remove a public link, that is designated by its UUID or by the path of the shared node.
+  The shared node itself is not impacted.
+  A confirmation is asked before removing, unless the --force flag is set: it is required in non-interactive sessions.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` share rm 3f4b1a2e-71a2-4d0c-9c1f-5d4b2a8e1c6f
+  $ ` + os.Args[0] + ` share rm -f common-files/MyPublicImage.jpg
+`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeRemotePaths,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		var links []*models.RestShareLink
+		for _, arg := range args {
+			l, err := resolveShareLink(ctx, arg)
+			if err != nil {
+				rest.Log.Fatal(err)
+			}
+			links = append(links, l)
+		}
+
+		if !shareRmForce {
+			var labels []string
+			for _, l := range links {
+				labels = append(labels, fmt.Sprintf("%s (%s)", l.Label, l.UUID))
+			}
+			fmt.Printf("About to remove %d link(s): %s\n", len(links), strings.Join(labels, ", "))
+		}
+		if !confirmOrAbort(shareRmForce) {
+			return
+		}
+
+		for _, l := range links {
+			if err := sdkClient.DeleteShareLink(ctx, l.UUID); err != nil {
+				rest.Log.Fatal(err)
+			}
+			fmt.Printf("Link %s has been removed\n", l.UUID)
+		}
+	},
+}
+
+func init() {
+	shareLs.PersistentFlags().StringVar(&shareFormat, "format", "table", "Output format table|json")
+	shareShow.PersistentFlags().StringVar(&shareFormat, "format", "table", "Output format table|json")
+
+	updateFlags := shareUpdate.PersistentFlags()
+	addShareOptionFlags(updateFlags)
+	updateFlags.BoolVar(&shareUpdateNoPassword, "no-password", false, "Remove the password protection")
+	updateFlags.StringVar(&shareFormat, "format", "table", "Output format table|json")
+
+	shareRm.Flags().BoolVarP(&shareRmForce, "force", "f", false, "Do not ask for user approval, required when the standard input is not a terminal")
+
+	shareNode.AddCommand(shareLs)
+	shareNode.AddCommand(shareShow)
+	shareNode.AddCommand(shareUpdate)
+	shareNode.AddCommand(shareRm)
+}
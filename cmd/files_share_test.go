@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	// Silently import convey to ease implementation
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseLongDuration(t *testing.T) {
+	Convey("Test parsing of durations in days", t, func() {
+		d, err := parseLongDuration("7d")
+		So(err, ShouldBeNil)
+		So(d, ShouldEqual, 7*24*time.Hour)
+
+		d, err = parseLongDuration("90m")
+		So(err, ShouldBeNil)
+		So(d, ShouldEqual, 90*time.Minute)
+
+		_, err = parseLongDuration("d")
+		So(err, ShouldNotBeNil)
+		_, err = parseLongDuration("1.5d")
+		So(err, ShouldNotBeNil)
+		_, err = parseLongDuration("tomorrow")
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestParseExpiration(t *testing.T) {
+	Convey("Test parsing of expiration dates", t, func() {
+		before := time.Now()
+		expireAt, err := parseExpiration("30d")
+		So(err, ShouldBeNil)
+		So(expireAt, ShouldHappenOnOrBetween, before.Add(30*24*time.Hour), time.Now().Add(30*24*time.Hour))
+
+		expireAt, err = parseExpiration("2030-01-02")
+		So(err, ShouldBeNil)
+		So(expireAt.Equal(time.Date(2030, 1, 2, 0, 0, 0, 0, time.Local)), ShouldBeTrue)
+
+		expireAt, err = parseExpiration("2030-01-02 15:04:05")
+		So(err, ShouldBeNil)
+		So(expireAt.Equal(time.Date(2030, 1, 2, 15, 4, 5, 0, time.Local)), ShouldBeTrue)
+
+		_, err = parseExpiration("02/01/2030")
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,298 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/pydio/cells-sdk-go/v4/models"
+
+	"github.com/pydio/cells-client/v4/rest"
+)
+
+var (
+	statJson  bool
+	statField string
+)
+
+// nodeStat gathers everything that we know about a node. The JSON keys are also the names accepted by the --field flag.
+type nodeStat struct {
+	Path           string                 `json:"path"`
+	UUID           string                 `json:"uuid"`
+	Type           string                 `json:"type"`
+	Size           int64                  `json:"size"`
+	Modified       string                 `json:"mtime,omitempty"`
+	ETag           string                 `json:"etag,omitempty"`
+	Hash           string                 `json:"hash,omitempty"`
+	Mime           string                 `json:"mime,omitempty"`
+	Owner          string                 `json:"owner,omitempty"`
+	Workspace      string                 `json:"workspace"`
+	WorkspaceLabel string                 `json:"workspaceLabel,omitempty"`
+	LockedBy       string                 `json:"lockedBy,omitempty"`
+	Meta           map[string]interface{} `json:"meta"`
+	Acls           []*statAcl             `json:"acls,omitempty"`
+	AclsError      string                 `json:"aclsError,omitempty"`
+	Links          []*statLink            `json:"links,omitempty"`
+}
+
+type statAcl struct {
+	RoleID      string   `json:"roleId"`
+	WorkspaceID string   `json:"workspaceId,omitempty"`
+	Actions     []string `json:"actions"`
+}
+
+type statLink struct {
+	UUID  string `json:"uuid"`
+	Label string `json:"label,omitempty"`
+	URL   string `json:"url"`
+}
+
+var statCmd = &cobra.Command{
+	Use:   "stat",
+	Short: "Show all details about a remote node",
+	Long: `
+DESCRIPTION
+
+  Show everything the server knows about a file or a folder: identifiers, size, modification time, hashes,
+  workspace, all the metadata that are stored on the node, the ACLs that are directly attached to it
+  and the public links that you have created on it.
+
+  Metadata values are decoded from JSON. Note that ACLs are only visible to users with sufficient permissions,
+  typically administrators.
+
+  Use the --json flag to get a JSON object, or the --field flag to only print a single value, typically in scripts.
+  Supported fields are the keys of the JSON output: path, uuid, type, size, mtime, etag, hash, mime, owner,
+  workspace, workspaceLabel, lockedBy, acls, aclsError and links. Use 'meta.<namespace>' to get the value of a given
+  metadata. An empty line is printed when the node has no value for the requested field.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` stat cells://common-files/reports/summary.pdf
+  $ ` + os.Args[0] + ` stat --json common-files/reports/summary.pdf
+  $ ` + os.Args[0] + ` stat --field uuid common-files/reports/summary.pdf
+  $ ` + os.Args[0] + ` stat --field meta.usermeta-tags common-files/reports/summary.pdf
+`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeRemotePaths,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		p := strings.Trim(trimRemotePrefix(args[0]), "/")
+		node, exists := sdkClient.StatNode(ctx, p)
+		if !exists {
+			rest.Log.Fatalf("Could not find node at %s\n", p)
+		}
+
+		st := newNodeStat(node)
+		if statField == "" || strings.HasPrefix(statField, "acls") {
+			if acls, err := sdkClient.SearchNodeAcls(ctx, node.UUID); err != nil {
+				st.AclsError = err.Error()
+			} else {
+				st.Acls = summarizeAcls(acls)
+			}
+		}
+		if statField == "" || strings.HasPrefix(statField, "links") {
+			if links, err := sdkClient.FindShareLinksForNode(ctx, node.UUID); err == nil {
+				for _, l := range links {
+					st.Links = append(st.Links, &statLink{UUID: l.UUID, Label: l.Label, URL: rest.StandardizeLink(sdkClient.GetConfig(), l.LinkURL)})
+				}
+			} else {
+				rest.Log.Debugf("could not list public links: %s", err.Error())
+			}
+		}
+
+		switch {
+		case statField != "":
+			v, ok := statFieldValue(st, statField)
+			if !ok {
+				rest.Log.Fatalf("Unknown field %s\n", statField)
+			}
+			fmt.Println(v)
+		case statJson:
+			data, _ := json.MarshalIndent(st, "", "  ")
+			fmt.Printf("%s\n", data)
+		default:
+			printNodeStat(st)
+		}
+	},
+}
+
+func init() {
+	flags := statCmd.Flags()
+	flags.BoolVar(&statJson, "json", false, "Print the details as a JSON object")
+	flags.StringVar(&statField, "field", "", "Only print the value of this field, e.g. uuid or meta.<namespace>")
+	RootCmd.AddCommand(statCmd)
+}
+
+func newNodeStat(node *models.TreeNode) *nodeStat {
+	p := strings.Trim(node.Path, "/")
+	st := &nodeStat{
+		Path:           p,
+		UUID:           node.UUID,
+		Type:           "file",
+		ETag:           node.Etag,
+		Hash:           fromMetaStore(node, "x-cells-hash"),
+		Mime:           fromMetaStore(node, "mime"),
+		Owner:          fromMetaStore(node, "owner"),
+		Workspace:      strings.SplitN(p, "/", 2)[0],
+		WorkspaceLabel: fromMetaStore(node, "ws_label"),
+		LockedBy:       rest.LockOwner(node),
+		Meta:           make(map[string]interface{}),
+	}
+	if rest.IsFolder(node) {
+		st.Type = "folder"
+	}
+	st.Size, _ = strconv.ParseInt(node.Size, 10, 64)
+	if i, e := strconv.ParseInt(node.MTime, 10, 64); e == nil && i > 0 {
+		st.Modified = time.Unix(i, 0).Format(time.RFC3339)
+	}
+	for k, v := range node.MetaStore {
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(v), &decoded); err != nil {
+			decoded = v
+		}
+		st.Meta[k] = decoded
+	}
+	return st
+}
+
+// summarizeAcls groups the actions of the ACLs by role and workspace.
+func summarizeAcls(acls []*models.IdmACL) []*statAcl {
+	byKey := make(map[string]*statAcl)
+	var result []*statAcl
+	for _, a := range acls {
+		if a.Action == nil {
+			continue
+		}
+		key := a.RoleID + "/" + a.WorkspaceID
+		s, ok := byKey[key]
+		if !ok {
+			s = &statAcl{RoleID: a.RoleID, WorkspaceID: a.WorkspaceID}
+			byKey[key] = s
+			result = append(result, s)
+		}
+		s.Actions = append(s.Actions, a.Action.Name)
+	}
+	return result
+}
+
+// statFields are the names of the fields accepted by the --field flag, on top of the metadata namespaces.
+// They are known in advance as empty values are omitted from the JSON output.
+var statFields = []string{"path", "uuid", "type", "size", "mtime", "etag", "hash", "mime", "owner",
+	"workspace", "workspaceLabel", "lockedBy", "meta", "acls", "aclsError", "links"}
+
+// statFieldValue returns the value of a field as printed by the --field flag: strings are printed as is, other values as JSON.
+// Known fields without value are returned empty, false is only returned for unknown fields.
+func statFieldValue(st *nodeStat, field string) (string, bool) {
+	// Metadata namespaces can contain dots: only split the first segment
+	segments := strings.SplitN(field, ".", 2)
+	name := ""
+	for _, f := range statFields {
+		if strings.EqualFold(f, segments[0]) {
+			name = f
+		}
+	}
+	if name == "" || (len(segments) > 1 && name != "meta") {
+		return "", false
+	}
+
+	data, _ := json.Marshal(st)
+	var all map[string]interface{}
+	_ = json.Unmarshal(data, &all)
+	current, found := all[name]
+	if found && len(segments) > 1 {
+		meta, _ := current.(map[string]interface{})
+		found = false
+		for k, v := range meta {
+			if strings.EqualFold(k, segments[1]) {
+				current, found = v, true
+				break
+			}
+		}
+	}
+	if !found || current == nil {
+		return "", true
+	}
+
+	switch v := current.(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	default:
+		data, _ = json.Marshal(v)
+		return string(data), true
+	}
+}
+
+func printNodeStat(st *nodeStat) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+	workspace := st.Workspace
+	if st.WorkspaceLabel != "" {
+		workspace = fmt.Sprintf("%s (%s)", st.Workspace, st.WorkspaceLabel)
+	}
+	table.AppendBulk([][]string{
+		{"Path", st.Path},
+		{"UUID", st.UUID},
+		{"Type", st.Type},
+		{"Size", fmt.Sprintf("%s (%d bytes)", sizeToHuman(strconv.FormatInt(st.Size, 10)), st.Size)},
+		{"Modified", rest.ValueOr(st.Modified, "-")},
+		{"ETag", rest.ValueOr(st.ETag, "-")},
+		{"Hash", rest.ValueOr(st.Hash, "-")},
+		{"MIME type", rest.ValueOr(st.Mime, "-")},
+		{"Owner", rest.ValueOr(st.Owner, "-")},
+		{"Workspace", workspace},
+		{"Locked by", rest.ValueOr(st.LockedBy, "-")},
+	})
+	table.Render()
+
+	if len(st.Meta) > 0 {
+		fmt.Println("\nMetadata:")
+		var keys []string
+		for k := range st.Meta {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		metaTable := tablewriter.NewWriter(os.Stdout)
+		metaTable.SetHeader([]string{"Namespace", "Value"})
+		metaTable.SetAlignment(tablewriter.ALIGN_LEFT)
+		metaTable.SetAutoWrapText(false)
+		for _, k := range keys {
+			v, _ := statFieldValue(st, "meta."+k)
+			metaTable.Append([]string{k, v})
+		}
+		metaTable.Render()
+	}
+
+	fmt.Println("\nACLs:")
+	if st.AclsError != "" {
+		fmt.Println("  Not available:", st.AclsError)
+	} else if len(st.Acls) == 0 {
+		fmt.Println("  No ACL is directly attached to this node")
+	} else {
+		aclTable := tablewriter.NewWriter(os.Stdout)
+		aclTable.SetHeader([]string{"Role", "Workspace", "Actions"})
+		aclTable.SetAlignment(tablewriter.ALIGN_LEFT)
+		aclTable.SetAutoWrapText(false)
+		for _, a := range st.Acls {
+			aclTable.Append([]string{a.RoleID, rest.ValueOr(a.WorkspaceID, "-"), strings.Join(a.Actions, ", ")})
+		}
+		aclTable.Render()
+	}
+
+	fmt.Println("\nPublic links:")
+	if len(st.Links) == 0 {
+		fmt.Println("  None")
+	}
+	for _, l := range st.Links {
+		fmt.Printf("  - %s %s\n", l.URL, rest.ValueOr(l.Label, ""))
+	}
+}
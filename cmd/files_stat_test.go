@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"testing"
+
+	// Silently import convey to ease implementation
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestStatFieldValue(t *testing.T) {
+	Convey("Test extraction of a single field of the stat result", t, func() {
+		st := &nodeStat{
+			Path:      "common-files/report.pdf",
+			Type:      "File",
+			Size:      2048,
+			Workspace: "common-files",
+			Meta: map[string]interface{}{
+				"usermeta-tags": "urgent",
+				"image.width":   1024,
+				"ImageDim":      map[string]interface{}{"height": 768},
+			},
+			Acls: []*statAcl{{RoleID: "ROOT_GROUP", Actions: []string{"read"}}},
+		}
+
+		value, ok := statFieldValue(st, "path")
+		So(ok, ShouldBeTrue)
+		So(value, ShouldEqual, "common-files/report.pdf")
+
+		// Field names are case-insensitive and numbers are not formatted with an exponent
+		value, ok = statFieldValue(st, "SIZE")
+		So(ok, ShouldBeTrue)
+		So(value, ShouldEqual, "2048")
+
+		// Metadata namespaces can contain dots
+		value, ok = statFieldValue(st, "meta.image.width")
+		So(ok, ShouldBeTrue)
+		So(value, ShouldEqual, "1024")
+		value, ok = statFieldValue(st, "meta.usermeta-tags")
+		So(ok, ShouldBeTrue)
+		So(value, ShouldEqual, "urgent")
+
+		// Objects and lists are returned as JSON
+		value, ok = statFieldValue(st, "meta.imagedim")
+		So(ok, ShouldBeTrue)
+		So(value, ShouldEqual, `{"height":768}`)
+		value, ok = statFieldValue(st, "acls")
+		So(ok, ShouldBeTrue)
+		So(value, ShouldEqual, `[{"actions":["read"],"roleId":"ROOT_GROUP"}]`)
+
+		// Documented fields and metadata without value are empty
+		for _, field := range []string{"owner", "hash", "lockedBy", "mtime", "links", "meta.unknown"} {
+			value, ok = statFieldValue(st, field)
+			So(ok, ShouldBeTrue)
+			So(value, ShouldBeEmpty)
+		}
+
+		_, ok = statFieldValue(st, "unknown")
+		So(ok, ShouldBeFalse)
+		_, ok = statFieldValue(st, "path.name")
+		So(ok, ShouldBeFalse)
+	})
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/manifoldco/promptui"
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/pydio/cells-sdk-go/v4/models"
+
+	"github.com/pydio/cells-client/v4/rest"
+)
+
+// printNodesSummary stats the passed remote paths and prints which nodes would be impacted by the current command,
+// with the number of files and folders and their total size. Folder sizes are only indicative.
+func printNodesSummary(ctx context.Context, action string, paths []string) {
+	var files, folders int
+	var total uint64
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Type", "Path", "Size"})
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+	for _, p := range paths {
+		t, size := "File", "-"
+		if node, ok := sdkClient.StatNode(ctx, p); ok {
+			if node.Type != nil && *node.Type == models.TreeNodeTypeCOLLECTION {
+				t = "Folder"
+				folders++
+			} else {
+				files++
+			}
+			size = sizeToHuman(node.Size)
+			if s, e := strconv.ParseUint(node.Size, 10, 64); e == nil {
+				total += s
+			}
+		}
+		table.Append([]string{t, p, size})
+	}
+	fmt.Printf("%d file(s) and %d folder(s), for a total of %s, would be %s:\n", files, folders, humanize.IBytes(total), action)
+	if len(paths) > 0 {
+		table.Render()
+	}
+}
+
+// printOverwrites stats the paths where the sources would be copied or moved and prints a summary of the existing nodes
+// that would be overwritten. When targetParent is true, the sources keep their name in the target folder.
+func printOverwrites(ctx context.Context, sources []string, target string, targetParent bool) {
+	var existing []string
+	for _, src := range sources {
+		dest := strings.Trim(target, "/")
+		if targetParent {
+			dest = path.Join(dest, path.Base(src))
+		}
+		if _, ok := sdkClient.StatNode(ctx, dest); ok {
+			existing = append(existing, dest)
+		}
+	}
+	if len(existing) == 0 {
+		fmt.Println("No existing node would be overwritten")
+		return
+	}
+	printNodesSummary(ctx, "overwritten", existing)
+}
+
+// confirmOrAbort asks the user to approve the operation that has been summarized, unless force is set.
+// In non-interactive sessions, it refuses to go on without the force flag rather than prompting.
+func confirmOrAbort(force bool) bool {
+	if force {
+		return true
+	}
+	if !isInteractive() {
+		rest.Log.Fatalln("Refusing to proceed without confirmation in a non-interactive session, use the --force flag to skip it")
+	}
+	p := promptui.Select{Label: "Are you sure", Items: []string{"No", "Yes"}}
+	if _, resp, e := p.Run(); resp != "Yes" || e != nil {
+		fmt.Println(promptui.IconBad, "Aborted by user")
+		return false
+	}
+	return true
+}
+
+// isInteractive returns true if the standard input is a terminal.
+func isInteractive() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
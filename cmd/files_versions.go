@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/pydio/cells-client/v4/rest"
+)
+
+var (
+	versionsGetId     string
+	versionsGetOutput string
+	versionsGetForce  bool
+)
+
+var versionsCmd = &cobra.Command{
+	Use:   "versions",
+	Short: "Manage the versions of a file",
+	Long: `
+DESCRIPTION
+
+  When a file is stored in a versioned datasource, the Cells server keeps track of its past versions.
+  Use the sub-commands to list, download or restore these versions.
+  See the help of respective sub-commands for further details.
+`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cm *cobra.Command, args []string) {
+		_ = cm.Usage()
+	},
+}
+
+var versionsLs = &cobra.Command{
+	Use:   "ls",
+	Short: "List the versions of a file",
+	Long: `
+DESCRIPTION
+
+  List all known versions of a file, the most recent first.
+  The first version in the list is the current content of the file.
+
+EXAMPLE
+
+  $ ` + os.Args[0] + ` versions ls cells://common-files/report.docx
+  Found 3 versions for common-files/report.docx:
+  +---+--------------------------------------+----------------+--------+--------+----------------------------------+
+  |   |              VERSION ID              |    MODIFIED    |  SIZE  | AUTHOR |               HASH               |
+  +---+--------------------------------------+----------------+--------+--------+----------------------------------+
+  | * | 2d8e4a1c-3c3a-4b6a-9a47-6b9f0d1b9f52 | 2 minutes ago  | 24 KiB | alice  | 9d1b0f7b3fbc6e1d8e4a6b4ad7d2f4a3 |
+  |   | 0b1c9e5e-8a47-4e7e-a6b1-1e2f9d63c1d0 | 3 days ago     | 21 KiB | bob    | 5a4b67dcbf2a8e3c7f6c2ed9b8c4f1a0 |
+  ...
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		p := trimRemotePrefix(args[0])
+		versions, err := sdkClient.ListVersions(cmd.Context(), p)
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		if len(versions) == 0 {
+			fmt.Printf("No version found for %s, is the datasource versioned?\n", p)
+			return
+		}
+
+		fmt.Printf("Found %d versions for %s:\n", len(versions), p)
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"", "Version ID", "Modified", "Size", "Author", "Hash"})
+		table.SetAutoWrapText(false)
+		// The server only stores the UUID of the author: resolve each of them once
+		authors := make(map[string]string)
+		for i, v := range versions {
+			current := ""
+			if i == 0 {
+				current = "*"
+			}
+			hash := v.Etag
+			if hash == "" {
+				hash = fromMetaStore(v, "x-cells-hash")
+			}
+			table.Append([]string{
+				current,
+				fromMetaStore(v, rest.MetaVersionId),
+				stampToDate(v.MTime),
+				sizeToHuman(v.Size),
+				versionAuthor(cmd.Context(), fromMetaStore(v, rest.MetaVersionOwner), authors),
+				hash,
+			})
+		}
+		table.Render()
+	},
+}
+
+var versionsGet = &cobra.Command{
+	Use:   "get",
+	Short: "Download a specific version of a file",
+	Long: `
+DESCRIPTION
+
+  Download a given version of a remote file to your client machine.
+  Use '` + os.Args[0] + ` versions ls' to retrieve the version IDs.
+
+  By default, the file is downloaded in the current directory, using the name of the remote file.
+  If the target exists, the download is aborted unless the '--force' flag is set.
+
+EXAMPLE
+
+  $ ` + os.Args[0] + ` versions get cells://common-files/report.docx --version 0b1c9e5e-8a47-4e7e-a6b1-1e2f9d63c1d0 -o ./report-old.docx
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if versionsGetId == "" {
+			rest.Log.Fatal("please provide the ID of the version to download with the --version flag")
+		}
+		ctx := cmd.Context()
+		p := trimRemotePrefix(args[0])
+		if _, ok := sdkClient.StatNode(ctx, p); !ok {
+			rest.Log.Fatalf("cannot find %s on remote server", p)
+		}
+
+		target, err := filepath.Abs(versionsGetOutput)
+		if err != nil {
+			rest.Log.Fatalf("%s is not a valid destination: %s", versionsGetOutput, err)
+		}
+		if info, e := os.Stat(target); e == nil {
+			if info.IsDir() {
+				target = filepath.Join(target, path.Base(p))
+			}
+		}
+		if _, e := os.Stat(target); e == nil && !versionsGetForce {
+			rest.Log.Fatalf("a file already exists at %s, use the --force flag to overwrite it", target)
+		}
+
+		reader, length, err := sdkClient.GetFileVersion(ctx, p, versionsGetId)
+		if err != nil {
+			rest.Log.Fatalf("could not retrieve version %s of %s: %s", versionsGetId, p, err.Error())
+		}
+		writer, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		defer func(writer *os.File) {
+			_ = writer.Close()
+		}(writer)
+		written, err := io.Copy(writer, reader)
+		if err != nil {
+			rest.Log.Fatalf("could not write version to %s: %s", target, err.Error())
+		} else if written != int64(length) {
+			rest.Log.Warnf("written length (%d) does not fit with source file length (%d) for %s", written, length, p)
+		}
+		rest.Log.Infof("Version %s of %s downloaded to %s", versionsGetId, p, target)
+	},
+}
+
+var versionsRestore = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore an older version of a file",
+	Long: `
+DESCRIPTION
+
+  Promote an older version of a file as its current content.
+  The current content is not lost: it remains available as a version.
+
+EXAMPLE
+
+  $ ` + os.Args[0] + ` versions restore cells://common-files/report.docx 0b1c9e5e-8a47-4e7e-a6b1-1e2f9d63c1d0
+`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		p := trimRemotePrefix(args[0])
+		versionId := strings.TrimSpace(args[1])
+
+		versions, err := sdkClient.ListVersions(ctx, p)
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		found := false
+		for i, v := range versions {
+			if fromMetaStore(v, rest.MetaVersionId) == versionId {
+				if i == 0 {
+					fmt.Printf("Version %s is already the current version of %s, nothing to do\n", versionId, p)
+					return
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			rest.Log.Fatalf("no version with ID %s found for %s", versionId, p)
+		}
+
+		if err = sdkClient.RestoreVersion(ctx, p, versionId); err != nil {
+			rest.Log.Fatal(err)
+		}
+		rest.Log.Infof("Version %s has been restored as the current version of %s", versionId, p)
+	},
+}
+
+func init() {
+	getFlags := versionsGet.PersistentFlags()
+	getFlags.StringVar(&versionsGetId, "version", "", "ID of the version to download")
+	getFlags.StringVarP(&versionsGetOutput, "output", "o", ".", "Local path where to download the version")
+	getFlags.BoolVarP(&versionsGetForce, "force", "f", false, "Overwrite the local target if it already exists")
+
+	versionsCmd.AddCommand(versionsLs)
+	versionsCmd.AddCommand(versionsGet)
+	versionsCmd.AddCommand(versionsRestore)
+	RootCmd.AddCommand(versionsCmd)
+}
+
+// versionAuthor returns the login of the author of a version, or its UUID if the user cannot be found,
+// e.g. because it has been deleted. Resolved logins are kept in the passed map.
+func versionAuthor(ctx context.Context, ownerUuid string, resolved map[string]string) string {
+	if ownerUuid == "" {
+		return "-"
+	}
+	if login, ok := resolved[ownerUuid]; ok {
+		return login
+	}
+	login := ownerUuid
+	if user, err := sdkClient.FindUserByUuid(ctx, ownerUuid); err == nil {
+		login = user.Login
+	} else {
+		rest.Log.Debugf("Could not resolve author of version: %s", err.Error())
+	}
+	resolved[ownerUuid] = login
+	return login
+}
@@ -0,0 +1,486 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/pydio/cells-sdk-go/v4/models"
+
+	"github.com/pydio/cells-client/v4/rest"
+)
+
+var (
+	aclUser       string
+	aclGroup      string
+	aclRole       string
+	aclRead       bool
+	aclWrite      bool
+	aclDeny       bool
+	aclRoleIDs    []string
+	aclWorkspaces []string
+	aclFormat     string
+)
+
+var aclCmd = &cobra.Command{
+	Use:     "acl",
+	Aliases: []string{"acls"},
+	Short:   "Manage the permissions on files and folders",
+	Long: `
+DESCRIPTION
+
+  Grant, revoke and list the permissions (ACLs) that are given to users, groups and roles on files and folders.
+  You must be logged in with an administrator account.
+  See the help of respective sub-commands for further details.
+`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cm *cobra.Command, args []string) {
+		_ = cm.Usage()
+	},
+}
+
+var aclGrant = &cobra.Command{
+	Use:   "grant",
+	Short: "Grant permissions on a path",
+	Long: `
+DESCRIPTION
+
+  Grant read, write or deny permissions on the passed paths to a user, a group or a role.
+  Paths start with the slug of a workspace, the permissions only apply through this workspace.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` idm acl grant common-files/projects --group /org/team --read --write
+  $ ` + os.Args[0] + ` idm acl grant common-files/projects/secret --user bob --deny
+  $ ` + os.Args[0] + ` idm acl grant acme/reports --role "Project managers" --read
+`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeRemotePaths,
+	Run: func(cmd *cobra.Command, args []string) {
+		updateAcls(cmd, args, true)
+	},
+}
+
+var aclRevoke = &cobra.Command{
+	Use:   "revoke",
+	Short: "Revoke permissions on a path",
+	Long: `
+DESCRIPTION
+
+  Revoke the read, write or deny permissions that have been given on the passed paths to a user, a group or a role.
+  If no permission flag is set, all permissions of the principal on the paths are revoked.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` idm acl revoke common-files/projects --group /org/team --write
+  $ ` + os.Args[0] + ` idm acl revoke common-files/projects/secret --user bob
+`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeRemotePaths,
+	Run: func(cmd *cobra.Command, args []string) {
+		updateAcls(cmd, args, false)
+	},
+}
+
+var aclLs = &cobra.Command{
+	Use:   "ls",
+	Short: "List the permissions per principal",
+	Long: `
+DESCRIPTION
+
+  List the permissions that are defined on the passed paths and/or for the roles and workspaces given with the flags.
+  For each principal and node, read and write permissions are combined, and deny prevails.
+
+  By default, the permissions that are directly defined for each principal are listed. With the --user flag,
+  the effective permissions of this user are computed instead: the ACLs of all its roles are merged by increasing
+  priority (the root group role, the roles of the parent groups, the assigned roles and the own role of the user)
+  and on each node, the role with the highest priority that defines rights wins. Use the 'idm can' command to also
+  take the parent folders into account.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` idm acl ls common-files/projects
+  $ ` + os.Args[0] + ` idm acl ls --user bob --workspace common-files
+  $ ` + os.Args[0] + ` idm acl ls --role ROOT_GROUP --workspace common-files
+  $ ` + os.Args[0] + ` idm acl ls --workspace acme --format json
+`,
+	ValidArgsFunction: completeRemotePaths,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		if len(args) == 0 && len(aclRoleIDs) == 0 && len(aclWorkspaces) == 0 && aclUser == "" {
+			rest.Log.Fatalln("Please give at least one path, role, workspace or user to filter the ACLs")
+		}
+		if aclUser != "" && len(aclRoleIDs) > 0 {
+			rest.Log.Fatalln("The --user and --role flags cannot be used together")
+		}
+
+		query := &models.IdmACLSingleQuery{RoleIDs: aclRoleIDs}
+		var userRoles []*canRole
+		if aclUser != "" {
+			user, err := sdkClient.FindUser(ctx, aclUser)
+			if err != nil {
+				rest.Log.Fatal(err)
+			}
+			for _, r := range user.Roles {
+				userRoles = append(userRoles, &canRole{ID: r.UUID, Label: canRoleLabel(user, r)})
+				query.RoleIDs = append(query.RoleIDs, r.UUID)
+			}
+			if len(userRoles) == 0 {
+				rest.Log.Fatalf("Could not retrieve the roles of user %s\n", user.Login)
+			}
+		}
+		paths := make(map[string]string)
+		for _, arg := range args {
+			p := strings.Trim(trimRemotePrefix(arg), "/")
+			node, ok := sdkClient.StatNode(ctx, p)
+			if !ok {
+				rest.Log.Fatalf("Could not find node at %s\n", p)
+			}
+			query.NodeIDs = append(query.NodeIDs, node.UUID)
+			paths[node.UUID] = p
+		}
+		for _, w := range aclWorkspaces {
+			if ws, err := sdkClient.FindWorkspace(ctx, w); err == nil {
+				query.WorkspaceIDs = append(query.WorkspaceIDs, ws.UUID)
+			} else {
+				query.WorkspaceIDs = append(query.WorkspaceIDs, w)
+			}
+		}
+		acls, err := sdkClient.SearchAcls(ctx, query)
+		if err != nil {
+			rest.Log.Fatalf("Could not list ACLs: %s\n", err.Error())
+		}
+		var rights []*principalRight
+		if userRoles != nil {
+			rights = effectiveRights(userRoles, acls)
+		} else {
+			rights = principalRights(ctx, acls)
+		}
+
+		switch aclFormat {
+		case "json":
+			if rights == nil {
+				rights = []*principalRight{}
+			}
+			data, _ := json.MarshalIndent(rights, "", "  ")
+			fmt.Printf("%s\n", data)
+		case "table":
+			if len(rights) == 0 {
+				fmt.Println("No ACL found.")
+				return
+			}
+			table := tablewriter.NewWriter(os.Stdout)
+			principal := "Principal"
+			if userRoles != nil {
+				principal = "Granted by"
+			}
+			table.SetHeader([]string{principal, "Role ID", "Workspace", "Node", "Rights"})
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetAutoWrapText(false)
+			for _, r := range rights {
+				node := r.NodeID
+				if p, ok := paths[node]; ok {
+					node = p
+				}
+				table.Append([]string{rest.ValueOr(r.RoleLabel, "-"), r.RoleID, rest.ValueOr(r.WorkspaceID, "-"), rest.ValueOr(node, "-"), r.Rights})
+			}
+			table.Render()
+		default:
+			cmd.Println("invalid output format, it must be either json or table")
+		}
+	},
+}
+
+func init() {
+	for _, c := range []*cobra.Command{aclGrant, aclRevoke} {
+		flags := c.Flags()
+		flags.StringVar(&aclUser, "user", "", "Login of the user")
+		flags.StringVar(&aclGroup, "group", "", "Full path of the group")
+		flags.StringVar(&aclRole, "role", "", "UUID or label of the role")
+		flags.BoolVar(&aclRead, "read", false, "Read permission")
+		flags.BoolVar(&aclWrite, "write", false, "Write permission")
+		flags.BoolVar(&aclDeny, "deny", false, "Explicitly deny any access")
+	}
+	aclLs.Flags().StringVar(&aclUser, "user", "", "List the effective permissions of this user, computed from all its roles")
+	aclLs.Flags().StringArrayVar(&aclRoleIDs, "role", []string{}, "Only list the ACLs of this role ID, can be repeated")
+	aclLs.Flags().StringArrayVar(&aclWorkspaces, "workspace", []string{}, "Only list the ACLs of this workspace slug or ID, can be repeated")
+	aclLs.Flags().StringVar(&aclFormat, "format", "table", "Output format table|json")
+
+	aclCmd.AddCommand(aclGrant, aclRevoke, aclLs)
+	idmCmd.AddCommand(aclCmd)
+}
+
+// aclPrincipal resolves the role that corresponds to the user, group or role passed with the flags.
+func aclPrincipal(ctx context.Context) (roleID, label string, err error) {
+	count := 0
+	for _, v := range []string{aclUser, aclGroup, aclRole} {
+		if v != "" {
+			count++
+		}
+	}
+	if count != 1 {
+		return "", "", fmt.Errorf("please give exactly one of the --user, --group or --role flags")
+	}
+	switch {
+	case aclUser != "":
+		u, e := sdkClient.FindUser(ctx, aclUser)
+		if e != nil {
+			return "", "", e
+		}
+		return u.UUID, "user " + u.Login, nil
+	case aclGroup != "":
+		g, e := sdkClient.FindGroup(ctx, aclGroup)
+		if e != nil {
+			return "", "", e
+		}
+		return g.UUID, "group " + rest.GroupFullPath(g), nil
+	default:
+		r, e := sdkClient.FindRole(ctx, aclRole)
+		if e != nil {
+			return "", "", e
+		}
+		return r.UUID, "role " + r.Label, nil
+	}
+}
+
+func updateAcls(cmd *cobra.Command, args []string, grant bool) {
+	ctx := cmd.Context()
+	roleID, label, err := aclPrincipal(ctx)
+	if err != nil {
+		rest.Log.Fatal(err)
+	}
+	var actions []string
+	if aclRead {
+		actions = append(actions, "read")
+	}
+	if aclWrite {
+		actions = append(actions, "write")
+	}
+	if aclDeny {
+		actions = append(actions, "deny")
+	}
+	if len(actions) == 0 {
+		if grant {
+			rest.Log.Fatalln("Please give at least one of the --read, --write or --deny flags")
+		}
+		actions = []string{"read", "write", "deny"}
+	}
+
+	for _, arg := range args {
+		p := strings.Trim(trimRemotePrefix(arg), "/")
+		nodeUUID, wsUUID, err := resolveAclNode(ctx, p)
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		existing, err := sdkClient.SearchAcls(ctx, &models.IdmACLSingleQuery{
+			RoleIDs:      []string{roleID},
+			NodeIDs:      []string{nodeUUID},
+			WorkspaceIDs: []string{wsUUID},
+		})
+		if err != nil {
+			rest.Log.Fatalf("Could not list ACLs of %s: %s\n", p, err.Error())
+		}
+		has := make(map[string]*models.IdmACL)
+		for _, a := range existing {
+			if a.Action != nil {
+				has[a.Action.Name] = a
+			}
+		}
+
+		var changed []string
+		for _, action := range actions {
+			current, exists := has[action]
+			switch {
+			case grant && !exists:
+				acl := &models.IdmACL{
+					Action:      &models.IdmACLAction{Name: action, Value: "1"},
+					RoleID:      roleID,
+					NodeID:      nodeUUID,
+					WorkspaceID: wsUUID,
+				}
+				if err = sdkClient.PutAcl(ctx, acl); err != nil {
+					rest.Log.Fatal(err)
+				}
+				changed = append(changed, action)
+			case !grant && exists:
+				if err = sdkClient.DeleteAcl(ctx, current); err != nil {
+					rest.Log.Fatal(err)
+				}
+				changed = append(changed, action)
+			}
+		}
+		switch {
+		case len(changed) == 0:
+			fmt.Printf("Nothing to change for %s on %s\n", label, p)
+		case grant:
+			fmt.Printf("Granted %s to %s on %s\n", strings.Join(changed, ", "), label, p)
+		default:
+			fmt.Printf("Revoked %s from %s on %s\n", strings.Join(changed, ", "), label, p)
+		}
+	}
+}
+
+// resolveAclNode returns the UUIDs of the node at the passed path and of its workspace. When the current user
+// cannot access the workspace, the path is resolved from the roots of the workspace in the administration tree.
+func resolveAclNode(ctx context.Context, p string) (nodeUUID, wsUUID string, err error) {
+	if node, ok := sdkClient.StatNode(ctx, p); ok && fromMetaStore(node, "ws_uuid") != "" {
+		return node.UUID, fromMetaStore(node, "ws_uuid"), nil
+	}
+
+	slug, rel, _ := strings.Cut(p, "/")
+	ws, err := sdkClient.FindWorkspace(ctx, slug)
+	if err != nil {
+		return "", "", fmt.Errorf("could not find node at %s: %s", p, err.Error())
+	}
+	roots := rest.WorkspaceRootUuids(ws)
+	var rootPath string
+	if len(roots) == 1 {
+		if root, ok := ws.RootNodes[roots[0]]; ok {
+			rootPath = root.Path
+		}
+		if rel == "" {
+			return roots[0], ws.UUID, nil
+		}
+	} else {
+		// Paths in workspaces with several roots start with the name of the root folder
+		first, sub, _ := strings.Cut(rel, "/")
+		for _, id := range roots {
+			if root, ok := ws.RootNodes[id]; ok && path.Base(root.Path) == first {
+				if sub == "" {
+					return id, ws.UUID, nil
+				}
+				rootPath, rel = root.Path, sub
+			}
+		}
+	}
+	if rootPath == "" {
+		return "", "", fmt.Errorf("could not find node at %s", p)
+	}
+	node, err := sdkClient.StatAdminNode(ctx, path.Join(rootPath, rel))
+	if err != nil {
+		return "", "", err
+	}
+	return node.UUID, ws.UUID, nil
+}
+
+type principalRight struct {
+	RoleID      string `json:"roleId"`
+	RoleLabel   string `json:"roleLabel,omitempty"`
+	WorkspaceID string `json:"workspaceId,omitempty"`
+	NodeID      string `json:"nodeId"`
+	Rights      string `json:"rights"`
+}
+
+// principalRights summarizes the read, write and deny ACLs by role, workspace and node: deny prevails over other rights.
+func principalRights(ctx context.Context, acls []*models.IdmACL) []*principalRight {
+	byKey := make(map[string]*principalRight)
+	labels := map[string]string{rest.RootGroupRole: "All users"}
+	var rights []*principalRight
+	for _, a := range acls {
+		if a.Action == nil {
+			continue
+		}
+		var flag string
+		switch a.Action.Name {
+		case "read":
+			flag = "r"
+		case "write":
+			flag = "w"
+		case "deny":
+			flag = "deny"
+		default:
+			continue
+		}
+		key := a.RoleID + "/" + a.WorkspaceID + "/" + a.NodeID
+		r, ok := byKey[key]
+		if !ok {
+			if _, known := labels[a.RoleID]; !known {
+				if role, e := sdkClient.GetRole(ctx, a.RoleID); e == nil {
+					labels[a.RoleID] = role.Label
+				} else {
+					labels[a.RoleID] = ""
+				}
+			}
+			r = &principalRight{RoleID: a.RoleID, RoleLabel: labels[a.RoleID], WorkspaceID: a.WorkspaceID, NodeID: a.NodeID}
+			byKey[key] = r
+			rights = append(rights, r)
+		}
+		if flag == "deny" {
+			r.Rights = "deny"
+		} else if r.Rights != "deny" {
+			r.Rights = normalizeRights(r.Rights + flag)
+		}
+	}
+	sort.SliceStable(rights, func(i, j int) bool { return rights[i].RoleLabel < rights[j].RoleLabel })
+	return rights
+}
+
+// effectiveRights merges the ACLs of the roles of a user, that are sorted by increasing priority: on each workspace
+// and node, the rights of the last role that defines some win, and deny prevails over the other rights of this role.
+func effectiveRights(roles []*canRole, acls []*models.IdmACL) []*principalRight {
+	byKey := make(map[string]map[string]string)
+	var keys []string
+	var nodes []*principalRight
+	for _, a := range acls {
+		if a.Action == nil {
+			continue
+		}
+		var flag string
+		switch a.Action.Name {
+		case "read":
+			flag = "r"
+		case "write":
+			flag = "w"
+		case "deny":
+			flag = "deny"
+		default:
+			continue
+		}
+		key := a.WorkspaceID + "/" + a.NodeID
+		if _, ok := byKey[key]; !ok {
+			byKey[key] = make(map[string]string)
+			keys = append(keys, key)
+			nodes = append(nodes, &principalRight{WorkspaceID: a.WorkspaceID, NodeID: a.NodeID})
+		}
+		byKey[key][a.RoleID] += flag
+	}
+
+	var rights []*principalRight
+	for i, key := range keys {
+		r := nodes[i]
+		for _, role := range roles {
+			flags, ok := byKey[key][role.ID]
+			if !ok {
+				continue
+			}
+			r.RoleID, r.RoleLabel = role.ID, role.Label
+			if strings.Contains(flags, "deny") {
+				r.Rights = "deny"
+			} else {
+				r.Rights = normalizeRights(flags)
+			}
+		}
+		if r.RoleID != "" {
+			rights = append(rights, r)
+		}
+	}
+	return rights
+}
+
+func normalizeRights(rights string) string {
+	var r string
+	if strings.Contains(rights, "r") {
+		r += "r"
+	}
+	if strings.Contains(rights, "w") {
+		r += "w"
+	}
+	return r
+}
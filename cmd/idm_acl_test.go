@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/pydio/cells-sdk-go/v4/models"
+
+	"github.com/pydio/cells-client/v4/rest"
+
+	// Silently import convey to ease implementation
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestEffectiveRights(t *testing.T) {
+	Convey("Test merge of the ACLs of all the roles of a user", t, func() {
+		// By increasing priority
+		roles := []*canRole{
+			{ID: rest.RootGroupRole, Label: "root group (all users)"},
+			{ID: "group-sales", Label: "group sales"},
+			{ID: "user-bob", Label: "user bob"},
+		}
+		rights := effectiveRights(roles, []*models.IdmACL{
+			canAcl(rest.RootGroupRole, "node-root", "read"),
+			canAcl(rest.RootGroupRole, "node-reports", "read"),
+			canAcl(rest.RootGroupRole, "node-reports", "write"),
+			canAcl("group-sales", "node-reports", "read"),
+			canAcl("group-sales", "node-secret", "deny"),
+			canAcl("user-bob", "node-secret", "read"),
+			canAcl("user-bob", "node-drafts", "deny"),
+			canAcl("user-bob", "node-drafts", "read"),
+		})
+		So(rights, ShouldHaveLength, 4)
+
+		So(rights[0].NodeID, ShouldEqual, "node-root")
+		So(rights[0].Rights, ShouldEqual, "r")
+		So(rights[0].RoleLabel, ShouldEqual, "root group (all users)")
+
+		// The role with the highest priority wins, even with fewer rights
+		So(rights[1].NodeID, ShouldEqual, "node-reports")
+		So(rights[1].Rights, ShouldEqual, "r")
+		So(rights[1].RoleID, ShouldEqual, "group-sales")
+
+		So(rights[2].NodeID, ShouldEqual, "node-secret")
+		So(rights[2].Rights, ShouldEqual, "r")
+		So(rights[2].RoleLabel, ShouldEqual, "user bob")
+
+		// Deny prevails over the other rights of the same role
+		So(rights[3].NodeID, ShouldEqual, "node-drafts")
+		So(rights[3].Rights, ShouldEqual, "deny")
+
+		Convey("ACLs of other roles are ignored", func() {
+			So(effectiveRights(roles, []*models.IdmACL{canAcl("user-alice", "node-root", "read")}), ShouldBeEmpty)
+		})
+	})
+}
@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"log"
 	"os"
 	"time"
 
@@ -12,6 +11,8 @@ import (
 
 	"github.com/pydio/cells-sdk-go/v4/client/acl_service"
 	"github.com/pydio/cells-sdk-go/v4/models"
+
+	"github.com/pydio/cells-client/v4/rest"
 )
 
 var (
@@ -59,7 +60,7 @@ Found 4 nodes at test:
 		apiClient := sdkClient.GetApiClient()
 
 		if len(listAclsByNodeIds) == 0 {
-			log.Fatal("Cannot list ACLs. Please precise *at least* one node UUID.")
+			rest.Log.Fatal("Cannot list ACLs. Please precise *at least* one node UUID.")
 		}
 
 		params := &acl_service.SearchAclsParams{
@@ -74,7 +75,7 @@ Found 4 nodes at test:
 		result, err := apiClient.ACLService.SearchAcls(params)
 		if err != nil {
 			fmt.Printf("could not list acls: %s\n", err.Error())
-			log.Fatal(err)
+			rest.Log.Fatal(err)
 		}
 
 		if len(result.Payload.ACLs) > 0 {
@@ -109,7 +110,7 @@ Found 4 nodes at test:
 					Context: ctx,
 				})
 				if er != nil {
-					log.Fatal("Could not delete ACL", u.ID, ":", er.Error())
+					rest.Log.Fatal("Could not delete ACL", u.ID, ":", er.Error())
 				} else {
 					fmt.Println(" - Removed ACL " + u.ID)
 					<-time.After(100 * time.Millisecond)
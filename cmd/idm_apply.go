@@ -0,0 +1,1213 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/google/uuid"
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/pydio/cells-sdk-go/v4/models"
+
+	"github.com/pydio/cells-client/v4/rest"
+)
+
+var (
+	idmConfigFile string
+	idmPrune      bool
+	idmForce      bool
+)
+
+// Built-in workspaces that are never pruned.
+var protectedWorkspaces = map[string]bool{"common-files": true, "personal-files": true}
+
+// Changes are applied in this order: creations and updates from groups to ACLs, then deletions the other way round.
+const (
+	orderGroups          = 0
+	orderRoles           = 100
+	orderUsers           = 200
+	orderWorkspaces      = 300
+	orderAcls            = 400
+	orderPruneAcls       = 500
+	orderPruneWorkspaces = 600
+	orderPruneUsers      = 700
+	orderPruneRoles      = 800
+	orderPruneGroups     = 1000
+)
+
+const idmConfigDoc = `
+  The file declares the following sections, that are all optional:
+
+  groups:
+    - path: /org/team             # Parent groups are implicitly created
+      displayName: The Team
+  roles:
+    - label: Project managers     # Roles are matched by UUID if given, by label otherwise
+      uuid: project-managers
+      autoApply: [standard]
+  users:
+    - login: alice
+      email: alice@example.com
+      displayName: Alice
+      groupPath: /org/team        # Users are created in the root group if omitted
+      profile: standard
+      roles: [Project managers]   # Omit to leave the assigned roles untouched
+      locked: false
+      password: ...               # Only used upon creation, a random one is generated otherwise
+  workspaces:
+    - slug: acme
+      label: ACME
+      description: ACME project
+      roots: [pydiods1:/projects/acme]
+      defaultRights: none         # r, rw or none
+      attributes: {ALLOW_SYNC: true}
+  acls:
+    - path: acme/reports          # Starts with the slug of the workspace
+      group: /org/team            # Or user: <login> or role: <label or UUID>
+      rights: [read, write]       # Or [deny]
+
+  Values that are omitted are not managed. With --prune, the users, groups, roles and admin workspaces that are
+  not declared are deleted, as well as the ACLs defined in the declared workspaces that are not declared.
+  Only the roles with a generated UUID are deleted: built-in roles such as ROOT_GROUP, ADMINS or MINISITE,
+  and more generally roles with a custom identifier, are kept. The current user, the groups that contain
+  kept users and the built-in workspaces are never deleted either.
+`
+
+var idmPlan = &cobra.Command{
+	Use:   "plan",
+	Short: "Show the changes needed to match an access configuration file",
+	Long: `
+DESCRIPTION
+
+  Compare the users, groups, roles, workspaces and ACLs declared in a YAML file with the live state of the server,
+  and show the changes that the apply command would perform. Nothing is modified.
+` + idmConfigDoc + `
+EXAMPLES
+
+  $ ` + os.Args[0] + ` idm plan -f access.yaml
+  $ ` + os.Args[0] + ` idm plan -f access.yaml --prune
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		p := newIdmPlanner(cmd.Context(), idmConfigFile, idmPrune)
+		p.print()
+	},
+}
+
+var idmApply = &cobra.Command{
+	Use:   "apply",
+	Short: "Apply an access configuration file",
+	Long: `
+DESCRIPTION
+
+  Compare the users, groups, roles, workspaces and ACLs declared in a YAML file with the live state of the server,
+  show the plan and, after confirmation, apply the changes in dependency order.
+` + idmConfigDoc + `
+EXAMPLES
+
+  $ ` + os.Args[0] + ` idm apply -f access.yaml
+  $ ` + os.Args[0] + ` idm apply -f access.yaml --prune --force
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		p := newIdmPlanner(ctx, idmConfigFile, idmPrune)
+		p.print()
+		if len(p.changes) == 0 || !confirmOrAbort(idmForce) {
+			return
+		}
+		sort.SliceStable(p.changes, func(i, j int) bool { return p.changes[i].order < p.changes[j].order })
+		for _, c := range p.changes {
+			if err := c.apply(ctx); err != nil {
+				rest.Log.Fatalf("Could not %s %s %s: %s\n", c.op, c.kind, c.name, err.Error())
+			}
+			fmt.Printf("%s %s: %sd\n", c.kind, c.name, c.op)
+		}
+		fmt.Println(promptui.IconGood, "All changes have been applied")
+	},
+}
+
+func init() {
+	for _, c := range []*cobra.Command{idmPlan, idmApply} {
+		flags := c.Flags()
+		flags.StringVarP(&idmConfigFile, "file", "f", "", "Path to the YAML access configuration file")
+		flags.BoolVar(&idmPrune, "prune", false, "Delete the objects that are not declared in the file")
+		_ = c.MarkFlagRequired("file")
+	}
+	idmApply.Flags().BoolVar(&idmForce, "force", false, "Do not ask for user approval")
+	idmCmd.AddCommand(idmPlan, idmApply)
+}
+
+type accessConfig struct {
+	Groups     []*accessGroup     `yaml:"groups"`
+	Roles      []*accessRole      `yaml:"roles"`
+	Users      []*accessUser      `yaml:"users"`
+	Workspaces []*accessWorkspace `yaml:"workspaces"`
+	Acls       []*accessAcl       `yaml:"acls"`
+}
+
+type accessGroup struct {
+	Path        string `yaml:"path"`
+	DisplayName string `yaml:"displayName"`
+}
+
+type accessRole struct {
+	UUID      string   `yaml:"uuid"`
+	Label     string   `yaml:"label"`
+	AutoApply []string `yaml:"autoApply"`
+}
+
+type accessUser struct {
+	Login       string   `yaml:"login"`
+	Email       string   `yaml:"email"`
+	DisplayName string   `yaml:"displayName"`
+	GroupPath   string   `yaml:"groupPath"`
+	Profile     string   `yaml:"profile"`
+	Roles       []string `yaml:"roles"`
+	Locked      *bool    `yaml:"locked"`
+	Password    string   `yaml:"password"`
+}
+
+type accessWorkspace struct {
+	Slug          string                 `yaml:"slug"`
+	Label         string                 `yaml:"label"`
+	Description   string                 `yaml:"description"`
+	Roots         []string               `yaml:"roots"`
+	DefaultRights string                 `yaml:"defaultRights"`
+	Attributes    map[string]interface{} `yaml:"attributes"`
+}
+
+type accessAcl struct {
+	Path   string   `yaml:"path"`
+	User   string   `yaml:"user"`
+	Group  string   `yaml:"group"`
+	Role   string   `yaml:"role"`
+	Rights []string `yaml:"rights"`
+}
+
+func (a *accessAcl) principal() string {
+	switch {
+	case a.User != "":
+		return "user " + a.User
+	case a.Group != "":
+		return "group " + a.Group
+	default:
+		return "role " + a.Role
+	}
+}
+
+// planChange is a single creation, update or deletion, with the function that performs it.
+type planChange struct {
+	kind    string
+	name    string
+	op      string
+	details []string
+	order   int
+	apply   func(ctx context.Context) error
+}
+
+// idmPlanner computes the changes between the access configuration and the live state of the server.
+type idmPlanner struct {
+	ctx          context.Context
+	config       *accessConfig
+	prune        bool
+	currentLogin string
+
+	groups     map[string]*models.IdmUser
+	users      map[string]*models.IdmUser
+	roles      []*models.IdmRole
+	workspaces map[string]*models.IdmWorkspace
+
+	// Objects that are created by the plan, or whose roots change for workspaces
+	pendingGroups     map[string]bool
+	pendingUsers      map[string]bool
+	pendingRoles      map[string]bool
+	pendingWorkspaces map[string]bool
+	rolesChanged      bool
+
+	changes   []*planChange
+	unmanaged int
+}
+
+func newIdmPlanner(ctx context.Context, configFile string, prune bool) *idmPlanner {
+	config, err := loadAccessConfig(configFile)
+	if err != nil {
+		rest.Log.Fatal(err)
+	}
+	p := &idmPlanner{
+		ctx:               ctx,
+		config:            config,
+		prune:             prune,
+		currentLogin:      sdkClient.GetConfig().User,
+		groups:            make(map[string]*models.IdmUser),
+		users:             make(map[string]*models.IdmUser),
+		workspaces:        make(map[string]*models.IdmWorkspace),
+		pendingGroups:     make(map[string]bool),
+		pendingUsers:      make(map[string]bool),
+		pendingRoles:      make(map[string]bool),
+		pendingWorkspaces: make(map[string]bool),
+	}
+	if err = p.fetchLiveState(); err != nil {
+		rest.Log.Fatal(err)
+	}
+	for _, step := range []func() error{p.planGroups, p.planRoles, p.planUsers, p.planWorkspaces, p.planAcls} {
+		if err = step(); err != nil {
+			rest.Log.Fatal(err)
+		}
+	}
+	return p
+}
+
+func loadAccessConfig(configFile string) (*accessConfig, error) {
+	file, err := os.Open(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not open %s: %s", configFile, err.Error())
+	}
+	defer file.Close()
+	config := &accessConfig{}
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	if err = decoder.Decode(config); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %s", configFile, err.Error())
+	}
+
+	seen := make(map[string]bool)
+	unique := func(kind, key string) error {
+		if seen[kind+":"+key] {
+			return fmt.Errorf("%s %s is declared twice", kind, key)
+		}
+		seen[kind+":"+key] = true
+		return nil
+	}
+	for _, g := range config.Groups {
+		g.Path = "/" + strings.Trim(g.Path, "/")
+		if g.Path == "/" {
+			return nil, fmt.Errorf("groups must have a path, the root group cannot be declared")
+		}
+		if err = unique("group", g.Path); err != nil {
+			return nil, err
+		}
+	}
+	for _, r := range config.Roles {
+		if r.Label == "" {
+			return nil, fmt.Errorf("roles must have a label")
+		}
+		if err = unique("role", r.Label); err != nil {
+			return nil, err
+		}
+		for _, profile := range r.AutoApply {
+			if !validProfile(profile) {
+				return nil, fmt.Errorf("invalid profile %s in auto-applied profiles of role %s", profile, r.Label)
+			}
+		}
+	}
+	for _, u := range config.Users {
+		if u.Login == "" {
+			return nil, fmt.Errorf("users must have a login")
+		}
+		if err = unique("user", u.Login); err != nil {
+			return nil, err
+		}
+		if u.GroupPath != "" {
+			u.GroupPath = "/" + strings.Trim(u.GroupPath, "/")
+		}
+		if u.Profile != "" && !validProfile(u.Profile) {
+			return nil, fmt.Errorf("invalid profile %s for user %s", u.Profile, u.Login)
+		}
+	}
+	for _, w := range config.Workspaces {
+		if w.Slug == "" {
+			return nil, fmt.Errorf("workspaces must have a slug")
+		}
+		if err = unique("workspace", w.Slug); err != nil {
+			return nil, err
+		}
+		switch w.DefaultRights {
+		case "", "r", "rw", "none":
+		default:
+			return nil, fmt.Errorf("invalid default rights %s for workspace %s, they must be one of r, rw or none", w.DefaultRights, w.Slug)
+		}
+	}
+	for _, a := range config.Acls {
+		a.Path = strings.Trim(a.Path, "/")
+		count := 0
+		for _, v := range []string{a.User, a.Group, a.Role} {
+			if v != "" {
+				count++
+			}
+		}
+		if a.Path == "" || count != 1 {
+			return nil, fmt.Errorf("ACLs must have a path and exactly one of user, group or role")
+		}
+		if len(a.Rights) == 0 {
+			return nil, fmt.Errorf("ACL on %s for %s has no rights", a.Path, a.principal())
+		}
+		for _, r := range a.Rights {
+			switch r {
+			case "read", "write", "deny":
+			default:
+				return nil, fmt.Errorf("invalid right %s on %s, it must be one of read, write or deny", r, a.Path)
+			}
+		}
+		if err = unique("ACL", a.Path+" for "+a.principal()); err != nil {
+			return nil, err
+		}
+	}
+	return config, nil
+}
+
+func validProfile(profile string) bool {
+	switch profile {
+	case "standard", "shared", "admin":
+		return true
+	}
+	return false
+}
+
+func (p *idmPlanner) fetchLiveState() error {
+	groups, err := sdkClient.ListGroups(p.ctx, "/", true)
+	if err != nil {
+		return err
+	}
+	for _, g := range groups {
+		p.groups[rest.GroupFullPath(g)] = g
+	}
+	users, err := sdkClient.ListUsers(p.ctx, "/", true)
+	if err != nil {
+		return err
+	}
+	for _, u := range users {
+		p.users[u.Login] = u
+	}
+	roles, err := sdkClient.ListRoles(p.ctx)
+	if err != nil {
+		return err
+	}
+	for _, r := range roles {
+		if !r.IsTeam {
+			p.roles = append(p.roles, r)
+		}
+	}
+	workspaces, err := sdkClient.ListWorkspaces(p.ctx)
+	if err != nil {
+		return err
+	}
+	for _, ws := range workspaces {
+		p.workspaces[ws.Slug] = ws
+	}
+	return nil
+}
+
+func (p *idmPlanner) add(c *planChange) {
+	p.changes = append(p.changes, c)
+}
+
+// addPrune registers the deletion of an unmanaged object if the --prune flag is set, or only counts it otherwise.
+func (p *idmPlanner) addPrune(c *planChange) {
+	if p.prune {
+		p.add(c)
+	} else {
+		p.unmanaged++
+	}
+}
+
+func groupDepth(groupPath string) int {
+	return strings.Count(strings.Trim(groupPath, "/"), "/") + 1
+}
+
+func (p *idmPlanner) planGroups() error {
+	declared := make(map[string]*accessGroup)
+	desired := make(map[string]bool)
+	addWithAncestors := func(groupPath string) {
+		for g := groupPath; g != "/"; g = path.Dir(g) {
+			desired[g] = true
+		}
+	}
+	for _, g := range p.config.Groups {
+		declared[g.Path] = g
+		addWithAncestors(g.Path)
+	}
+	for _, u := range p.config.Users {
+		if u.GroupPath != "" {
+			addWithAncestors(u.GroupPath)
+		} else if live, ok := p.users[u.Login]; ok {
+			// The group is not managed but must be kept, as deleting a group also deletes its members
+			addWithAncestors(rest.GroupFullPath(live))
+		}
+	}
+	if current, ok := p.users[p.currentLogin]; ok {
+		addWithAncestors(rest.GroupFullPath(current))
+	}
+
+	var paths []string
+	for g := range desired {
+		paths = append(paths, g)
+	}
+	sort.Strings(paths)
+	for _, groupPath := range paths {
+		groupPath := groupPath
+		decl := declared[groupPath]
+		live, exists := p.groups[groupPath]
+		if !exists {
+			p.pendingGroups[groupPath] = true
+			c := &planChange{kind: "group", name: groupPath, op: "create", order: orderGroups + groupDepth(groupPath)}
+			displayName := ""
+			if decl != nil && decl.DisplayName != "" {
+				displayName = decl.DisplayName
+				c.details = append(c.details, "displayName: "+displayName)
+			}
+			c.apply = func(ctx context.Context) error {
+				_, err := sdkClient.CreateGroup(ctx, groupPath, displayName)
+				return err
+			}
+			p.add(c)
+			continue
+		}
+		if decl != nil && decl.DisplayName != "" && live.Attributes[rest.UserAttrDisplayName] != decl.DisplayName {
+			p.add(&planChange{
+				kind: "group", name: groupPath, op: "update", order: orderGroups + groupDepth(groupPath),
+				details: []string{fmt.Sprintf("displayName: %s -> %s", rest.ValueOr(live.Attributes[rest.UserAttrDisplayName], "-"), decl.DisplayName)},
+				apply: func(ctx context.Context) error {
+					if live.Attributes == nil {
+						live.Attributes = make(map[string]string)
+					}
+					live.Attributes[rest.UserAttrDisplayName] = decl.DisplayName
+					_, err := sdkClient.MoveGroup(ctx, live, groupPath)
+					return err
+				},
+			})
+		}
+	}
+
+	for groupPath, live := range p.groups {
+		if desired[groupPath] {
+			continue
+		}
+		live := live
+		p.addPrune(&planChange{
+			kind: "group", name: groupPath, op: "delete", order: orderPruneGroups - groupDepth(groupPath),
+			apply: func(ctx context.Context) error { return sdkClient.DeleteGroup(ctx, live) },
+		})
+	}
+	return nil
+}
+
+// liveRole finds the live role that corresponds to the passed reference, either a UUID or a label.
+func (p *idmPlanner) liveRole(ref string) (*models.IdmRole, bool) {
+	r, err := rest.MatchRole(p.roles, ref)
+	return r, err == nil
+}
+
+func (p *idmPlanner) planRoles() error {
+	matched := make(map[string]bool)
+	for _, decl := range p.config.Roles {
+		decl := decl
+		var live *models.IdmRole
+		if decl.UUID != "" {
+			for _, r := range p.roles {
+				if r.UUID == decl.UUID {
+					live = r
+				}
+			}
+		} else if r, err := rest.MatchRole(p.roles, decl.Label); err == nil {
+			live = r
+		} else if !rest.IsNotFound(err) {
+			return err
+		}
+
+		if live == nil {
+			p.pendingRoles[decl.Label] = true
+			if decl.UUID != "" {
+				p.pendingRoles[decl.UUID] = true
+			}
+			c := &planChange{kind: "role", name: decl.Label, op: "create", order: orderRoles}
+			if len(decl.AutoApply) > 0 {
+				c.details = append(c.details, "autoApply: "+strings.Join(decl.AutoApply, ", "))
+			}
+			c.apply = func(ctx context.Context) error {
+				id := decl.UUID
+				if id == "" {
+					id = uuid.New().String()
+				}
+				_, err := sdkClient.PutRole(ctx, &models.IdmRole{UUID: id, Label: decl.Label, AutoApplies: decl.AutoApply})
+				p.rolesChanged = true
+				return err
+			}
+			p.add(c)
+			continue
+		}
+
+		matched[live.UUID] = true
+		var details []string
+		if live.Label != decl.Label {
+			details = append(details, fmt.Sprintf("label: %s -> %s", live.Label, decl.Label))
+		}
+		if decl.AutoApply != nil && !sameStrings(live.AutoApplies, decl.AutoApply) {
+			details = append(details, fmt.Sprintf("autoApply: [%s] -> [%s]", strings.Join(live.AutoApplies, ", "), strings.Join(decl.AutoApply, ", ")))
+		}
+		if len(details) > 0 {
+			p.add(&planChange{
+				kind: "role", name: decl.Label, op: "update", order: orderRoles, details: details,
+				apply: func(ctx context.Context) error {
+					live.Label = decl.Label
+					if decl.AutoApply != nil {
+						live.AutoApplies = decl.AutoApply
+					}
+					_, err := sdkClient.PutRole(ctx, live)
+					p.rolesChanged = true
+					return err
+				},
+			})
+		}
+	}
+
+	for _, live := range p.roles {
+		if matched[live.UUID] || !prunableRole(live) {
+			continue
+		}
+		live := live
+		p.addPrune(&planChange{
+			kind: "role", name: live.Label, op: "delete", order: orderPruneRoles,
+			apply: func(ctx context.Context) error { return sdkClient.DeleteRole(ctx, live.UUID) },
+		})
+	}
+	return nil
+}
+
+// prunableRole returns true for the roles that can be deleted with --prune: built-in roles use fixed identifiers,
+// whereas roles that are created by the tools of the server and by this command have a generated UUID.
+func prunableRole(role *models.IdmRole) bool {
+	_, err := uuid.Parse(role.UUID)
+	return err == nil && !role.IsTeam
+}
+
+// resolveRoles finds the roles with the passed references when applying changes, including the roles that have just been created.
+func (p *idmPlanner) resolveRoles(ctx context.Context, refs []string) ([]*models.IdmRole, error) {
+	if p.rolesChanged {
+		roles, err := sdkClient.ListRoles(ctx)
+		if err != nil {
+			return nil, err
+		}
+		p.roles, p.rolesChanged = roles, false
+	}
+	var roles []*models.IdmRole
+	for _, ref := range refs {
+		r, err := rest.MatchRole(p.roles, ref)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, r)
+	}
+	return roles, nil
+}
+
+func (p *idmPlanner) planUsers() error {
+	for _, decl := range p.config.Users {
+		decl := decl
+		for _, ref := range decl.Roles {
+			if _, ok := p.liveRole(ref); !ok && !p.pendingRoles[ref] {
+				return fmt.Errorf("unknown role %s for user %s", ref, decl.Login)
+			}
+		}
+
+		live, exists := p.users[decl.Login]
+		if !exists {
+			p.pendingUsers[decl.Login] = true
+			c := &planChange{kind: "user", name: decl.Login, op: "create", order: orderUsers}
+			c.details = append(c.details, "group: "+rest.ValueOr(decl.GroupPath, "/"))
+			for _, a := range [][2]string{{"email", decl.Email}, {"displayName", decl.DisplayName}, {"profile", decl.Profile}} {
+				if a[1] != "" {
+					c.details = append(c.details, a[0]+": "+a[1])
+				}
+			}
+			if len(decl.Roles) > 0 {
+				c.details = append(c.details, "roles: "+strings.Join(decl.Roles, ", "))
+			}
+			if decl.Locked != nil && *decl.Locked {
+				c.details = append(c.details, "locked")
+			}
+			c.apply = func(ctx context.Context) error {
+				user := &models.IdmUser{
+					Login:      decl.Login,
+					GroupPath:  rest.ValueOr(decl.GroupPath, "/"),
+					Attributes: map[string]string{rest.UserAttrProfile: rest.ValueOr(decl.Profile, "standard")},
+					Password:   decl.Password,
+				}
+				setAccessUserAttributes(user, decl)
+				roles, err := p.resolveRoles(ctx, decl.Roles)
+				if err != nil {
+					return err
+				}
+				user.Roles = roles
+				if user.Password == "" {
+					if user.Password, err = generatePassword(16); err != nil {
+						return err
+					}
+					rest.SetUserLock(user, "pass_change", true)
+					fmt.Printf("Generated password for user %s: %s\n", decl.Login, user.Password)
+				}
+				if decl.Locked != nil && *decl.Locked {
+					rest.SetUserLock(user, rest.UserLockLogout, true)
+				}
+				_, err = sdkClient.PutUser(ctx, user)
+				return err
+			}
+			p.add(c)
+			continue
+		}
+
+		var details []string
+		if decl.GroupPath != "" && rest.GroupFullPath(live) != decl.GroupPath {
+			details = append(details, fmt.Sprintf("group: %s -> %s", rest.GroupFullPath(live), decl.GroupPath))
+		}
+		for _, a := range [][3]string{
+			{"email", rest.UserAttrEmail, decl.Email},
+			{"displayName", rest.UserAttrDisplayName, decl.DisplayName},
+			{"profile", rest.UserAttrProfile, decl.Profile},
+		} {
+			if a[2] != "" && live.Attributes[a[1]] != a[2] {
+				details = append(details, fmt.Sprintf("%s: %s -> %s", a[0], rest.ValueOr(live.Attributes[a[1]], "-"), a[2]))
+			}
+		}
+		if decl.Roles != nil {
+			var current []string
+			has := make(map[string]bool)
+			for _, r := range rest.AssignedRoles(live) {
+				current = append(current, r.Label)
+				has[r.UUID] = true
+			}
+			changed := false
+			wanted := make(map[string]bool)
+			for _, ref := range decl.Roles {
+				if r, ok := p.liveRole(ref); ok && has[r.UUID] {
+					wanted[r.UUID] = true
+				} else {
+					changed = true
+				}
+			}
+			if changed || len(wanted) != len(has) {
+				details = append(details, fmt.Sprintf("roles: [%s] -> [%s]", strings.Join(current, ", "), strings.Join(decl.Roles, ", ")))
+			}
+		}
+		locked := false
+		for _, l := range rest.UserLocks(live) {
+			if l == rest.UserLockLogout {
+				locked = true
+			}
+		}
+		if decl.Locked != nil && *decl.Locked != locked {
+			details = append(details, fmt.Sprintf("locked: %t -> %t", locked, *decl.Locked))
+		}
+		if len(details) == 0 {
+			continue
+		}
+		p.add(&planChange{
+			kind: "user", name: decl.Login, op: "update", order: orderUsers, details: details,
+			apply: func(ctx context.Context) error {
+				if decl.GroupPath != "" {
+					live.GroupPath = decl.GroupPath
+				}
+				if live.Attributes == nil {
+					live.Attributes = make(map[string]string)
+				}
+				if decl.Profile != "" {
+					live.Attributes[rest.UserAttrProfile] = decl.Profile
+				}
+				setAccessUserAttributes(live, decl)
+				if decl.Roles != nil {
+					roles, err := p.resolveRoles(ctx, decl.Roles)
+					if err != nil {
+						return err
+					}
+					live.Roles = rest.WithAssignedRoles(live, roles)
+				}
+				if decl.Locked != nil {
+					rest.SetUserLock(live, rest.UserLockLogout, *decl.Locked)
+				}
+				_, err := sdkClient.PutUser(ctx, live)
+				return err
+			},
+		})
+	}
+
+	declared := make(map[string]bool)
+	for _, u := range p.config.Users {
+		declared[u.Login] = true
+	}
+	for login, live := range p.users {
+		if declared[login] || login == p.currentLogin {
+			continue
+		}
+		live := live
+		p.addPrune(&planChange{
+			kind: "user", name: login, op: "delete", order: orderPruneUsers,
+			apply: func(ctx context.Context) error { return sdkClient.DeleteUser(ctx, live) },
+		})
+	}
+	return nil
+}
+
+func setAccessUserAttributes(user *models.IdmUser, decl *accessUser) {
+	if decl.Email != "" {
+		user.Attributes[rest.UserAttrEmail] = decl.Email
+	}
+	if decl.DisplayName != "" {
+		user.Attributes[rest.UserAttrDisplayName] = decl.DisplayName
+	}
+}
+
+func (p *idmPlanner) planWorkspaces() error {
+	for _, decl := range p.config.Workspaces {
+		decl := decl
+		rights := decl.DefaultRights
+		if rights == "none" {
+			rights = ""
+		}
+
+		live, exists := p.workspaces[decl.Slug]
+		if !exists {
+			if decl.Label == "" || len(decl.Roots) == 0 {
+				return fmt.Errorf("workspace %s must have a label and roots to be created", decl.Slug)
+			}
+			p.pendingWorkspaces[decl.Slug] = true
+			c := &planChange{kind: "workspace", name: decl.Slug, op: "create", order: orderWorkspaces}
+			c.details = append(c.details, "label: "+decl.Label, "roots: "+strings.Join(decl.Roots, ", "))
+			if rights != "" {
+				c.details = append(c.details, "defaultRights: "+rights)
+			}
+			c.apply = func(ctx context.Context) error {
+				ws := &models.IdmWorkspace{
+					Slug:        decl.Slug,
+					Label:       decl.Label,
+					Description: decl.Description,
+					Scope:       models.NewIdmWorkspaceScope(models.IdmWorkspaceScopeADMIN),
+				}
+				if err := applyAccessWorkspace(ctx, ws, decl, rights, true); err != nil {
+					return err
+				}
+				created, err := sdkClient.PutWorkspace(ctx, ws)
+				if err != nil {
+					return err
+				}
+				sdkClient.InvalidateMeta()
+				if rights != "" {
+					return sdkClient.SetWorkspaceDefaultRights(ctx, created, rights)
+				}
+				return nil
+			}
+			p.add(c)
+			continue
+		}
+
+		var details []string
+		if decl.Label != "" && decl.Label != live.Label {
+			details = append(details, fmt.Sprintf("label: %s -> %s", live.Label, decl.Label))
+		}
+		if decl.Description != "" && decl.Description != live.Description {
+			details = append(details, fmt.Sprintf("description: %s -> %s", rest.ValueOr(live.Description, "-"), decl.Description))
+		}
+		rootsChanged := false
+		if decl.Roots != nil {
+			var current, wanted []string
+			for _, id := range rest.WorkspaceRootUuids(live) {
+				if n, ok := live.RootNodes[id]; ok && n != nil {
+					current = append(current, strings.Trim(n.Path, "/"))
+				}
+			}
+			for _, r := range decl.Roots {
+				wanted = append(wanted, workspaceRootPath(r))
+			}
+			if !sameStrings(current, wanted) {
+				rootsChanged = true
+				p.pendingWorkspaces[decl.Slug] = true
+				details = append(details, fmt.Sprintf("roots: [%s] -> [%s]", strings.Join(current, ", "), strings.Join(wanted, ", ")))
+			}
+		}
+		attributes := rest.WorkspaceAttributes(live)
+		rightsChanged := false
+		if decl.DefaultRights != "" {
+			current, _ := attributes[rest.WorkspaceAttrDefaultRights].(string)
+			if current != rights {
+				rightsChanged = true
+				details = append(details, fmt.Sprintf("defaultRights: %s -> %s", rest.ValueOr(current, "none"), rest.ValueOr(rights, "none")))
+			}
+		}
+		var keys []string
+		for k := range decl.Attributes {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if !jsonEqual(attributes[k], decl.Attributes[k]) {
+				before, _ := json.Marshal(attributes[k])
+				after, _ := json.Marshal(decl.Attributes[k])
+				details = append(details, fmt.Sprintf("attributes.%s: %s -> %s", k, before, after))
+			}
+		}
+		if len(details) == 0 {
+			continue
+		}
+		p.add(&planChange{
+			kind: "workspace", name: decl.Slug, op: "update", order: orderWorkspaces, details: details,
+			apply: func(ctx context.Context) error {
+				if decl.Label != "" {
+					live.Label = decl.Label
+				}
+				if decl.Description != "" {
+					live.Description = decl.Description
+				}
+				if err := applyAccessWorkspace(ctx, live, decl, rights, rootsChanged); err != nil {
+					return err
+				}
+				updated, err := sdkClient.PutWorkspace(ctx, live)
+				if err != nil {
+					return err
+				}
+				sdkClient.InvalidateMeta()
+				if rightsChanged || rootsChanged {
+					r, _ := rest.WorkspaceAttributes(updated)[rest.WorkspaceAttrDefaultRights].(string)
+					return sdkClient.SetWorkspaceDefaultRights(ctx, updated, r)
+				}
+				return nil
+			},
+		})
+	}
+
+	for slug, live := range p.workspaces {
+		if protectedWorkspaces[slug] {
+			continue
+		}
+		declared := false
+		for _, w := range p.config.Workspaces {
+			declared = declared || w.Slug == slug
+		}
+		if declared {
+			continue
+		}
+		slug := slug
+		p.addPrune(&planChange{
+			kind: "workspace", name: live.Slug, op: "delete", order: orderPruneWorkspaces,
+			apply: func(ctx context.Context) error { return sdkClient.DeleteWorkspace(ctx, slug) },
+		})
+	}
+	return nil
+}
+
+// applyAccessWorkspace sets the declared attributes, default rights and, if required, roots on the workspace.
+func applyAccessWorkspace(ctx context.Context, ws *models.IdmWorkspace, decl *accessWorkspace, rights string, withRoots bool) error {
+	attributes := rest.WorkspaceAttributes(ws)
+	for k, v := range decl.Attributes {
+		attributes[k] = v
+	}
+	if decl.DefaultRights != "" {
+		attributes[rest.WorkspaceAttrDefaultRights] = rights
+	}
+	data, err := json.Marshal(attributes)
+	if err != nil {
+		return fmt.Errorf("invalid attributes: %s", err.Error())
+	}
+	ws.Attributes = string(data)
+	if withRoots {
+		return setWorkspaceRoots(ctx, ws, decl.Roots)
+	}
+	return nil
+}
+
+// aclRoleID finds the live role that corresponds to the principal of the ACL, pending is true if the principal
+// is only created by the plan.
+func (p *idmPlanner) aclRoleID(a *accessAcl) (roleID string, pending bool, err error) {
+	switch {
+	case a.User != "":
+		if u, ok := p.users[a.User]; ok {
+			return u.UUID, false, nil
+		}
+		if p.pendingUsers[a.User] {
+			return "", true, nil
+		}
+		return "", false, fmt.Errorf("unknown user %s", a.User)
+	case a.Group != "":
+		groupPath := "/" + strings.Trim(a.Group, "/")
+		if g, ok := p.groups[groupPath]; ok {
+			return g.UUID, false, nil
+		}
+		if p.pendingGroups[groupPath] {
+			return "", true, nil
+		}
+		return "", false, fmt.Errorf("unknown group %s", groupPath)
+	default:
+		if r, ok := p.liveRole(a.Role); ok {
+			return r.UUID, false, nil
+		}
+		if p.pendingRoles[a.Role] {
+			return "", true, nil
+		}
+		return "", false, fmt.Errorf("unknown role %s", a.Role)
+	}
+}
+
+// resolveAclRole finds the role that corresponds to the principal of the ACL when applying changes.
+func (p *idmPlanner) resolveAclRole(ctx context.Context, a *accessAcl) (string, error) {
+	switch {
+	case a.User != "":
+		u, err := sdkClient.FindUser(ctx, a.User)
+		if err != nil {
+			return "", err
+		}
+		return u.UUID, nil
+	case a.Group != "":
+		g, err := sdkClient.FindGroup(ctx, "/"+strings.Trim(a.Group, "/"))
+		if err != nil {
+			return "", err
+		}
+		return g.UUID, nil
+	default:
+		roles, err := p.resolveRoles(ctx, []string{a.Role})
+		if err != nil {
+			return "", err
+		}
+		return roles[0].UUID, nil
+	}
+}
+
+func (p *idmPlanner) planAcls() error {
+	managed := make(map[string]bool)
+	for _, decl := range p.config.Acls {
+		decl := decl
+		name := decl.Path + " for " + decl.principal()
+		slug, _, _ := strings.Cut(decl.Path, "/")
+		roleID, pending, err := p.aclRoleID(decl)
+		if err != nil {
+			return fmt.Errorf("invalid ACL on %s: %s", name, err.Error())
+		}
+		if pending || p.pendingWorkspaces[slug] {
+			// The principal or the workspace does not exist yet, the ACL is resolved when applying
+			p.add(&planChange{
+				kind: "acl", name: name, op: "create", order: orderAcls,
+				details: []string{"rights: " + strings.Join(decl.Rights, ", ")},
+				apply: func(ctx context.Context) error {
+					id, err := p.resolveAclRole(ctx, decl)
+					if err != nil {
+						return err
+					}
+					nodeUUID, wsUUID, err := resolveAclNode(ctx, decl.Path)
+					if err != nil {
+						return err
+					}
+					return syncAcls(ctx, id, nodeUUID, wsUUID, decl.Rights, nil)
+				},
+			})
+			continue
+		}
+
+		nodeUUID, wsUUID, err := resolveAclNode(p.ctx, decl.Path)
+		if err != nil {
+			return fmt.Errorf("invalid ACL on %s: %s", name, err.Error())
+		}
+		managed[roleID+"/"+wsUUID+"/"+nodeUUID] = true
+		existing, err := sdkClient.SearchAcls(p.ctx, &models.IdmACLSingleQuery{
+			RoleIDs:      []string{roleID},
+			NodeIDs:      []string{nodeUUID},
+			WorkspaceIDs: []string{wsUUID},
+		})
+		if err != nil {
+			return fmt.Errorf("could not list ACLs on %s: %s", decl.Path, err.Error())
+		}
+		var current []string
+		var currentAcls []*models.IdmACL
+		for _, a := range existing {
+			if a.Action != nil && (a.Action.Name == "read" || a.Action.Name == "write" || a.Action.Name == "deny") {
+				current = append(current, a.Action.Name)
+				currentAcls = append(currentAcls, a)
+			}
+		}
+		if sameStrings(current, decl.Rights) {
+			continue
+		}
+		op := "update"
+		if len(current) == 0 {
+			op = "create"
+		}
+		p.add(&planChange{
+			kind: "acl", name: name, op: op, order: orderAcls,
+			details: []string{fmt.Sprintf("rights: [%s] -> [%s]", strings.Join(current, ", "), strings.Join(decl.Rights, ", "))},
+			apply: func(ctx context.Context) error {
+				return syncAcls(ctx, roleID, nodeUUID, wsUUID, decl.Rights, currentAcls)
+			},
+		})
+	}
+
+	// Unmanaged ACLs are only looked for in the declared workspaces, default rights are managed by the workspaces.
+	// Workspaces whose roots change are skipped, as their declared ACLs are only resolved when applying.
+	for _, decl := range p.config.Workspaces {
+		ws, ok := p.workspaces[decl.Slug]
+		if !ok || p.pendingWorkspaces[decl.Slug] {
+			continue
+		}
+		acls, err := sdkClient.SearchAcls(p.ctx, &models.IdmACLSingleQuery{WorkspaceIDs: []string{ws.UUID}})
+		if err != nil {
+			return fmt.Errorf("could not list ACLs of workspace %s: %s", ws.Slug, err.Error())
+		}
+		byKey := make(map[string][]*models.IdmACL)
+		var keys []string
+		for _, a := range acls {
+			if a.Action == nil || a.RoleID == rest.RootGroupRole {
+				continue
+			}
+			switch a.Action.Name {
+			case "read", "write", "deny":
+			default:
+				continue
+			}
+			key := a.RoleID + "/" + a.WorkspaceID + "/" + a.NodeID
+			if managed[key] {
+				continue
+			}
+			if _, seen := byKey[key]; !seen {
+				keys = append(keys, key)
+			}
+			byKey[key] = append(byKey[key], a)
+		}
+		for _, key := range keys {
+			toDelete := byKey[key]
+			p.addPrune(&planChange{
+				kind: "acl", name: fmt.Sprintf("node %s in %s for role %s", toDelete[0].NodeID, ws.Slug, toDelete[0].RoleID), op: "delete", order: orderPruneAcls,
+				apply: func(ctx context.Context) error {
+					for _, a := range toDelete {
+						if err := sdkClient.DeleteAcl(ctx, a); err != nil {
+							return err
+						}
+					}
+					return nil
+				},
+			})
+		}
+	}
+	return nil
+}
+
+// syncAcls creates and deletes ACLs so that the role has exactly the passed rights on the node.
+func syncAcls(ctx context.Context, roleID, nodeUUID, wsUUID string, rights []string, current []*models.IdmACL) error {
+	if current == nil {
+		existing, err := sdkClient.SearchAcls(ctx, &models.IdmACLSingleQuery{
+			RoleIDs:      []string{roleID},
+			NodeIDs:      []string{nodeUUID},
+			WorkspaceIDs: []string{wsUUID},
+		})
+		if err != nil {
+			return err
+		}
+		current = existing
+	}
+	has := make(map[string]bool)
+	for _, a := range current {
+		if a.Action == nil {
+			continue
+		}
+		switch a.Action.Name {
+		case "read", "write", "deny":
+		default:
+			continue
+		}
+		if !containsString(rights, a.Action.Name) {
+			if err := sdkClient.DeleteAcl(ctx, a); err != nil {
+				return err
+			}
+		}
+		has[a.Action.Name] = true
+	}
+	for _, r := range rights {
+		if has[r] {
+			continue
+		}
+		acl := &models.IdmACL{
+			Action:      &models.IdmACLAction{Name: r, Value: "1"},
+			RoleID:      roleID,
+			NodeID:      nodeUUID,
+			WorkspaceID: wsUUID,
+		}
+		if err := sdkClient.PutAcl(ctx, acl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *idmPlanner) print() {
+	if len(p.changes) == 0 {
+		fmt.Println("No changes. The server matches the configuration.")
+	} else {
+		sort.SliceStable(p.changes, func(i, j int) bool {
+			if p.changes[i].order/100 != p.changes[j].order/100 {
+				return p.changes[i].order < p.changes[j].order
+			}
+			return p.changes[i].name < p.changes[j].name
+		})
+		counts := make(map[string]int)
+		fmt.Println("The following changes would be applied:")
+		fmt.Println()
+		for _, c := range p.changes {
+			counts[c.op]++
+			var symbol string
+			switch c.op {
+			case "create":
+				symbol = color.GreenString("+")
+			case "update":
+				symbol = color.YellowString("~")
+			default:
+				symbol = color.RedString("-")
+			}
+			fmt.Printf("  %s %s %s\n", symbol, c.kind, c.name)
+			for _, d := range c.details {
+				fmt.Printf("      %s\n", d)
+			}
+		}
+		fmt.Println()
+		fmt.Printf("Plan: %d to create, %d to update, %d to delete.\n", counts["create"], counts["update"], counts["delete"])
+	}
+	if p.unmanaged > 0 {
+		fmt.Printf("%d object(s) on the server are not declared in the configuration, use --prune to delete them.\n", p.unmanaged)
+	}
+}
+
+// sameStrings returns true if both slices contain the same values, regardless of their order.
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sa := append([]string{}, a...)
+	sb := append([]string{}, b...)
+	sort.Strings(sa)
+	sort.Strings(sb)
+	return reflect.DeepEqual(sa, sb)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonEqual compares two values once serialized to JSON, as attributes decoded from YAML and JSON have different types.
+func jsonEqual(a, b interface{}) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	var va, vb interface{}
+	_ = json.Unmarshal(ja, &va)
+	_ = json.Unmarshal(jb, &vb)
+	return reflect.DeepEqual(va, vb)
+}
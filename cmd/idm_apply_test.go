@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/pydio/cells-sdk-go/v4/models"
+
+	// Silently import convey to ease implementation
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func writeAccessConfig(t *testing.T, content string) string {
+	file := filepath.Join(t.TempDir(), "access.yaml")
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+// plannedChanges summarizes the changes of a plan, e.g. "create group /org/sales".
+func plannedChanges(p *idmPlanner) []string {
+	var summary []string
+	for _, c := range p.changes {
+		summary = append(summary, c.op+" "+c.kind+" "+c.name)
+	}
+	sort.Strings(summary)
+	return summary
+}
+
+func newTestPlanner(config *accessConfig, prune bool) *idmPlanner {
+	return &idmPlanner{
+		config:            config,
+		prune:             prune,
+		groups:            make(map[string]*models.IdmUser),
+		users:             make(map[string]*models.IdmUser),
+		workspaces:        make(map[string]*models.IdmWorkspace),
+		pendingGroups:     make(map[string]bool),
+		pendingUsers:      make(map[string]bool),
+		pendingRoles:      make(map[string]bool),
+		pendingWorkspaces: make(map[string]bool),
+	}
+}
+
+func TestLoadAccessConfig(t *testing.T) {
+	Convey("Test loading of the access configuration", t, func() {
+		Convey("Paths are normalized and omitted group paths are kept empty", func() {
+			config, err := loadAccessConfig(writeAccessConfig(t, `
+groups:
+  - path: org/sales/
+users:
+  - login: alice
+    groupPath: org/sales
+  - login: bob
+acls:
+  - path: /finance/reports/
+    group: /org/sales
+    rights: [read]
+`))
+			So(err, ShouldBeNil)
+			So(config.Groups[0].Path, ShouldEqual, "/org/sales")
+			So(config.Users[0].GroupPath, ShouldEqual, "/org/sales")
+			So(config.Users[1].GroupPath, ShouldEqual, "")
+			So(config.Acls[0].Path, ShouldEqual, "finance/reports")
+		})
+
+		Convey("Invalid configurations are rejected", func() {
+			for _, content := range []string{
+				"users:\n  - login: alice\n  - login: alice\n",
+				"users:\n  - login: alice\n    profile: superuser\n",
+				"groups:\n  - path: /\n",
+				"roles:\n  - uuid: 1234\n",
+				"workspaces:\n  - slug: finance\n    defaultRights: w\n",
+				"acls:\n  - path: finance\n    user: alice\n    group: /org\n    rights: [read]\n",
+				"acls:\n  - path: finance\n    user: alice\n    rights: [execute]\n",
+				"unknown: true\n",
+			} {
+				_, err := loadAccessConfig(writeAccessConfig(t, content))
+				So(err, ShouldNotBeNil)
+			}
+		})
+	})
+}
+
+func TestPlanGroups(t *testing.T) {
+	Convey("Test plan of the groups", t, func() {
+		config := &accessConfig{
+			Groups: []*accessGroup{{Path: "/org/sales", DisplayName: "Sales"}},
+			Users: []*accessUser{
+				{Login: "alice", GroupPath: "/org/marketing"},
+				{Login: "bob"},
+			},
+		}
+		p := newTestPlanner(config, true)
+		p.currentLogin = "admin"
+		for _, g := range []string{"/org", "/org/sales", "/legacy", "/admins", "/obsolete"} {
+			p.groups[g] = &models.IdmUser{IsGroup: true, GroupPath: g, Attributes: map[string]string{}}
+		}
+		p.users["bob"] = &models.IdmUser{Login: "bob", GroupPath: "/legacy"}
+		p.users["admin"] = &models.IdmUser{Login: "admin", GroupPath: "/admins"}
+
+		So(p.planGroups(), ShouldBeNil)
+		// The groups of bob, whose group is not managed, and of the current user are kept
+		So(plannedChanges(p), ShouldResemble, []string{
+			"create group /org/marketing",
+			"delete group /obsolete",
+			"update group /org/sales",
+		})
+		So(p.pendingGroups["/org/marketing"], ShouldBeTrue)
+
+		Convey("Unmanaged groups are only counted without --prune", func() {
+			p := newTestPlanner(config, false)
+			p.groups["/obsolete"] = &models.IdmUser{IsGroup: true, GroupPath: "/obsolete"}
+			So(p.planGroups(), ShouldBeNil)
+			So(plannedChanges(p), ShouldResemble, []string{
+				"create group /org",
+				"create group /org/marketing",
+				"create group /org/sales",
+			})
+			So(p.unmanaged, ShouldEqual, 1)
+		})
+	})
+}
+
+func TestPlanRoles(t *testing.T) {
+	Convey("Test plan of the roles", t, func() {
+		config := &accessConfig{
+			Roles: []*accessRole{
+				{Label: "Editors", AutoApply: []string{"standard"}},
+				{Label: "Reviewers"},
+			},
+		}
+		p := newTestPlanner(config, true)
+		p.roles = []*models.IdmRole{
+			{UUID: "ADMINS", Label: "Administrators"},
+			{UUID: "0c2d7a4e-6f3b-4f0e-9d1b-2a7e5c8b9f10", Label: "Editors"},
+			{UUID: "5b8e1f3a-2c4d-4e6f-8a9b-0c1d2e3f4a5b", Label: "Old role"},
+		}
+
+		So(p.planRoles(), ShouldBeNil)
+		// Built-in roles are never pruned
+		So(plannedChanges(p), ShouldResemble, []string{
+			"create role Reviewers",
+			"delete role Old role",
+			"update role Editors",
+		})
+		So(p.pendingRoles["Reviewers"], ShouldBeTrue)
+	})
+}
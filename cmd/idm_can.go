@@ -0,0 +1,274 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/pydio/cells-sdk-go/v4/models"
+
+	"github.com/pydio/cells-client/v4/rest"
+)
+
+var (
+	canUser   string
+	canPath   string
+	canFormat string
+)
+
+var idmCan = &cobra.Command{
+	Use:   "can",
+	Short: "Explain the effective permissions of a user on a path",
+	Long: `
+DESCRIPTION
+
+  Compute whether a user can read and write at the given path, and explain how the decision is made.
+  The path starts with the slug of a workspace: permissions are evaluated through this workspace.
+
+  The roles of the user are applied by increasing priority: the root group role that is inherited by all users,
+  the roles of the parent groups, the explicitly assigned roles and finally the own role of the user.
+  For each node from the root of the workspace to the path, the ACLs of the role with the highest priority
+  that defines rights on this node win. Then, a deny on any node along the path prevails, otherwise
+  the rights that are defined on the deepest node apply.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` idm can --user bob --path cells://common-files/projects/acme
+  $ ` + os.Args[0] + ` idm can --user bob --path acme/reports --format json
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		if canUser == "" || canPath == "" {
+			rest.Log.Fatalln("The --user and --path flags are required")
+		}
+		user, err := sdkClient.FindUser(ctx, canUser)
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		p := strings.Trim(trimRemotePrefix(canPath), "/")
+		slug, rel, _ := strings.Cut(p, "/")
+		ws, err := sdkClient.FindWorkspace(ctx, slug)
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+
+		var roles []*canRole
+		var roleIDs []string
+		for _, r := range user.Roles {
+			roles = append(roles, &canRole{ID: r.UUID, Label: canRoleLabel(user, r)})
+			roleIDs = append(roleIDs, r.UUID)
+		}
+		if len(roles) == 0 {
+			rest.Log.Fatalf("Could not retrieve the roles of user %s\n", user.Login)
+		}
+
+		// Nodes from the root of the workspace to the path. Workspaces with several roots have no node for their slug.
+		var steps []*canStep
+		prefix := slug
+		if len(rest.WorkspaceRootUuids(ws)) == 1 {
+			steps = append(steps, &canStep{Path: slug})
+		}
+		if rel != "" {
+			for _, segment := range strings.Split(rel, "/") {
+				prefix = path.Join(prefix, segment)
+				steps = append(steps, &canStep{Path: prefix})
+			}
+		}
+		if len(steps) == 0 {
+			rest.Log.Fatalf("Workspace %s has several roots, please give a path inside one of them\n", ws.Slug)
+		}
+		var nodeIDs []string
+		for _, s := range steps {
+			nodeUUID, wsUUID, e := resolveAclNode(ctx, s.Path)
+			if e != nil {
+				rest.Log.Fatal(e)
+			}
+			if wsUUID != ws.UUID {
+				rest.Log.Fatalf("%s is not in workspace %s\n", s.Path, ws.Slug)
+			}
+			s.NodeID = nodeUUID
+			nodeIDs = append(nodeIDs, nodeUUID)
+		}
+
+		acls, err := sdkClient.SearchAcls(ctx, &models.IdmACLSingleQuery{RoleIDs: roleIDs, NodeIDs: nodeIDs})
+		if err != nil {
+			rest.Log.Fatalf("Could not list ACLs of user %s: %s\n", user.Login, err.Error())
+		}
+		result := evaluateCan(user, ws, roles, steps, acls)
+
+		switch canFormat {
+		case "json":
+			data, _ := json.MarshalIndent(result, "", "  ")
+			fmt.Printf("%s\n", data)
+		case "table":
+			printCanResult(result)
+		default:
+			cmd.Println("invalid output format, it must be either json or table")
+		}
+	},
+}
+
+func init() {
+	flags := idmCan.Flags()
+	flags.StringVar(&canUser, "user", "", "Login of the user")
+	flags.StringVar(&canPath, "path", "", "Path to check, starting with the slug of the workspace")
+	flags.StringVar(&canFormat, "format", "table", "Output format table|json")
+	_ = idmCan.RegisterFlagCompletionFunc("path", completeRemotePaths)
+	idmCmd.AddCommand(idmCan)
+}
+
+type canRole struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+}
+
+// canStep holds the rights that apply on a node along the path, and the role they come from.
+type canStep struct {
+	Path   string `json:"path"`
+	NodeID string `json:"nodeId"`
+	Rights string `json:"rights,omitempty"`
+	Role   string `json:"role,omitempty"`
+}
+
+type canDecision struct {
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason"`
+}
+
+type canResult struct {
+	User      string       `json:"user"`
+	Workspace string       `json:"workspace"`
+	Path      string       `json:"path"`
+	Locked    bool         `json:"locked"`
+	Roles     []*canRole   `json:"roles"`
+	Steps     []*canStep   `json:"steps"`
+	Read      *canDecision `json:"read"`
+	Write     *canDecision `json:"write"`
+}
+
+// evaluateCan merges the ACLs of the roles on each node, then walks the path to take the read and write decisions.
+func evaluateCan(user *models.IdmUser, ws *models.IdmWorkspace, roles []*canRole, steps []*canStep, acls []*models.IdmACL) *canResult {
+	result := &canResult{
+		User:      user.Login,
+		Workspace: ws.Slug,
+		Path:      steps[len(steps)-1].Path,
+		Roles:     roles,
+		Steps:     steps,
+	}
+	for _, l := range rest.UserLocks(user) {
+		result.Locked = result.Locked || l == rest.UserLockLogout
+	}
+
+	for _, s := range steps {
+		for _, r := range roles {
+			var rights string
+			for _, a := range acls {
+				if a.Action == nil || a.NodeID != s.NodeID || a.RoleID != r.ID || (a.WorkspaceID != "" && a.WorkspaceID != ws.UUID) {
+					continue
+				}
+				switch a.Action.Name {
+				case "read":
+					rights += "r"
+				case "write":
+					rights += "w"
+				case "deny":
+					rights += "deny"
+				}
+			}
+			if rights == "" {
+				continue
+			}
+			// Roles are sorted by increasing priority: the last role that defines rights on the node wins
+			if strings.Contains(rights, "deny") {
+				s.Rights = "deny"
+			} else {
+				s.Rights = normalizeRights(rights)
+			}
+			s.Role = r.Label
+		}
+	}
+
+	result.Read = &canDecision{Reason: "no ACL gives access along the path"}
+	result.Write = &canDecision{Reason: "no ACL gives access along the path"}
+	for _, s := range steps {
+		if s.Rights == "deny" {
+			reason := fmt.Sprintf("denied on %s by %s", s.Path, s.Role)
+			result.Read.Reason, result.Write.Reason = reason, reason
+			return result
+		}
+	}
+	for i := len(steps) - 1; i >= 0; i-- {
+		s := steps[i]
+		if s.Rights == "" {
+			continue
+		}
+		result.Read.Allowed = strings.Contains(s.Rights, "r")
+		result.Write.Allowed = strings.Contains(s.Rights, "w")
+		for _, d := range []*canDecision{result.Read, result.Write} {
+			if d.Allowed {
+				d.Reason = fmt.Sprintf("granted on %s by %s", s.Path, s.Role)
+			} else {
+				d.Reason = fmt.Sprintf("not granted on %s by %s, that overrides the parent folders", s.Path, s.Role)
+			}
+		}
+		break
+	}
+	if result.Locked {
+		result.Read.Allowed, result.Write.Allowed = false, false
+		result.Read.Reason = "the account is locked, " + result.Read.Reason
+		result.Write.Reason = "the account is locked, " + result.Write.Reason
+	}
+	return result
+}
+
+func canRoleLabel(user *models.IdmUser, r *models.IdmRole) string {
+	switch {
+	case r.UUID == rest.RootGroupRole:
+		return "root group (all users)"
+	case r.UUID == user.UUID || r.UserRole:
+		return "user " + user.Login
+	case r.GroupRole:
+		return "group " + r.Label
+	default:
+		return "role " + r.Label
+	}
+}
+
+func printCanResult(result *canResult) {
+	fmt.Printf("Roles of user %s, by increasing priority:\n", result.User)
+	for i, r := range result.Roles {
+		fmt.Printf("  %d. %s\n", i+1, r.Label)
+	}
+	fmt.Println()
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Path", "Rights", "Defined by"})
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+	for _, s := range result.Steps {
+		table.Append([]string{s.Path, rest.ValueOr(s.Rights, "-"), rest.ValueOr(s.Role, "-")})
+	}
+	table.Render()
+	fmt.Println()
+
+	if result.Locked {
+		fmt.Printf("%s the account of %s is locked\n", color.RedString("!"), result.User)
+	}
+	for _, d := range []struct {
+		name     string
+		decision *canDecision
+	}{{"read", result.Read}, {"write", result.Write}} {
+		verdict := color.GreenString("ALLOWED")
+		if !d.decision.Allowed {
+			verdict = color.RedString("DENIED")
+		}
+		fmt.Printf("%-6s %s: %s\n", d.name, verdict, d.decision.Reason)
+	}
+}
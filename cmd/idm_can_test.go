@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/pydio/cells-sdk-go/v4/models"
+
+	"github.com/pydio/cells-client/v4/rest"
+
+	// Silently import convey to ease implementation
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func canAcl(roleID, nodeID, action string) *models.IdmACL {
+	return &models.IdmACL{RoleID: roleID, NodeID: nodeID, WorkspaceID: "ws-finance", Action: &models.IdmACLAction{Name: action, Value: "1"}}
+}
+
+func TestEvaluateCan(t *testing.T) {
+	Convey("Test evaluation of the effective permissions", t, func() {
+		user := &models.IdmUser{UUID: "user-bob", Login: "bob"}
+		ws := &models.IdmWorkspace{UUID: "ws-finance", Slug: "finance"}
+		// By increasing priority
+		roles := []*canRole{
+			{ID: rest.RootGroupRole, Label: "root group (all users)"},
+			{ID: "group-sales", Label: "group sales"},
+			{ID: "user-bob", Label: "user bob"},
+		}
+		newSteps := func() []*canStep {
+			return []*canStep{
+				{Path: "finance", NodeID: "node-root"},
+				{Path: "finance/reports", NodeID: "node-reports"},
+				{Path: "finance/reports/2024", NodeID: "node-2024"},
+			}
+		}
+
+		Convey("Without ACL, nothing is allowed", func() {
+			result := evaluateCan(user, ws, roles, newSteps(), nil)
+			So(result.Path, ShouldEqual, "finance/reports/2024")
+			So(result.Read.Allowed, ShouldBeFalse)
+			So(result.Write.Allowed, ShouldBeFalse)
+		})
+
+		Convey("The role with the highest priority wins on a node", func() {
+			result := evaluateCan(user, ws, roles, newSteps(), []*models.IdmACL{
+				canAcl(rest.RootGroupRole, "node-root", "read"),
+				canAcl("group-sales", "node-reports", "read"),
+				canAcl("group-sales", "node-reports", "write"),
+				canAcl("user-bob", "node-reports", "read"),
+			})
+			So(result.Steps[0].Rights, ShouldEqual, "r")
+			So(result.Steps[1].Rights, ShouldEqual, "r")
+			So(result.Steps[1].Role, ShouldEqual, "user bob")
+			So(result.Read.Allowed, ShouldBeTrue)
+			So(result.Write.Allowed, ShouldBeFalse)
+			So(result.Read.Reason, ShouldEqual, "granted on finance/reports by user bob")
+		})
+
+		Convey("The deepest node with rights applies", func() {
+			result := evaluateCan(user, ws, roles, newSteps(), []*models.IdmACL{
+				canAcl(rest.RootGroupRole, "node-root", "read"),
+				canAcl(rest.RootGroupRole, "node-root", "write"),
+				canAcl("group-sales", "node-2024", "read"),
+			})
+			So(result.Read.Allowed, ShouldBeTrue)
+			So(result.Write.Allowed, ShouldBeFalse)
+		})
+
+		Convey("A deny along the path prevails", func() {
+			result := evaluateCan(user, ws, roles, newSteps(), []*models.IdmACL{
+				canAcl("group-sales", "node-reports", "deny"),
+				canAcl("group-sales", "node-2024", "read"),
+				canAcl("group-sales", "node-2024", "write"),
+			})
+			So(result.Steps[1].Rights, ShouldEqual, "deny")
+			So(result.Read.Allowed, ShouldBeFalse)
+			So(result.Write.Allowed, ShouldBeFalse)
+			So(result.Read.Reason, ShouldEqual, "denied on finance/reports by group sales")
+		})
+
+		Convey("ACLs of other workspaces are ignored", func() {
+			other := canAcl("group-sales", "node-reports", "read")
+			other.WorkspaceID = "ws-other"
+			result := evaluateCan(user, ws, roles, newSteps(), []*models.IdmACL{other})
+			So(result.Read.Allowed, ShouldBeFalse)
+		})
+
+		Convey("Locked accounts have no access", func() {
+			user.Attributes = map[string]string{rest.UserAttrLocks: `["logout"]`}
+			result := evaluateCan(user, ws, roles, newSteps(), []*models.IdmACL{canAcl(rest.RootGroupRole, "node-root", "read")})
+			So(result.Locked, ShouldBeTrue)
+			So(result.Read.Allowed, ShouldBeFalse)
+		})
+	})
+}
@@ -0,0 +1,376 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/pydio/cells-sdk-go/v4/models"
+
+	"github.com/pydio/cells-client/v4/rest"
+)
+
+var (
+	groupDisplayName      string
+	groupRmForce          bool
+	groupMembersRecursive bool
+	groupMembersTarget    string
+)
+
+var groupCmd = &cobra.Command{
+	Use:     "group",
+	Aliases: []string{"groups"},
+	Short:   "Manage the groups and their members",
+	Long: `
+DESCRIPTION
+
+  Groups are organised as a hierarchy of paths, e.g. /org/team, and each user belongs to exactly one group.
+  These commands manage the groups and move users in and out of them. You must be logged in with an administrator account.
+  See the help of respective sub-commands for further details.
+`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cm *cobra.Command, args []string) {
+		_ = cm.Usage()
+	},
+}
+
+var groupTree = &cobra.Command{
+	Use:   "tree",
+	Short: "Show the hierarchy of groups",
+	Long: `
+DESCRIPTION
+
+  Show the hierarchy of groups under the passed group (the root by default), with the number of users
+  that are direct members of each group.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` idm group tree
+  $ ` + os.Args[0] + ` idm group tree /org
+`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		root := "/"
+		if len(args) == 1 {
+			root = "/" + strings.Trim(args[0], "/")
+		}
+		groups, err := sdkClient.ListGroups(ctx, root, true)
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		users, err := sdkClient.ListUsers(ctx, root, true)
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+
+		children := make(map[string][]string)
+		for _, g := range groups {
+			p := rest.GroupFullPath(g)
+			children[path.Dir(p)] = append(children[path.Dir(p)], p)
+		}
+		members := make(map[string]int)
+		for _, u := range users {
+			members[rest.GroupFullPath(u)]++
+		}
+
+		fmt.Printf("%s (%s)\n", root, membersLabel(members[root]))
+		printGroupTree(root, "", children, members)
+	},
+}
+
+var groupCreate = &cobra.Command{
+	Use:   "create",
+	Short: "Create groups",
+	Long: `
+DESCRIPTION
+
+  Create groups at the passed full paths. Missing parent groups are also created.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` idm group create /org/team --display-name "The Team"
+`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		for _, arg := range args {
+			fullPath := "/" + strings.Trim(arg, "/")
+			if fullPath == "/" {
+				rest.Log.Fatalln("Cannot create the root group")
+			}
+			var ancestors []string
+			for p := path.Dir(fullPath); p != "/"; p = path.Dir(p) {
+				ancestors = append([]string{p}, ancestors...)
+			}
+			for _, p := range ancestors {
+				if _, err := sdkClient.FindGroup(ctx, p); err == nil {
+					continue
+				} else if !rest.IsNotFound(err) {
+					rest.Log.Fatal(err)
+				}
+				if _, err := sdkClient.CreateGroup(ctx, p, ""); err != nil {
+					rest.Log.Fatal(err)
+				}
+				fmt.Printf("Group %s has been created\n", p)
+			}
+			if _, err := sdkClient.FindGroup(ctx, fullPath); err == nil {
+				rest.Log.Fatalf("Group %s already exists\n", fullPath)
+			} else if !rest.IsNotFound(err) {
+				rest.Log.Fatal(err)
+			}
+			if _, err := sdkClient.CreateGroup(ctx, fullPath, groupDisplayName); err != nil {
+				rest.Log.Fatal(err)
+			}
+			fmt.Printf("Group %s has been created\n", fullPath)
+		}
+	},
+}
+
+var groupRm = &cobra.Command{
+	Use:   "rm",
+	Short: "Remove groups",
+	Long: `
+DESCRIPTION
+
+  Remove the passed groups. Warning: all their sub-groups and their users are also definitively removed.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` idm group rm /org/former-team
+`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		var groups []*models.IdmUser
+		for _, arg := range args {
+			g, err := sdkClient.FindGroup(ctx, arg)
+			if err != nil {
+				rest.Log.Fatal(err)
+			}
+			groups = append(groups, g)
+		}
+
+		if !groupRmForce {
+			for _, g := range groups {
+				p := rest.GroupFullPath(g)
+				users, err := sdkClient.ListUsers(ctx, p, true)
+				if err != nil {
+					rest.Log.Fatal(err)
+				}
+				subGroups, err := sdkClient.ListGroups(ctx, p, true)
+				if err != nil {
+					rest.Log.Fatal(err)
+				}
+				fmt.Printf("Group %s would be removed with its %d sub-group(s) and %s\n", p, len(subGroups), membersLabel(len(users)))
+			}
+			if !confirmOrAbort(false) {
+				return
+			}
+		}
+
+		for _, g := range groups {
+			if err := sdkClient.DeleteGroup(ctx, g); err != nil {
+				rest.Log.Fatal(err)
+			}
+			fmt.Printf("Group %s has been removed\n", rest.GroupFullPath(g))
+		}
+	},
+}
+
+var groupRename = &cobra.Command{
+	Use:   "rename",
+	Short: "Rename or move a group",
+	Long: `
+DESCRIPTION
+
+  Change the full path of a group: its members and sub-groups are moved along.
+  The new parent group must already exist.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` idm group rename /org/team /org/new-team
+  $ ` + os.Args[0] + ` idm group rename /org/team /other-org/team
+`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		group, err := sdkClient.FindGroup(ctx, args[0])
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		oldPath := rest.GroupFullPath(group)
+		newPath := "/" + strings.Trim(args[1], "/")
+		if newPath == "/" || newPath == oldPath || strings.HasPrefix(newPath, oldPath+"/") {
+			rest.Log.Fatalf("Cannot move %s to %s\n", oldPath, newPath)
+		}
+		if _, err = sdkClient.FindGroup(ctx, newPath); err == nil {
+			rest.Log.Fatalf("Group %s already exists\n", newPath)
+		} else if !rest.IsNotFound(err) {
+			rest.Log.Fatal(err)
+		}
+		if parent := path.Dir(newPath); parent != "/" {
+			if _, err = sdkClient.FindGroup(ctx, parent); err != nil {
+				rest.Log.Fatal(err)
+			}
+		}
+		if _, err = sdkClient.MoveGroup(ctx, group, newPath); err != nil {
+			rest.Log.Fatal(err)
+		}
+		fmt.Printf("Group %s has been moved to %s\n", oldPath, newPath)
+	},
+}
+
+var groupMembers = &cobra.Command{
+	Use:   "members",
+	Short: "Manage the members of a group",
+	Long: `
+DESCRIPTION
+
+  List the members of a group, and move users in and out of it.
+  See the help of respective sub-commands for further details.
+`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cm *cobra.Command, args []string) {
+		_ = cm.Usage()
+	},
+}
+
+var groupMembersLs = &cobra.Command{
+	Use:   "ls",
+	Short: "List the members of a group",
+	Long: `
+DESCRIPTION
+
+  List the users that are direct members of the passed group, or of its sub-groups as well with the --recursive flag.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` idm group members ls /org/team
+  $ ` + os.Args[0] + ` idm group members ls -r /org
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		groupPath := "/" + strings.Trim(args[0], "/")
+		users, err := sdkClient.ListUsers(cmd.Context(), groupPath, groupMembersRecursive)
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		if len(users) == 0 {
+			fmt.Printf("No user found in %s\n", groupPath)
+			return
+		}
+		sort.Slice(users, func(i, j int) bool { return users[i].Login < users[j].Login })
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Login", "Display name", "Group"})
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+		table.SetAutoWrapText(false)
+		for _, u := range users {
+			table.Append([]string{u.Login, u.Attributes[rest.UserAttrDisplayName], rest.GroupFullPath(u)})
+		}
+		table.Render()
+	},
+}
+
+var groupMembersAdd = &cobra.Command{
+	Use:   "add",
+	Short: "Move users into a group",
+	Long: `
+DESCRIPTION
+
+  Move the passed users into the group. As a user belongs to exactly one group, they leave their current group.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` idm group members add /org/team jdoe asmith
+`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		moveUsersToGroup(cmd, args[0], args[1:], "")
+	},
+}
+
+var groupMembersRm = &cobra.Command{
+	Use:   "rm",
+	Short: "Move users out of a group",
+	Long: `
+DESCRIPTION
+
+  Move the passed users out of the group, to the root group by default or to the group given by the --to flag.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` idm group members rm /org/team jdoe
+  $ ` + os.Args[0] + ` idm group members rm /org/team jdoe --to /org/alumni
+`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		moveUsersToGroup(cmd, groupMembersTarget, args[1:], args[0])
+	},
+}
+
+func init() {
+	groupCreate.Flags().StringVar(&groupDisplayName, "display-name", "", "Name of the group, as displayed in the web interface")
+	groupRm.Flags().BoolVarP(&groupRmForce, "force", "f", false, "Do not ask for user approval")
+	groupMembersLs.Flags().BoolVarP(&groupMembersRecursive, "recursive", "r", false, "Also list the members of the sub-groups")
+	groupMembersRm.Flags().StringVar(&groupMembersTarget, "to", "/", "Group in which the users are moved")
+
+	groupMembers.AddCommand(groupMembersLs, groupMembersAdd, groupMembersRm)
+	groupCmd.AddCommand(groupTree, groupCreate, groupRm, groupRename, groupMembers)
+	idmCmd.AddCommand(groupCmd)
+}
+
+// moveUsersToGroup moves the users to the target group. If from is not empty, users must currently be members of this group.
+func moveUsersToGroup(cmd *cobra.Command, target string, logins []string, from string) {
+	ctx := cmd.Context()
+	target = "/" + strings.Trim(target, "/")
+	if target != "/" {
+		if _, err := sdkClient.FindGroup(ctx, target); err != nil {
+			rest.Log.Fatal(err)
+		}
+	}
+	for _, login := range logins {
+		user, err := sdkClient.FindUser(ctx, login)
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		current := rest.GroupFullPath(user)
+		if from != "" && current != "/"+strings.Trim(from, "/") {
+			rest.Log.Fatalf("User %s is not a member of %s but of %s\n", login, from, current)
+		}
+		if current == target {
+			fmt.Printf("User %s is already in %s\n", login, target)
+			continue
+		}
+		user.GroupPath = target
+		if _, err = sdkClient.PutUser(ctx, user); err != nil {
+			rest.Log.Fatal(err)
+		}
+		fmt.Printf("User %s has been moved from %s to %s\n", login, current, target)
+	}
+}
+
+func printGroupTree(parent, indent string, children map[string][]string, members map[string]int) {
+	kids := children[parent]
+	sort.Strings(kids)
+	for i, k := range kids {
+		branch, next := "├── ", "│   "
+		if i == len(kids)-1 {
+			branch, next = "└── ", "    "
+		}
+		fmt.Printf("%s%s%s (%s)\n", indent, branch, path.Base(k), membersLabel(members[k]))
+		printGroupTree(k, indent+next, children, members)
+	}
+}
+
+func membersLabel(count int) string {
+	if count == 1 {
+		return "1 user"
+	}
+	return fmt.Sprintf("%d users", count)
+}
@@ -0,0 +1,362 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/pydio/cells-sdk-go/v4/models"
+
+	"github.com/pydio/cells-client/v4/rest"
+)
+
+var (
+	roleUUID       string
+	roleAutoApply  []string
+	roleFormat     string
+	roleRmForce    bool
+	roleUsers      []string
+	roleGroups     []string
+	roleAssignRefs []string
+)
+
+var roleCmd = &cobra.Command{
+	Use:     "role",
+	Aliases: []string{"roles"},
+	Short:   "Manage the roles and their assignment",
+	Long: `
+DESCRIPTION
+
+  Create, inspect and remove roles, and assign them to users and groups. You must be logged in with an administrator account.
+  Roles are designated either by their UUID or by their label.
+  See the help of respective sub-commands for further details.
+`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cm *cobra.Command, args []string) {
+		_ = cm.Usage()
+	},
+}
+
+var roleCreate = &cobra.Command{
+	Use:   "create",
+	Short: "Create a new role",
+	Long: `
+DESCRIPTION
+
+  Create a new role with the passed label. A UUID is generated, unless one is given with the --uuid flag.
+  With the --auto-apply flag, the role is automatically applied to all users with the given profiles.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` idm role create "Project managers"
+  $ ` + os.Args[0] + ` idm role create "All users" --uuid all-users --auto-apply standard,admin
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		id := roleUUID
+		if id == "" {
+			id = uuid.New().String()
+		} else if _, err := sdkClient.GetRole(ctx, id); err == nil {
+			rest.Log.Fatalf("A role with UUID %s already exists\n", id)
+		}
+		for _, p := range roleAutoApply {
+			switch p {
+			case "standard", "shared", "admin":
+			default:
+				rest.Log.Fatalf("Invalid profile %s, it must be one of standard, shared or admin\n", p)
+			}
+		}
+		role, err := sdkClient.PutRole(ctx, &models.IdmRole{UUID: id, Label: args[0], AutoApplies: roleAutoApply})
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		fmt.Printf("Role %s has been created with UUID %s\n", role.Label, role.UUID)
+	},
+}
+
+var roleShow = &cobra.Command{
+	Use:   "show",
+	Short: "Show the details of a role",
+	Long: `
+DESCRIPTION
+
+  Show the details of a role, including the ACLs it defines and its security policies.
+  Technical user and group roles can also be shown, using their UUID.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` idm role show 0b8d6e5c-7a1f-4a4e-9f51-2cbb41f9d1a2
+  $ ` + os.Args[0] + ` idm role show "Project managers" --format json
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		role, err := resolveRole(ctx, args[0])
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		acls, err := sdkClient.SearchRoleAcls(ctx, role.UUID)
+		if err != nil {
+			rest.Log.Fatalf("Could not list ACLs of role %s: %s\n", role.UUID, err.Error())
+		}
+
+		switch roleFormat {
+		case "json":
+			data, _ := json.MarshalIndent(map[string]interface{}{"role": role, "acls": acls}, "", "  ")
+			fmt.Printf("%s\n", data)
+		case "table":
+			printRoleDetails(role, acls)
+		default:
+			cmd.Println("invalid output format, it must be either json or table")
+		}
+	},
+}
+
+var roleRm = &cobra.Command{
+	Use:   "rm",
+	Short: "Remove roles",
+	Long: `
+DESCRIPTION
+
+  Remove the passed roles: they are unassigned from all users and groups, and their ACLs are deleted.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` idm role rm "Project managers"
+  $ ` + os.Args[0] + ` idm role rm -f 0b8d6e5c-7a1f-4a4e-9f51-2cbb41f9d1a2
+`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		var roles []*models.IdmRole
+		for _, arg := range args {
+			r, err := sdkClient.FindRole(ctx, arg)
+			if err != nil {
+				rest.Log.Fatal(err)
+			}
+			roles = append(roles, r)
+		}
+
+		if !roleRmForce {
+			var labels []string
+			for _, r := range roles {
+				labels = append(labels, fmt.Sprintf("%s (%s)", r.Label, r.UUID))
+			}
+			fmt.Printf("About to remove %d role(s): %s\n", len(roles), strings.Join(labels, ", "))
+			if !confirmOrAbort(false) {
+				return
+			}
+		}
+
+		for _, r := range roles {
+			if err := sdkClient.DeleteRole(ctx, r.UUID); err != nil {
+				rest.Log.Fatal(err)
+			}
+			fmt.Printf("Role %s has been removed\n", r.Label)
+		}
+	},
+}
+
+var roleAssign = &cobra.Command{
+	Use:   "assign",
+	Short: "Assign roles to users and groups",
+	Long: `
+DESCRIPTION
+
+  Assign the roles given with the --role flag to the users and groups given with the --user and --group flags.
+  Groups are designated by their full path.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` idm role assign --role "Project managers" --user alice --user bob
+  $ ` + os.Args[0] + ` idm role assign --role 0b8d6e5c-7a1f-4a4e-9f51-2cbb41f9d1a2 --group /org/team
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		updateRoleAssignments(cmd.Context(), true)
+	},
+}
+
+var roleUnassign = &cobra.Command{
+	Use:   "unassign",
+	Short: "Remove roles from users and groups",
+	Long: `
+DESCRIPTION
+
+  Remove the roles given with the --role flag from the users and groups given with the --user and --group flags.
+  Groups are designated by their full path.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` idm role unassign --role "Project managers" --user alice
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		updateRoleAssignments(cmd.Context(), false)
+	},
+}
+
+func init() {
+	roleCreate.Flags().StringVar(&roleUUID, "uuid", "", "Use this UUID rather than a generated one")
+	roleCreate.Flags().StringSliceVar(&roleAutoApply, "auto-apply", []string{}, "Comma separated list of profiles (standard, shared, admin) to which the role is automatically applied")
+	roleShow.Flags().StringVar(&roleFormat, "format", "table", "Output format table|json")
+	roleRm.Flags().BoolVarP(&roleRmForce, "force", "f", false, "Do not ask for user approval")
+	for _, c := range []*cobra.Command{roleAssign, roleUnassign} {
+		flags := c.Flags()
+		flags.StringArrayVar(&roleAssignRefs, "role", []string{}, "UUID or label of the role, can be repeated")
+		flags.StringArrayVar(&roleUsers, "user", []string{}, "Login of a user, can be repeated")
+		flags.StringArrayVar(&roleGroups, "group", []string{}, "Full path of a group, can be repeated")
+	}
+
+	roleCmd.AddCommand(roleCreate, roleShow, roleRm, roleAssign, roleUnassign)
+	idmCmd.AddCommand(roleCmd)
+}
+
+// resolveRole finds an assignable role by its UUID or label, or any role, including technical ones, by its UUID.
+func resolveRole(ctx context.Context, uuidOrLabel string) (*models.IdmRole, error) {
+	if r, err := sdkClient.FindRole(ctx, uuidOrLabel); err == nil {
+		return r, nil
+	}
+	return sdkClient.GetRole(ctx, uuidOrLabel)
+}
+
+func updateRoleAssignments(ctx context.Context, assign bool) {
+	if len(roleAssignRefs) == 0 {
+		rest.Log.Fatalln("Please give at least one role with the --role flag")
+	}
+	if len(roleUsers) == 0 && len(roleGroups) == 0 {
+		rest.Log.Fatalln("Please give at least one user or group with the --user or --group flags")
+	}
+	var roles []*models.IdmRole
+	for _, ref := range roleAssignRefs {
+		r, err := sdkClient.FindRole(ctx, ref)
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		roles = append(roles, r)
+	}
+
+	var principals []*models.IdmUser
+	for _, login := range roleUsers {
+		u, err := sdkClient.FindUser(ctx, login)
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		principals = append(principals, u)
+	}
+	for _, g := range roleGroups {
+		group, err := sdkClient.FindGroup(ctx, g)
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		principals = append(principals, group)
+	}
+
+	for _, p := range principals {
+		name := p.Login
+		if p.IsGroup {
+			name = rest.GroupFullPath(p)
+		}
+		current := rest.AssignedRoles(p)
+		var updated []*models.IdmRole
+		var changed []string
+		if assign {
+			updated = current
+			for _, r := range roles {
+				if !containsRole(current, r) {
+					updated = append(updated, r)
+					changed = append(changed, r.Label)
+				}
+			}
+		} else {
+			for _, r := range current {
+				if containsRole(roles, r) {
+					changed = append(changed, r.Label)
+				} else {
+					updated = append(updated, r)
+				}
+			}
+		}
+		if len(changed) == 0 {
+			fmt.Printf("Nothing to change for %s\n", name)
+			continue
+		}
+		if err := sdkClient.SetAssignedRoles(ctx, p, updated); err != nil {
+			rest.Log.Fatal(err)
+		}
+		if assign {
+			fmt.Printf("Role(s) %s assigned to %s\n", strings.Join(changed, ", "), name)
+		} else {
+			fmt.Printf("Role(s) %s removed from %s\n", strings.Join(changed, ", "), name)
+		}
+	}
+}
+
+func containsRole(roles []*models.IdmRole, role *models.IdmRole) bool {
+	for _, r := range roles {
+		if r.UUID == role.UUID {
+			return true
+		}
+	}
+	return false
+}
+
+func printRoleDetails(role *models.IdmRole, acls []*models.IdmACL) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+	table.AppendBulk([][]string{
+		{"UUID", role.UUID},
+		{"Label", role.Label},
+		{"Type", roleType(role)},
+		{"Auto apply", rest.ValueOr(strings.Join(role.AutoApplies, ", "), "-")},
+		{"Last updated", stampToAbsoluteDate(strconv.Itoa(int(role.LastUpdated)))},
+	})
+	table.Render()
+
+	fmt.Println("\nACLs:")
+	if len(acls) == 0 {
+		fmt.Println("  None")
+	} else {
+		aclTable := tablewriter.NewWriter(os.Stdout)
+		aclTable.SetHeader([]string{"Action", "Value", "Workspace", "Node"})
+		aclTable.SetAlignment(tablewriter.ALIGN_LEFT)
+		aclTable.SetAutoWrapText(false)
+		for _, a := range acls {
+			if a.Action == nil {
+				continue
+			}
+			aclTable.Append([]string{a.Action.Name, a.Action.Value, rest.ValueOr(a.WorkspaceID, "-"), rest.ValueOr(a.NodeID, "-")})
+		}
+		aclTable.Render()
+	}
+
+	fmt.Println("\nPolicies:")
+	if len(role.Policies) == 0 {
+		fmt.Println("  None")
+		return
+	}
+	policyTable := tablewriter.NewWriter(os.Stdout)
+	policyTable.SetHeader([]string{"Action", "Subject", "Effect"})
+	policyTable.SetAlignment(tablewriter.ALIGN_LEFT)
+	policyTable.SetAutoWrapText(false)
+	for _, p := range role.Policies {
+		var action, effect string
+		if p.Action != nil {
+			action = string(*p.Action)
+		}
+		if p.Effect != nil {
+			effect = string(*p.Effect)
+		}
+		policyTable.Append([]string{action, p.Subject, effect})
+	}
+	policyTable.Render()
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/pydio/cells-client/v4/rest"
+)
+
+var (
+	idmExportOutput    string
+	snapshotDiffFormat string
+)
+
+var idmExport = &cobra.Command{
+	Use:   "export",
+	Short: "Export a snapshot of all identities and of their access rights",
+	Long: `
+DESCRIPTION
+
+  Export the users, groups, roles, workspaces, ACLs and security policies of the server in a single versioned
+  JSON document, typically for backup or audit purposes. Passwords and other secrets are never exported.
+  On top of the workspaces managed by the administrators, the cells and the hidden workspaces of the public links
+  are also exported with their scope (ROOM or LINK), so that the rights given through shares are also captured.
+  Snapshots that have been taken at different dates can then be compared with the snapshot diff command.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` idm export -o snapshot-$(date +%F).json
+  $ ` + os.Args[0] + ` idm export | jq '.acls[] | select(.principal == "user bob")'
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		snapshot, err := sdkClient.TakeIdmSnapshot(cmd.Context())
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		data, err := json.MarshalIndent(snapshot, "", "  ")
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		if idmExportOutput == "" {
+			fmt.Printf("%s\n", data)
+			return
+		}
+		if err = os.WriteFile(idmExportOutput, append(data, '\n'), 0600); err != nil {
+			rest.Log.Fatalf("Could not write %s: %s\n", idmExportOutput, err.Error())
+		}
+		fmt.Printf("Snapshot of %d user(s), %d group(s), %d role(s), %d workspace(s) and %d ACL(s) has been written to %s\n",
+			len(snapshot.Users), len(snapshot.Groups), len(snapshot.Roles), len(snapshot.Workspaces), len(snapshot.Acls), idmExportOutput)
+	},
+}
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Work with identity snapshots",
+	Long: `
+DESCRIPTION
+
+  Commands to work with the identity snapshots that are created with the export command.
+  See the help of respective sub-commands for further details.
+`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cm *cobra.Command, args []string) {
+		_ = cm.Usage()
+	},
+}
+
+var snapshotDiff = &cobra.Command{
+	Use:   "diff",
+	Short: "Report the access changes between two snapshots",
+	Long: `
+DESCRIPTION
+
+  Compare two identity snapshots and report what changed from the first to the second one:
+  added and removed users, groups, roles, workspaces and policies, users that changed group, profile,
+  roles or locks, and permissions that have been given, modified or revoked.
+
+  Reported differences are:
+   + added: the object only exists in the second snapshot
+   - removed: the object only exists in the first snapshot
+   ~ modified: the object exists in both snapshots with a different access
+
+EXIT STATUS
+
+  Like the diff command, it exits with 0 if no difference has been found, 1 if some differences
+  have been found and 2 in case of error.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` idm snapshot diff snapshot-2024-01-01.json snapshot-2024-07-01.json
+  ~ user bob
+      group: /org/sales -> /org/management
+  + acl finance/reports for group /org/management
+      rights: rw
+
+  $ ` + os.Args[0] + ` idm snapshot diff --format json old.json new.json
+`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if snapshotDiffFormat != "text" && snapshotDiffFormat != "json" {
+			diffFatal(fmt.Errorf("invalid output format %s, it must be either text or json", snapshotDiffFormat))
+		}
+		var snapshots []*rest.IdmSnapshot
+		for _, arg := range args {
+			s, err := readIdmSnapshot(arg)
+			if err != nil {
+				diffFatal(err)
+			}
+			snapshots = append(snapshots, s)
+		}
+
+		changes := rest.DiffSnapshots(snapshots[0], snapshots[1])
+		switch snapshotDiffFormat {
+		case "json":
+			if changes == nil {
+				changes = []*rest.SnapshotChange{}
+			}
+			data, _ := json.MarshalIndent(map[string]interface{}{
+				"from":    map[string]interface{}{"file": args[0], "createdAt": snapshots[0].CreatedAt},
+				"to":      map[string]interface{}{"file": args[1], "createdAt": snapshots[1].CreatedAt},
+				"changes": changes,
+			}, "", "  ")
+			fmt.Printf("%s\n", data)
+		default:
+			if len(changes) > 0 {
+				fmt.Printf("--- %s (%s)\n+++ %s (%s)\n", args[0], snapshots[0].CreatedAt.Format("2006-01-02 15:04"), args[1], snapshots[1].CreatedAt.Format("2006-01-02 15:04"))
+			}
+			for _, c := range changes {
+				switch c.Type {
+				case rest.DiffAdded:
+					fmt.Printf("%s %s %s\n", color.GreenString("+"), c.Kind, c.Name)
+				case rest.DiffRemoved:
+					fmt.Printf("%s %s %s\n", color.RedString("-"), c.Kind, c.Name)
+				default:
+					fmt.Printf("%s %s %s\n", color.YellowString("~"), c.Kind, c.Name)
+				}
+				for _, d := range c.Details {
+					fmt.Printf("    %s\n", d)
+				}
+			}
+		}
+
+		if len(changes) > 0 {
+			exit(1)
+		}
+	},
+}
+
+func init() {
+	idmExport.Flags().StringVarP(&idmExportOutput, "output", "o", "", "Path of the file where to write the snapshot, defaults to the standard output")
+	snapshotDiff.Flags().StringVar(&snapshotDiffFormat, "format", "text", "Output format text|json")
+	snapshotCmd.AddCommand(snapshotDiff)
+	idmCmd.AddCommand(idmExport, snapshotCmd)
+}
+
+// readIdmSnapshot loads a snapshot and checks that its format is supported.
+func readIdmSnapshot(file string) (*rest.IdmSnapshot, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %s", file, err.Error())
+	}
+	snapshot := &rest.IdmSnapshot{}
+	if err = json.Unmarshal(data, snapshot); err != nil {
+		return nil, fmt.Errorf("%s is not a valid snapshot: %s", file, err.Error())
+	}
+	if snapshot.Version < 1 || snapshot.Version > rest.IdmSnapshotVersion {
+		return nil, fmt.Errorf("%s has an unsupported snapshot version %d", file, snapshot.Version)
+	}
+	return snapshot, nil
+}
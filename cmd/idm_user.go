@@ -0,0 +1,444 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/pydio/cells-sdk-go/v4/models"
+
+	"github.com/pydio/cells-client/v4/rest"
+)
+
+var (
+	userEmail         string
+	userDisplayName   string
+	userProfile       string
+	userGroupPath     string
+	userAttributes    []string
+	userPasswordStdin bool
+	userRmForce       bool
+	userFormat        string
+)
+
+var userCmd = &cobra.Command{
+	Use:     "user",
+	Aliases: []string{"users"},
+	Short:   "Manage the user accounts",
+	Long: `
+DESCRIPTION
+
+  Create, update, lock and remove user accounts. You must be logged in with an administrator account.
+  See the help of respective sub-commands for further details.
+`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cm *cobra.Command, args []string) {
+		_ = cm.Usage()
+	},
+}
+
+var userCreate = &cobra.Command{
+	Use:   "create",
+	Short: "Create a new user",
+	Long: `
+DESCRIPTION
+
+  Create a new user with the passed login.
+
+  The password is either read from the standard input when the --password-stdin flag is set,
+  or asked interactively otherwise.
+  Additional attributes can be defined with the --attribute flag, using the key=value form.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` idm user create jdoe --email jdoe@example.com --display-name "John Doe" --group-path /org/team
+  $ echo "$PASSWORD" | ` + os.Args[0] + ` idm user create jdoe --password-stdin --profile admin
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		login := args[0]
+		if _, err := sdkClient.FindUser(ctx, login); err == nil {
+			rest.Log.Fatalf("A user with login %s already exists\n", login)
+		} else if !rest.IsNotFound(err) {
+			rest.Log.Fatal(err)
+		}
+
+		user := &models.IdmUser{
+			Login:      login,
+			GroupPath:  "/" + strings.Trim(userGroupPath, "/"),
+			Attributes: map[string]string{rest.UserAttrProfile: "standard"},
+		}
+		if err := applyUserFlags(cmd.Flags(), user); err != nil {
+			rest.Log.Fatal(err)
+		}
+		password, err := readNewPassword()
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		user.Password = password
+
+		created, err := sdkClient.PutUser(ctx, user)
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		fmt.Printf("User %s has been created with UUID %s\n", created.Login, created.UUID)
+	},
+}
+
+var userUpdate = &cobra.Command{
+	Use:   "update",
+	Short: "Update an existing user",
+	Long: `
+DESCRIPTION
+
+  Update the attributes, the profile or the group of an existing user.
+  Only the values that are explicitly passed are modified.
+  Use the --attribute flag with an empty value (e.g. --attribute key=) to remove an attribute.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` idm user update jdoe --display-name "John H. Doe" --group-path /org/other-team
+  $ ` + os.Args[0] + ` idm user update jdoe --profile standard --attribute "parameter:core.conf:lang=\"fr\""
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		user, err := sdkClient.FindUser(ctx, args[0])
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		if err = applyUserFlags(cmd.Flags(), user); err != nil {
+			rest.Log.Fatal(err)
+		}
+		if _, err = sdkClient.PutUser(ctx, user); err != nil {
+			rest.Log.Fatal(err)
+		}
+		fmt.Printf("User %s has been updated\n", user.Login)
+	},
+}
+
+var userPasswd = &cobra.Command{
+	Use:   "passwd",
+	Short: "Define a new password for a user",
+	Long: `
+DESCRIPTION
+
+  Define a new password for an existing user. The password is either read from the standard input
+  when the --password-stdin flag is set, or asked interactively otherwise.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` idm user passwd jdoe
+  $ echo "$PASSWORD" | ` + os.Args[0] + ` idm user passwd jdoe --password-stdin
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		user, err := sdkClient.FindUser(ctx, args[0])
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		if user.Password, err = readNewPassword(); err != nil {
+			rest.Log.Fatal(err)
+		}
+		if _, err = sdkClient.PutUser(ctx, user); err != nil {
+			rest.Log.Fatal(err)
+		}
+		fmt.Printf("Password of user %s has been updated\n", user.Login)
+	},
+}
+
+var userLock = &cobra.Command{
+	Use:   "lock",
+	Short: "Prevent users from logging in",
+	Long: `
+DESCRIPTION
+
+  Lock the accounts of the passed users: they cannot log in anymore until they are unlocked.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` idm user lock jdoe
+`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		setUsersLock(cmd.Context(), args, true)
+	},
+}
+
+var userUnlock = &cobra.Command{
+	Use:   "unlock",
+	Short: "Allow locked users to log in again",
+	Long: `
+DESCRIPTION
+
+  Unlock the accounts of the passed users, that have been locked by an administrator
+  or by the server, for instance after too many failed login attempts.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` idm user unlock jdoe
+`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		setUsersLock(cmd.Context(), args, false)
+	},
+}
+
+var userRm = &cobra.Command{
+	Use:   "rm",
+	Short: "Remove users",
+	Long: `
+DESCRIPTION
+
+  Definitively remove the passed users. Their personal files are also removed by the server.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` idm user rm jdoe
+  $ ` + os.Args[0] + ` idm user rm -f jdoe asmith
+`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		var users []*models.IdmUser
+		for _, login := range args {
+			u, err := sdkClient.FindUser(ctx, login)
+			if err != nil {
+				rest.Log.Fatal(err)
+			}
+			users = append(users, u)
+		}
+
+		if !userRmForce {
+			var labels []string
+			for _, u := range users {
+				labels = append(labels, fmt.Sprintf("%s (%s)", u.Login, rest.GroupFullPath(u)))
+			}
+			fmt.Printf("About to remove %d user(s): %s\n", len(users), strings.Join(labels, ", "))
+			if !confirmOrAbort(false) {
+				return
+			}
+		}
+
+		for _, u := range users {
+			if err := sdkClient.DeleteUser(ctx, u); err != nil {
+				rest.Log.Fatal(err)
+			}
+			fmt.Printf("User %s has been removed\n", u.Login)
+		}
+	},
+}
+
+var userShow = &cobra.Command{
+	Use:   "show",
+	Short: "Show the details of a user",
+	Long: `
+DESCRIPTION
+
+  Show the details of a user, including its attributes and its roles.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` idm user show jdoe
+  $ ` + os.Args[0] + ` idm user show jdoe --format json
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		user, err := sdkClient.FindUser(cmd.Context(), args[0])
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		switch userFormat {
+		case "json":
+			user.Password = ""
+			data, _ := json.MarshalIndent(user, "", "  ")
+			fmt.Printf("%s\n", data)
+		case "table":
+			printUserDetails(user)
+		default:
+			cmd.Println("invalid output format, it must be either json or table")
+		}
+	},
+}
+
+func init() {
+	for _, c := range []*cobra.Command{userCreate, userUpdate} {
+		flags := c.Flags()
+		flags.StringVar(&userEmail, "email", "", "Email address of the user")
+		flags.StringVar(&userDisplayName, "display-name", "", "Name of the user, as displayed in the web interface")
+		flags.StringVar(&userGroupPath, "group-path", "/", "Full path of the group of the user, e.g. /org/team")
+		flags.StringArrayVar(&userAttributes, "attribute", []string{}, "Additional attribute in the key=value form, can be repeated")
+	}
+	userCreate.Flags().StringVar(&userProfile, "profile", "standard", "Profile of the user: standard, shared or admin")
+	userUpdate.Flags().StringVar(&userProfile, "profile", "", "Profile of the user: standard, shared or admin")
+	userCreate.Flags().BoolVar(&userPasswordStdin, "password-stdin", false, "Read the password from the standard input")
+	userPasswd.Flags().BoolVar(&userPasswordStdin, "password-stdin", false, "Read the password from the standard input")
+	userRm.Flags().BoolVarP(&userRmForce, "force", "f", false, "Do not ask for user approval")
+	userShow.Flags().StringVar(&userFormat, "format", "table", "Output format table|json")
+
+	userCmd.AddCommand(userCreate, userUpdate, userPasswd, userLock, userUnlock, userRm, userShow)
+	idmCmd.AddCommand(userCmd)
+}
+
+// applyUserFlags sets the values that have been explicitly passed on the command line on the user.
+func applyUserFlags(flags *pflag.FlagSet, user *models.IdmUser) error {
+	if user.Attributes == nil {
+		user.Attributes = make(map[string]string)
+	}
+	if flags.Changed("email") {
+		user.Attributes[rest.UserAttrEmail] = userEmail
+	}
+	if flags.Changed("display-name") {
+		user.Attributes[rest.UserAttrDisplayName] = userDisplayName
+	}
+	if flags.Changed("profile") {
+		switch userProfile {
+		case "standard", "shared", "admin":
+			user.Attributes[rest.UserAttrProfile] = userProfile
+		default:
+			return fmt.Errorf("invalid profile %s, it must be one of standard, shared or admin", userProfile)
+		}
+	}
+	if flags.Changed("group-path") {
+		user.GroupPath = "/" + strings.Trim(userGroupPath, "/")
+	}
+	for _, a := range userAttributes {
+		k, v, ok := strings.Cut(a, "=")
+		if !ok || k == "" {
+			return fmt.Errorf("invalid attribute %s, it must be in the key=value form", a)
+		}
+		if v == "" {
+			delete(user.Attributes, k)
+		} else {
+			user.Attributes[k] = v
+		}
+	}
+	return nil
+}
+
+// readNewPassword reads the password on the first line of the standard input if the --password-stdin flag is set,
+// or asks for it twice otherwise.
+func readNewPassword() (string, error) {
+	if userPasswordStdin {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			if err != nil {
+				return "", fmt.Errorf("could not read password from standard input: %s", err.Error())
+			}
+			return "", fmt.Errorf("password read from standard input is empty")
+		}
+		return line, nil
+	}
+	if !isInteractive() {
+		return "", fmt.Errorf("cannot prompt for a password in a non-interactive session, use the --password-stdin flag")
+	}
+	p := promptui.Prompt{Label: "New password", Mask: '*', Validate: notEmpty}
+	password, err := p.Run()
+	if err != nil {
+		return "", err
+	}
+	p = promptui.Prompt{Label: "Confirm password", Mask: '*', Validate: func(s string) error {
+		if s != password {
+			return fmt.Errorf("passwords do not match")
+		}
+		return nil
+	}}
+	if _, err = p.Run(); err != nil {
+		return "", err
+	}
+	return password, nil
+}
+
+func setUsersLock(ctx context.Context, logins []string, locked bool) {
+	for _, login := range logins {
+		user, err := sdkClient.FindUser(ctx, login)
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		rest.SetUserLock(user, rest.UserLockLogout, locked)
+		if !locked {
+			// Also reset the counter of failed connections, that would otherwise lock the user again
+			delete(user.Attributes, "failedConnections")
+		}
+		if _, err = sdkClient.PutUser(ctx, user); err != nil {
+			rest.Log.Fatal(err)
+		}
+		if locked {
+			fmt.Printf("User %s has been locked\n", login)
+		} else {
+			fmt.Printf("User %s has been unlocked\n", login)
+		}
+	}
+}
+
+func printUserDetails(user *models.IdmUser) {
+	locks := rest.UserLocks(user)
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+	table.AppendBulk([][]string{
+		{"Login", user.Login},
+		{"UUID", user.UUID},
+		{"Display name", rest.ValueOr(user.Attributes[rest.UserAttrDisplayName], "-")},
+		{"Email", rest.ValueOr(user.Attributes[rest.UserAttrEmail], "-")},
+		{"Profile", rest.ValueOr(user.Attributes[rest.UserAttrProfile], "-")},
+		{"Group", rest.GroupFullPath(user)},
+		{"Locks", rest.ValueOr(strings.Join(locks, ", "), "-")},
+	})
+	table.Render()
+
+	var keys []string
+	for k := range user.Attributes {
+		switch k {
+		case rest.UserAttrDisplayName, rest.UserAttrEmail, rest.UserAttrProfile, rest.UserAttrLocks:
+		default:
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) > 0 {
+		sort.Strings(keys)
+		fmt.Println("\nAttributes:")
+		attrTable := tablewriter.NewWriter(os.Stdout)
+		attrTable.SetHeader([]string{"Key", "Value"})
+		attrTable.SetAlignment(tablewriter.ALIGN_LEFT)
+		attrTable.SetAutoWrapText(false)
+		for _, k := range keys {
+			attrTable.Append([]string{k, user.Attributes[k]})
+		}
+		attrTable.Render()
+	}
+
+	fmt.Println("\nRoles:")
+	roleTable := tablewriter.NewWriter(os.Stdout)
+	roleTable.SetHeader([]string{"Label", "UUID", "Type"})
+	roleTable.SetAlignment(tablewriter.ALIGN_LEFT)
+	roleTable.SetAutoWrapText(false)
+	for _, r := range user.Roles {
+		roleTable.Append([]string{r.Label, r.UUID, roleType(r)})
+	}
+	roleTable.Render()
+}
+
+// roleType returns a human-readable description of the kind of role.
+func roleType(r *models.IdmRole) string {
+	switch {
+	case r.UserRole:
+		return "User"
+	case r.GroupRole:
+		return "Group"
+	default:
+		return "Role"
+	}
+}
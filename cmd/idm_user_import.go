@@ -0,0 +1,518 @@
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/pydio/cells-sdk-go/v4/models"
+
+	"github.com/pydio/cells-client/v4/rest"
+)
+
+var (
+	userImportMapping   []string
+	userImportGroupPath string
+	userImportDryRun    bool
+	userImportFormat    string
+
+	userExportOutput    string
+	userExportGroupPath string
+)
+
+// userImportFields are the columns that are known by the import, they are also produced by the export.
+var userImportFields = []string{"login", "email", "displayName", "groupPath", "profile", "roles", "password"}
+
+// userRecord is a user as described by one row of an import file.
+type userRecord struct {
+	Line        int      `json:"-"`
+	Login       string   `json:"login"`
+	Email       string   `json:"email,omitempty"`
+	DisplayName string   `json:"displayName,omitempty"`
+	GroupPath   string   `json:"groupPath,omitempty"`
+	Profile     string   `json:"profile,omitempty"`
+	Roles       []string `json:"roles,omitempty"`
+	Password    string   `json:"password,omitempty"`
+}
+
+type userImportResult struct {
+	Line    int      `json:"line"`
+	Login   string   `json:"login"`
+	Action  string   `json:"action"`
+	Details []string `json:"details,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+var userImport = &cobra.Command{
+	Use:   "import",
+	Short: "Create or update users from a CSV or JSON file",
+	Long: `
+DESCRIPTION
+
+  Create or update users in bulk from a file. The format is deduced from the extension of the file:
+   - .csv: the first line gives the names of the columns
+   - .json: a list of objects, as produced by the export command
+
+  Known fields are: login, email, displayName, groupPath, profile, roles and password.
+  Roles are designated by their label or their UUID and separated by semicolons.
+  Use the --map flag to use other column names, e.g.: --map mail=email --map uid=login
+
+  The import is idempotent: users that do not exist yet are created, existing users are updated
+  so that they match the file, and users that already match are left untouched. Empty values are ignored,
+  and when roles are given, they replace the roles that are currently assigned to the user.
+  The password is only used when creating a user: if none is given, a random password is generated,
+  shown in the report and the user must change it upon first login.
+
+  There is no invite option: the server offers no invitation workflow for the accounts that are created
+  by an administrator, and mailing the generated passwords from the server would expose them in clear text.
+  To invite users, leave the password empty and send them the generated one through your own channel.
+  As their account is created with the "pass_change" lock, they are asked to choose their own password
+  when they first log in.
+
+  Use --dry-run to only show the changes that would be applied. A report gives the result for each row.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` idm users import users.csv --dry-run
+  $ ` + os.Args[0] + ` idm users import users.csv --map "E-Mail=email" --map "Team=groupPath"
+  $ ` + os.Args[0] + ` idm users import people.json --group-path /customers/acme --format json > report.json
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if userImportFormat != "table" && userImportFormat != "json" {
+			rest.Log.Fatalf("Invalid output format %s, it must be either table or json\n", userImportFormat)
+		}
+		mapping, err := userImportMap(userImportMapping)
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		records, err := readUserRecords(args[0], mapping)
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		if len(records) == 0 {
+			rest.Log.Fatalf("No user found in %s\n", args[0])
+		}
+
+		ctx := cmd.Context()
+		roles, err := sdkClient.ListRoles(ctx)
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+
+		var results []*userImportResult
+		failed := 0
+		for _, r := range records {
+			res := importUser(ctx, r, roles, userImportDryRun)
+			if res.Error != "" {
+				failed++
+			}
+			results = append(results, res)
+		}
+		printUserImportResults(results)
+		if failed > 0 {
+			exit(1)
+		}
+	},
+}
+
+var userExport = &cobra.Command{
+	Use:   "export",
+	Short: "Export users to a CSV or JSON file",
+	Long: `
+DESCRIPTION
+
+  Export the users of a group and of its sub-groups, with their attributes and assigned roles.
+  The result is written to the passed file, or to the standard output, and can be imported
+  again, typically on another server, with the import command. Passwords are never exported.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` idm users export users.csv
+  $ ` + os.Args[0] + ` idm users export --output json --group-path /customers/acme > acme.json
+`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if userExportOutput != "csv" && userExportOutput != "json" {
+			rest.Log.Fatalf("Invalid output format %s, it must be either csv or json\n", userExportOutput)
+		}
+		ctx := cmd.Context()
+		users, err := sdkClient.ListUsers(ctx, userExportGroupPath, true)
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		sort.Slice(users, func(i, j int) bool { return users[i].Login < users[j].Login })
+
+		var out io.Writer = os.Stdout
+		if len(args) == 1 {
+			f, e := os.Create(args[0])
+			if e != nil {
+				rest.Log.Fatalf("Could not create %s: %s\n", args[0], e.Error())
+			}
+			defer f.Close()
+			out = f
+		}
+		var records []*userRecord
+		for _, u := range users {
+			records = append(records, newUserRecord(u))
+		}
+		if err = writeUserRecords(out, records, userExportOutput); err != nil {
+			rest.Log.Fatal(err)
+		}
+		if len(args) == 1 {
+			fmt.Printf("%d user(s) have been exported to %s\n", len(records), args[0])
+		}
+	},
+}
+
+func init() {
+	importFlags := userImport.Flags()
+	importFlags.StringArrayVar(&userImportMapping, "map", []string{}, "Use a custom column name for a field, in the column=field form, can be repeated")
+	importFlags.StringVar(&userImportGroupPath, "group-path", "/", "Group of the users whose group path is not defined in the file")
+	importFlags.BoolVar(&userImportDryRun, "dry-run", false, "Only show the changes that would be applied")
+	importFlags.StringVar(&userImportFormat, "format", "table", "Format of the report table|json")
+
+	exportFlags := userExport.Flags()
+	exportFlags.StringVarP(&userExportOutput, "output", "o", "csv", "Output format csv|json")
+	exportFlags.StringVar(&userExportGroupPath, "group-path", "/", "Only export the users of this group and of its sub-groups")
+
+	userCmd.AddCommand(userImport, userExport)
+}
+
+// userImportMap builds the mapping from the lower-cased column names to the known fields.
+func userImportMap(flags []string) (map[string]string, error) {
+	mapping := make(map[string]string)
+	for _, f := range userImportFields {
+		mapping[strings.ToLower(f)] = f
+	}
+	for _, m := range flags {
+		column, field, ok := strings.Cut(m, "=")
+		if !ok || column == "" {
+			return nil, fmt.Errorf("invalid mapping %s, it must be in the column=field form", m)
+		}
+		known := false
+		for _, f := range userImportFields {
+			if strings.EqualFold(f, field) {
+				mapping[strings.ToLower(strings.TrimSpace(column))] = f
+				known = true
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown field %s in mapping, it must be one of %s", field, strings.Join(userImportFields, ", "))
+		}
+	}
+	return mapping, nil
+}
+
+func readUserRecords(filePath string, mapping map[string]string) ([]*userRecord, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("could not open %s: %s", filePath, err.Error())
+	}
+	defer file.Close()
+
+	var records []*userRecord
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".csv":
+		records, err = readUserCsv(file, mapping)
+	case ".json":
+		if err = json.NewDecoder(file).Decode(&records); err != nil {
+			err = fmt.Errorf("could not parse JSON: %s", err.Error())
+		}
+		for i, r := range records {
+			r.Line = i + 1
+		}
+	default:
+		return nil, fmt.Errorf("unsupported file %s, the extension must be .csv or .json", filePath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range records {
+		if r.GroupPath == "" {
+			r.GroupPath = userImportGroupPath
+		}
+		r.GroupPath = "/" + strings.Trim(r.GroupPath, "/")
+	}
+	return records, nil
+}
+
+func readUserCsv(reader io.Reader, mapping map[string]string) ([]*userRecord, error) {
+	r := csv.NewReader(reader)
+	r.FieldsPerRecord = -1
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("could not read CSV header: %s", err.Error())
+	}
+	fields := make([]string, len(header))
+	for i, h := range header {
+		f, ok := mapping[strings.ToLower(strings.TrimSpace(h))]
+		if !ok {
+			return nil, fmt.Errorf("unknown column %s in CSV header, use the --map flag to map it to a known field", h)
+		}
+		fields[i] = f
+	}
+
+	var records []*userRecord
+	line := 1
+	for {
+		values, err := r.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("could not parse CSV: %s", err.Error())
+		}
+		line++
+		record := &userRecord{Line: line}
+		for i, v := range values {
+			if i < len(fields) {
+				record.set(fields[i], v)
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func (r *userRecord) set(field, value string) {
+	value = strings.TrimSpace(value)
+	switch field {
+	case "login":
+		r.Login = value
+	case "email":
+		r.Email = value
+	case "displayName":
+		r.DisplayName = value
+	case "groupPath":
+		r.GroupPath = value
+	case "profile":
+		r.Profile = value
+	case "roles":
+		for _, role := range strings.Split(value, ";") {
+			if role = strings.TrimSpace(role); role != "" {
+				r.Roles = append(r.Roles, role)
+			}
+		}
+	case "password":
+		r.Password = value
+	}
+}
+
+func newUserRecord(u *models.IdmUser) *userRecord {
+	r := &userRecord{
+		Login:       u.Login,
+		Email:       u.Attributes[rest.UserAttrEmail],
+		DisplayName: u.Attributes[rest.UserAttrDisplayName],
+		GroupPath:   rest.GroupFullPath(u),
+		Profile:     u.Attributes[rest.UserAttrProfile],
+	}
+	for _, role := range rest.AssignedRoles(u) {
+		r.Roles = append(r.Roles, role.Label)
+	}
+	return r
+}
+
+func writeUserRecords(out io.Writer, records []*userRecord, format string) error {
+	if format == "json" {
+		if records == nil {
+			records = []*userRecord{}
+		}
+		data, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(out, "%s\n", data)
+		return err
+	}
+	w := csv.NewWriter(out)
+	// Passwords are never exported
+	header := userImportFields[:len(userImportFields)-1]
+	_ = w.Write(header)
+	for _, r := range records {
+		_ = w.Write([]string{r.Login, r.Email, r.DisplayName, r.GroupPath, r.Profile, strings.Join(r.Roles, ";")})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// importUser creates the user described by the record or updates the existing user so that it matches the record.
+func importUser(ctx context.Context, r *userRecord, roles []*models.IdmRole, dryRun bool) *userImportResult {
+	res := &userImportResult{Line: r.Line, Login: r.Login}
+	fail := func(err error) *userImportResult {
+		res.Action = "failed"
+		res.Error = err.Error()
+		return res
+	}
+	if r.Login == "" {
+		return fail(fmt.Errorf("login is missing"))
+	}
+	switch r.Profile {
+	case "", "standard", "shared", "admin":
+	default:
+		return fail(fmt.Errorf("invalid profile %s", r.Profile))
+	}
+	var targetRoles []*models.IdmRole
+	for _, name := range r.Roles {
+		role, err := rest.MatchRole(roles, name)
+		if err != nil {
+			return fail(err)
+		}
+		targetRoles = append(targetRoles, role)
+	}
+
+	user, err := sdkClient.FindUser(ctx, r.Login)
+	if err != nil && !rest.IsNotFound(err) {
+		return fail(err)
+	}
+	isNew := err != nil
+	if isNew {
+		res.Action = "create"
+		user = &models.IdmUser{
+			Login:      r.Login,
+			GroupPath:  r.GroupPath,
+			Attributes: map[string]string{rest.UserAttrProfile: "standard"},
+		}
+		res.Details = append(res.Details, "group: "+r.GroupPath)
+	} else {
+		res.Action = "update"
+		if user.Attributes == nil {
+			user.Attributes = make(map[string]string)
+		}
+		if r.GroupPath != rest.GroupFullPath(user) {
+			res.Details = append(res.Details, fmt.Sprintf("group: %s => %s", rest.GroupFullPath(user), r.GroupPath))
+			user.GroupPath = r.GroupPath
+		}
+	}
+
+	for _, a := range []struct{ key, value string }{
+		{rest.UserAttrEmail, r.Email},
+		{rest.UserAttrDisplayName, r.DisplayName},
+		{rest.UserAttrProfile, r.Profile},
+	} {
+		if a.value == "" || user.Attributes[a.key] == a.value {
+			continue
+		}
+		if isNew {
+			res.Details = append(res.Details, fmt.Sprintf("%s: %s", a.key, a.value))
+		} else {
+			res.Details = append(res.Details, fmt.Sprintf("%s: %s => %s", a.key, rest.ValueOr(user.Attributes[a.key], "-"), a.value))
+		}
+		user.Attributes[a.key] = a.value
+	}
+
+	if len(targetRoles) > 0 {
+		if added, removed := diffRoles(rest.AssignedRoles(user), targetRoles); len(added) > 0 || len(removed) > 0 {
+			if len(added) > 0 {
+				res.Details = append(res.Details, "add roles: "+strings.Join(added, ", "))
+			}
+			if len(removed) > 0 {
+				res.Details = append(res.Details, "remove roles: "+strings.Join(removed, ", "))
+			}
+			user.Roles = rest.WithAssignedRoles(user, targetRoles)
+		}
+	}
+
+	if isNew {
+		user.Password = r.Password
+		if user.Password == "" {
+			if user.Password, err = generatePassword(16); err != nil {
+				return fail(err)
+			}
+			rest.SetUserLock(user, "pass_change", true)
+			if !dryRun {
+				res.Details = append(res.Details, "generated password: "+user.Password)
+			}
+		}
+	} else if len(res.Details) == 0 {
+		res.Action = "unchanged"
+		return res
+	}
+
+	if dryRun {
+		res.Action = "would " + res.Action
+		return res
+	}
+	if _, err = sdkClient.PutUser(ctx, user); err != nil {
+		return fail(err)
+	}
+	res.Action += "d"
+	return res
+}
+
+// diffRoles returns the labels of the roles that must be added and removed to go from the current to the target roles.
+func diffRoles(current, target []*models.IdmRole) (added, removed []string) {
+	has := make(map[string]bool)
+	for _, r := range current {
+		has[r.UUID] = true
+	}
+	wanted := make(map[string]bool)
+	for _, r := range target {
+		wanted[r.UUID] = true
+		if !has[r.UUID] {
+			added = append(added, r.Label)
+		}
+	}
+	for _, r := range current {
+		if !wanted[r.UUID] {
+			removed = append(removed, r.Label)
+		}
+	}
+	return
+}
+
+func generatePassword(length int) (string, error) {
+	const chars = "abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789-_!"
+	b := make([]byte, length)
+	for i := range b {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(chars))))
+		if err != nil {
+			return "", fmt.Errorf("could not generate password: %s", err.Error())
+		}
+		b[i] = chars[n.Int64()]
+	}
+	return string(b), nil
+}
+
+func printUserImportResults(results []*userImportResult) {
+	if userImportFormat == "json" {
+		data, _ := json.MarshalIndent(results, "", "  ")
+		fmt.Printf("%s\n", data)
+		return
+	}
+	counts := make(map[string]int)
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Line", "Login", "Action", "Details"})
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+	for _, r := range results {
+		counts[r.Action]++
+		details := strings.Join(r.Details, "; ")
+		if r.Error != "" {
+			details = r.Error
+		}
+		table.Append([]string{fmt.Sprintf("%d", r.Line), r.Login, r.Action, details})
+	}
+	table.Render()
+
+	var actions []string
+	for a := range counts {
+		actions = append(actions, a)
+	}
+	sort.Strings(actions)
+	var summary []string
+	for _, a := range actions {
+		summary = append(summary, fmt.Sprintf("%d %s", counts[a], a))
+	}
+	fmt.Printf("%d user(s) processed: %s\n", len(results), strings.Join(summary, ", "))
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	// Silently import convey to ease implementation
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUserImportMap(t *testing.T) {
+	Convey("Test mapping of column names", t, func() {
+		mapping, err := userImportMap([]string{"Mail Address=email", "group=groupPath"})
+		So(err, ShouldBeNil)
+		So(mapping["mail address"], ShouldEqual, "email")
+		So(mapping["group"], ShouldEqual, "groupPath")
+		So(mapping["displayname"], ShouldEqual, "displayName")
+		So(mapping["login"], ShouldEqual, "login")
+
+		_, err = userImportMap([]string{"email"})
+		So(err, ShouldNotBeNil)
+		_, err = userImportMap([]string{"phone=mobile"})
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestReadUserCsv(t *testing.T) {
+	Convey("Test reading users from a CSV file", t, func() {
+		mapping, _ := userImportMap([]string{"mail=email"})
+		records, err := readUserCsv(strings.NewReader(`Login,Mail,GroupPath,Roles
+alice, alice@example.com ,/org/sales,Editors; Reviewers
+bob,bob@example.com
+`), mapping)
+		So(err, ShouldBeNil)
+		So(records, ShouldHaveLength, 2)
+		So(records[0], ShouldResemble, &userRecord{
+			Line:      2,
+			Login:     "alice",
+			Email:     "alice@example.com",
+			GroupPath: "/org/sales",
+			Roles:     []string{"Editors", "Reviewers"},
+		})
+		So(records[1].Line, ShouldEqual, 3)
+		So(records[1].GroupPath, ShouldEqual, "")
+
+		Convey("Unknown columns are rejected", func() {
+			_, err := readUserCsv(strings.NewReader("login,phone\nalice,0123\n"), mapping)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
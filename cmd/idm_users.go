@@ -2,12 +2,13 @@ package cmd
 
 import (
 	"fmt"
-	"log"
 
 	"github.com/spf13/cobra"
 
 	"github.com/pydio/cells-sdk-go/v4/client/user_service"
 	"github.com/pydio/cells-sdk-go/v4/models"
+
+	"github.com/pydio/cells-client/v4/rest"
 )
 
 var listUsers = &cobra.Command{
@@ -32,7 +33,7 @@ DESCRIPTION
 		})
 		if err != nil {
 			fmt.Printf("could not list users: %s\n", err.Error())
-			log.Fatal(err)
+			rest.Log.Fatal(err)
 		}
 
 		if len(result.Payload.Users) > 0 {
@@ -0,0 +1,386 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/pydio/cells-sdk-go/v4/models"
+
+	"github.com/pydio/cells-client/v4/rest"
+)
+
+var (
+	workspaceSlug          string
+	workspaceLabel         string
+	workspaceDescription   string
+	workspaceRoots         []string
+	workspaceDefaultRights string
+	workspaceAttributes    string
+	workspaceRmForce       bool
+	workspaceFormat        string
+)
+
+var workspaceCmd = &cobra.Command{
+	Use:     "workspace",
+	Aliases: []string{"workspaces", "ws"},
+	Short:   "Manage the workspaces",
+	Long: `
+DESCRIPTION
+
+  Create, configure and remove workspaces. You must be logged in with an administrator account.
+  Workspaces are designated by their slug, that is the first segment of the paths in the workspace.
+  See the help of respective sub-commands for further details.
+`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cm *cobra.Command, args []string) {
+		_ = cm.Usage()
+	},
+}
+
+var workspaceCreate = &cobra.Command{
+	Use:   "create",
+	Short: "Create a new workspace",
+	Long: `
+DESCRIPTION
+
+  Create a new workspace whose content is made of one or more folders of the datasources.
+  Roots are given as <datasource>:<path>, e.g. pydiods1:/projects/acme, the folders must already exist.
+
+  Default rights are given to all users, use 'r' for read-only access, 'rw' for read and write access
+  or 'none' (the default) to only give access through roles. Additional attributes can be passed as a JSON object.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` idm workspace create --slug acme --label "ACME Project" --root pydiods1:/projects/acme
+  $ ` + os.Args[0] + ` idm workspace create --slug shared --label Shared --root pydiods1:/shared --default-rights rw \
+      --attributes '{"ALLOW_SYNC":true}'
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		if workspaceSlug == "" || workspaceLabel == "" || len(workspaceRoots) == 0 {
+			rest.Log.Fatalln("The --slug, --label and --root flags are required")
+		}
+		if _, err := sdkClient.FindWorkspace(ctx, workspaceSlug); err == nil {
+			rest.Log.Fatalf("A workspace with slug %s already exists\n", workspaceSlug)
+		} else if !rest.IsNotFound(err) {
+			rest.Log.Fatal(err)
+		}
+		ws := &models.IdmWorkspace{
+			Slug:  workspaceSlug,
+			Scope: models.NewIdmWorkspaceScope(models.IdmWorkspaceScopeADMIN),
+		}
+		if err := applyWorkspaceFlags(ctx, cmd.Flags(), ws); err != nil {
+			rest.Log.Fatal(err)
+		}
+		created, err := sdkClient.PutWorkspace(ctx, ws)
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		if workspaceDefaultRights != "none" {
+			if err = sdkClient.SetWorkspaceDefaultRights(ctx, created, workspaceDefaultRights); err != nil {
+				rest.Log.Fatal(err)
+			}
+		}
+		fmt.Printf("Workspace %s has been created with UUID %s\n", created.Slug, created.UUID)
+	},
+}
+
+var workspaceUpdate = &cobra.Command{
+	Use:   "update",
+	Short: "Update an existing workspace",
+	Long: `
+DESCRIPTION
+
+  Update the label, the description, the roots, the default rights or the attributes of a workspace.
+  Only the values that are explicitly passed are modified. When roots are passed, they replace the current ones:
+  the rights that roles had on the former roots are then given on the new ones.
+  Passed attributes are merged with the existing ones, use null to remove an attribute.
+  See '` + os.Args[0] + ` idm workspace create --help' for the details about the flags.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` idm workspace update acme --label "ACME Project (archived)" --default-rights r
+  $ ` + os.Args[0] + ` idm workspace update acme --attributes '{"ALLOW_SYNC":null}'
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		ws, err := sdkClient.FindWorkspace(ctx, args[0])
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		oldRoots := append([]string{}, rest.WorkspaceRootUuids(ws)...)
+		if err = applyWorkspaceFlags(ctx, cmd.Flags(), ws); err != nil {
+			rest.Log.Fatal(err)
+		}
+		updated, err := sdkClient.PutWorkspace(ctx, ws)
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		if cmd.Flags().Changed("root") {
+			// Roles keep the rights they had on the former roots
+			if err = sdkClient.MoveWorkspaceRootAcls(ctx, updated, oldRoots); err != nil {
+				rest.Log.Fatal(err)
+			}
+		}
+		// Default rights are only rewritten when they are explicitly passed or already managed by the attribute
+		rights, managed := rest.WorkspaceAttributes(updated)[rest.WorkspaceAttrDefaultRights].(string)
+		if cmd.Flags().Changed("default-rights") || (cmd.Flags().Changed("root") && managed) {
+			if err = sdkClient.SetWorkspaceDefaultRights(ctx, updated, rights); err != nil {
+				rest.Log.Fatal(err)
+			}
+		}
+		fmt.Printf("Workspace %s has been updated\n", updated.Slug)
+	},
+}
+
+var workspaceRm = &cobra.Command{
+	Use:   "rm",
+	Short: "Remove workspaces",
+	Long: `
+DESCRIPTION
+
+  Remove the passed workspaces. The files and folders of the datasources are not impacted,
+  but all rights that have been given on the workspaces are lost.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` idm workspace rm acme
+  $ ` + os.Args[0] + ` idm workspace rm -f acme former-project
+`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		var workspaces []*models.IdmWorkspace
+		for _, slug := range args {
+			ws, err := sdkClient.FindWorkspace(ctx, slug)
+			if err != nil {
+				rest.Log.Fatal(err)
+			}
+			workspaces = append(workspaces, ws)
+		}
+
+		if !workspaceRmForce {
+			var labels []string
+			for _, ws := range workspaces {
+				labels = append(labels, fmt.Sprintf("%s (%s)", ws.Label, ws.Slug))
+			}
+			fmt.Printf("About to remove %d workspace(s): %s\n", len(workspaces), strings.Join(labels, ", "))
+			if !confirmOrAbort(false) {
+				return
+			}
+		}
+
+		for _, ws := range workspaces {
+			if err := sdkClient.DeleteWorkspace(ctx, ws.Slug); err != nil {
+				rest.Log.Fatal(err)
+			}
+			fmt.Printf("Workspace %s has been removed\n", ws.Slug)
+		}
+	},
+}
+
+var workspaceShow = &cobra.Command{
+	Use:   "show",
+	Short: "Show the details of a workspace",
+	Long: `
+DESCRIPTION
+
+  Show the details of a workspace, including its root nodes and the rights that are given to roles on it.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` idm workspace show acme
+  $ ` + os.Args[0] + ` idm workspace show acme --format json
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		ws, err := sdkClient.FindWorkspace(ctx, args[0])
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		acls, err := sdkClient.SearchAcls(ctx, &models.IdmACLSingleQuery{WorkspaceIDs: []string{ws.UUID}})
+		if err != nil {
+			rest.Log.Fatalf("Could not list ACLs of workspace %s: %s\n", ws.Slug, err.Error())
+		}
+		rights := principalRights(ctx, acls)
+
+		switch workspaceFormat {
+		case "json":
+			data, _ := json.MarshalIndent(map[string]interface{}{"workspace": ws, "rights": rights}, "", "  ")
+			fmt.Printf("%s\n", data)
+		case "table":
+			printWorkspaceDetails(ws, rights)
+		default:
+			cmd.Println("invalid output format, it must be either json or table")
+		}
+	},
+}
+
+func init() {
+	for _, c := range []*cobra.Command{workspaceCreate, workspaceUpdate} {
+		flags := c.Flags()
+		flags.StringVar(&workspaceLabel, "label", "", "Label of the workspace")
+		flags.StringVar(&workspaceDescription, "description", "", "Description of the workspace")
+		flags.StringArrayVar(&workspaceRoots, "root", []string{}, "Root folder in the <datasource>:<path> form, can be repeated")
+		flags.StringVar(&workspaceDefaultRights, "default-rights", "none", "Rights given to all users: r, rw or none")
+		flags.StringVar(&workspaceAttributes, "attributes", "", "Additional attributes as a JSON object")
+	}
+	workspaceCreate.Flags().StringVar(&workspaceSlug, "slug", "", "Slug of the workspace, used in the paths")
+	workspaceRm.Flags().BoolVarP(&workspaceRmForce, "force", "f", false, "Do not ask for user approval")
+	workspaceShow.Flags().StringVar(&workspaceFormat, "format", "table", "Output format table|json")
+
+	workspaceCmd.AddCommand(workspaceCreate, workspaceUpdate, workspaceRm, workspaceShow)
+	idmCmd.AddCommand(workspaceCmd)
+}
+
+// applyWorkspaceFlags sets the values that have been explicitly passed on the command line on the workspace.
+func applyWorkspaceFlags(ctx context.Context, flags *pflag.FlagSet, ws *models.IdmWorkspace) error {
+	if flags.Changed("label") {
+		ws.Label = workspaceLabel
+	}
+	if flags.Changed("description") {
+		ws.Description = workspaceDescription
+	}
+
+	attributes := rest.WorkspaceAttributes(ws)
+	if flags.Changed("attributes") {
+		passed := make(map[string]interface{})
+		if err := json.Unmarshal([]byte(workspaceAttributes), &passed); err != nil {
+			return fmt.Errorf("invalid attributes, they must be a JSON object: %s", err.Error())
+		}
+		for k, v := range passed {
+			if v == nil {
+				delete(attributes, k)
+			} else {
+				attributes[k] = v
+			}
+		}
+	}
+	if flags.Changed("default-rights") {
+		switch workspaceDefaultRights {
+		case "r", "rw":
+			attributes[rest.WorkspaceAttrDefaultRights] = workspaceDefaultRights
+		case "none":
+			attributes[rest.WorkspaceAttrDefaultRights] = ""
+		default:
+			return fmt.Errorf("invalid default rights %s, they must be one of r, rw or none", workspaceDefaultRights)
+		}
+	}
+	data, _ := json.Marshal(attributes)
+	ws.Attributes = string(data)
+
+	if flags.Changed("root") {
+		return setWorkspaceRoots(ctx, ws, workspaceRoots)
+	}
+	return nil
+}
+
+// setWorkspaceRoots replaces the root nodes of the workspace by the passed folders, given in the <datasource>:<path> form.
+func setWorkspaceRoots(ctx context.Context, ws *models.IdmWorkspace, roots []string) error {
+	ws.RootNodes = make(map[string]models.TreeNode)
+	ws.RootUUIDs = nil
+	for _, root := range roots {
+		node, err := sdkClient.StatAdminNode(ctx, workspaceRootPath(root))
+		if err != nil {
+			return err
+		}
+		if !rest.IsFolder(node) {
+			return fmt.Errorf("root %s is not a folder", root)
+		}
+		ws.RootNodes[node.UUID] = *node
+		ws.RootUUIDs = append(ws.RootUUIDs, node.UUID)
+	}
+	return nil
+}
+
+// workspaceRootPath converts a root in the <datasource>:<path> form to a path in the administration tree.
+func workspaceRootPath(root string) string {
+	if ds, p, ok := strings.Cut(root, ":"); ok {
+		return strings.Trim(path.Join(ds, p), "/")
+	}
+	return strings.Trim(root, "/")
+}
+
+func printWorkspaceDetails(ws *models.IdmWorkspace, rights []*principalRight) {
+	attributes := rest.WorkspaceAttributes(ws)
+	defaultRights, _ := attributes[rest.WorkspaceAttrDefaultRights].(string)
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+	table.AppendBulk([][]string{
+		{"UUID", ws.UUID},
+		{"Slug", ws.Slug},
+		{"Label", ws.Label},
+		{"Description", rest.ValueOr(ws.Description, "-")},
+		{"Default rights", rest.ValueOr(defaultRights, "none")},
+		{"Last updated", stampToAbsoluteDate(strconv.Itoa(int(ws.LastUpdated)))},
+	})
+	table.Render()
+
+	var keys []string
+	for k := range attributes {
+		if k != rest.WorkspaceAttrDefaultRights {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) > 0 {
+		sort.Strings(keys)
+		fmt.Println("\nAttributes:")
+		attrTable := tablewriter.NewWriter(os.Stdout)
+		attrTable.SetHeader([]string{"Key", "Value"})
+		attrTable.SetAlignment(tablewriter.ALIGN_LEFT)
+		attrTable.SetAutoWrapText(false)
+		for _, k := range keys {
+			v, _ := json.Marshal(attributes[k])
+			attrTable.Append([]string{k, string(v)})
+		}
+		attrTable.Render()
+	}
+
+	fmt.Println("\nRoot nodes:")
+	rootPaths := make(map[string]string)
+	rootTable := tablewriter.NewWriter(os.Stdout)
+	rootTable.SetHeader([]string{"UUID", "Path"})
+	rootTable.SetAlignment(tablewriter.ALIGN_LEFT)
+	rootTable.SetAutoWrapText(false)
+	for _, id := range rest.WorkspaceRootUuids(ws) {
+		p := "-"
+		if n, ok := ws.RootNodes[id]; ok && n.Path != "" {
+			p = n.Path
+		}
+		rootPaths[id] = p
+		rootTable.Append([]string{id, p})
+	}
+	rootTable.Render()
+
+	fmt.Println("\nRights:")
+	if len(rights) == 0 {
+		fmt.Println("  No rights are given on this workspace")
+		return
+	}
+	rightsTable := tablewriter.NewWriter(os.Stdout)
+	rightsTable.SetHeader([]string{"Role", "Role ID", "Node", "Rights"})
+	rightsTable.SetAlignment(tablewriter.ALIGN_LEFT)
+	rightsTable.SetAutoWrapText(false)
+	for _, r := range rights {
+		node := r.NodeID
+		if p, ok := rootPaths[node]; ok && p != "-" {
+			node = p
+		}
+		rightsTable.Append([]string{rest.ValueOr(r.RoleLabel, "-"), r.RoleID, node, r.Rights})
+	}
+	rightsTable.Render()
+}
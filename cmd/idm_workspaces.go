@@ -2,12 +2,13 @@ package cmd
 
 import (
 	"fmt"
-	"log"
 
 	"github.com/spf13/cobra"
 
 	"github.com/pydio/cells-sdk-go/v4/client/workspace_service"
 	"github.com/pydio/cells-sdk-go/v4/models"
+
+	"github.com/pydio/cells-client/v4/rest"
 )
 
 var listWorkspaces = &cobra.Command{
@@ -33,7 +34,7 @@ DESCRIPTION
 		result, err := apiClient.WorkspaceService.SearchWorkspaces(params)
 		if err != nil {
 			fmt.Printf("could not list workspaces: %s\n", err.Error())
-			log.Fatal(err)
+			rest.Log.Fatal(err)
 		}
 
 		//prints the workspace label
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"os"
 	"strings"
 
@@ -15,6 +14,8 @@ import (
 
 	eeclient "github.com/pydio/cells-enterprise-sdk-go/client"
 	"github.com/pydio/cells-enterprise-sdk-go/client/scheduler_service"
+
+	"github.com/pydio/cells-client/v4/rest"
 )
 
 var (
@@ -118,7 +119,7 @@ EXAMPLES
 		if deleteJobFilter != "" {
 			err := json.Unmarshal([]byte(deleteJobFilter), &filters)
 			if err != nil {
-				log.Fatalf("invalid filter JSON: %v", err)
+				rest.Log.Fatalf("invalid filter JSON: %v", err)
 			}
 			filterMap := make(map[string]any)
 			for _, j := range jobs {
@@ -160,7 +161,7 @@ EXAMPLES
 					fmt.Printf("⚠️  Are you sure you want to delete [%s] job? Type 'yes' to confirm: ", filteredJobs[0].Label)
 					_, err := fmt.Scanln(&doDelete)
 					if err != nil {
-						log.Fatalf("unexpected error while getting user's confirmation: %s", err)
+						rest.Log.Fatalf("unexpected error while getting user's confirmation: %s", err)
 						return
 					}
 
@@ -15,6 +15,8 @@ import (
 	"github.com/pydio/cells-sdk-go/v4/client"
 	"github.com/pydio/cells-sdk-go/v4/client/jobs_service"
 	"github.com/pydio/cells-sdk-go/v4/models"
+
+	"github.com/pydio/cells-client/v4/rest"
 )
 
 var (
@@ -103,7 +105,7 @@ EXAMPLES
 		if filterRaw != "" {
 			err := json.Unmarshal([]byte(filterRaw), &filters)
 			if err != nil {
-				log.Fatalf("invalid filter JSON: %v", err)
+				rest.Log.Fatalf("invalid filter JSON: %v", err)
 			}
 			filterMap := make(map[string]interface{})
 			for _, j := range jobs {
@@ -90,7 +90,7 @@ $` + os.Args[0] + ` meta get -all=true
 			} else {
 				cmd.Printf("Could not get metadata for files at %s, cause: %s\n", p, err.Error())
 			}
-			os.Exit(1)
+			exit(1)
 		}
 		if len(result.Payload.Nodes) == 0 {
 			// Nothing to list: should never happen, we always have at least the current path.
@@ -147,6 +147,7 @@ func init() {
 	flags.StringVarP(&metaGetFormat, "format", "f", "table", "Output format json|table")
 	flags.BoolVarP(&metaGetListNamespaces, "all", "a", false, "Get available namespaces")
 	flags.StringVarP(&metaGetNameSpace, "namespace", "n", "", "Metadata namespace")
+	_ = metaGet.RegisterFlagCompletionFunc("path", completeRemotePaths)
 	metaCmd.AddCommand(metaGet)
 }
 
@@ -14,6 +14,8 @@ import (
 	"github.com/pydio/cells-sdk-go/v4/client/meta_service"
 	"github.com/pydio/cells-sdk-go/v4/client/user_meta_service"
 	"github.com/pydio/cells-sdk-go/v4/models"
+
+	"github.com/pydio/cells-client/v4/rest"
 )
 
 var (
@@ -24,6 +26,7 @@ var (
 	metaSetStringValue  string
 	metaSetNumericValue int
 	metaSetBooleanValue bool
+	metaSetDryRun       bool
 )
 
 const (
@@ -42,9 +45,12 @@ EXAMPLE
 
 # Update usermeta-tag-validation-status meta of node:
 
-$` + os.Args[0] + ` meta set --path=personal/admin/test.txt --operation=update --meta-name=usermeta-tag-validation-status --string-value=Validated
+$` + os.Args[0] + ` meta set --path=personal/admin/test.txt --operation=update --namespace=usermeta-tag-validation-status --string-value=Validated
+
+# Update the same meta for all PDF files of a folder and its sub-folders:
 
-`,
+$` + os.Args[0] + ` meta set --path='personal/admin/**/*.pdf' --operation=update --namespace=usermeta-tag-validation-status --string-value=Validated
+` + globHelp,
 	Run: func(cmd *cobra.Command, args []string) {
 		ctx := cmd.Context()
 		client := sdkClient.GetApiClient()
@@ -54,12 +60,6 @@ $` + os.Args[0] + ` meta set --path=personal/admin/test.txt --operation=update -
 			return
 		}
 
-		node, err := validateFileExist(ctx)
-		if err != nil {
-			cmd.PrintErr(err)
-			return
-		}
-
 		if err := validateMetaNamespace(); err != nil {
 			cmd.PrintErr(err)
 			return
@@ -84,8 +84,26 @@ $` + os.Args[0] + ` meta set --path=personal/admin/test.txt --operation=update -
 		}
 
 		v := getFinalJsonValue(nsDef.Type, uv)
-	
-		do(ctx, client, node, v)
+
+		paths := []string{strings.Trim(metaSetNodePath, "/")}
+		if rest.HasGlob(metaSetNodePath) {
+			paths = expandRemotePaths(ctx, paths, false)
+		}
+		if metaSetDryRun {
+			printNodesSummary(ctx, "updated", paths)
+			return
+		}
+		for _, p := range paths {
+			node, err := validateFileExist(ctx, p)
+			if err != nil {
+				cmd.PrintErr(err)
+				return
+			}
+			if err = do(ctx, client, node, v); err != nil {
+				cmd.PrintErrf("could not update metadata of %s: %s\n", p, err.Error())
+				return
+			}
+		}
 	},
 }
 
@@ -98,6 +116,8 @@ func init() {
 	flags.StringVarP(&metaSetStringValue, "string-value", "s", "", "String-formated metadata value")
 	flags.IntVarP(&metaSetNumericValue, "numeric-value", "r", 0, "String-formated metadata value")
 	flags.BoolVarP(&metaSetBooleanValue, "boolean-value", "b", false, "String-formated metadata value")
+	flags.BoolVar(&metaSetDryRun, "dry-run", false, "Only list the nodes that would be updated")
+	_ = metaSet.RegisterFlagCompletionFunc("path", completeRemotePaths)
 	metaCmd.AddCommand(metaSet)
 }
 
@@ -150,8 +170,7 @@ func validateFilePath() error {
 	return nil
 }
 
-func validateFileExist(ctx context.Context) (*models.TreeNode, error) {
-	p := strings.Trim(metaSetNodePath, "/")
+func validateFileExist(ctx context.Context, p string) (*models.TreeNode, error) {
 	node, exists := sdkClient.StatNode(ctx, p)
 
 	if !exists && node == nil {
@@ -190,6 +209,7 @@ func do(ctx context.Context, client *client.PydioCellsRestAPI, node *models.Tree
 		Context: ctx,
 	}
 	_, err := client.UserMetaService.UpdateUserMeta(params)
+	sdkClient.InvalidateMeta(node.Path)
 	return err
 }
 
@@ -11,6 +11,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -31,10 +32,13 @@ const (
 var (
 	// These commands and respective children do not need an already configured environment.
 	infoCommands = []string{
-		"help", "config", "version", "completion", "oauth", "clear", "doc", "update", "token", "tools",
+		"help", "config", "version", "completion", "oauth", "clear", "doc", "update", "tools",
 		// legacy
 		"configure",
 	}
+	// These commands do not need a configured environment either, but only at the first level:
+	// their names are common words that might also be used as arguments, e.g. "ls link".
+	rootInfoCommands = []string{"link"}
 
 	sdkClient *rest.SdkClient
 
@@ -49,6 +53,9 @@ var (
 	skipKeyring bool
 	skipVerify  bool
 	noCache     bool
+
+	useMetaCache bool
+	metaCacheTTL time.Duration
 )
 
 // RootCmd is the parent of all commands defined in this package.
@@ -94,7 +101,8 @@ ENVIRONMENT
 
 `, PersistentPreRun: func(cmd *cobra.Command, args []string) {
 
-		if len(os.Args) == 1 {
+		if len(os.Args) == 1 || shellMode {
+			// In the interactive shell, the environment has already been set up when the shell started
 			return
 		}
 
@@ -114,6 +122,11 @@ ENVIRONMENT
 				break
 			}
 		}
+		for _, skip := range rootInfoCommands {
+			if os.Args[1] == skip {
+				needSetup = false
+			}
+		}
 
 		// We cannot initialise config path before:
 		// default value is built upon the AppName that can be overwritten by an extending app
@@ -138,6 +151,9 @@ ENVIRONMENT
 		noCache = viper.GetBool("no-cache")
 		skipKeyring = viper.GetBool("skip-keyring")
 		skipVerify = viper.GetBool("skip-verify")
+		// The opt-out flag prevails, e.g. when the cache is enabled by the environment
+		useMetaCache = viper.GetBool("meta-cache") && !viper.GetBool("no-meta-cache")
+		metaCacheTTL = viper.GetDuration("meta-cache-ttl")
 
 		// Tweak to support old flags
 		if viper.GetBool("no_cache") {
@@ -152,6 +168,10 @@ ENVIRONMENT
 
 		if needSetup {
 			e := setUpEnvironment(cmd.Context())
+			if e != nil && os.Args[1] == cobra.ShellCompRequestCmd {
+				// Do not break the completion script, remote paths are simply not completed
+				return
+			}
 			if e != nil {
 				if !os.IsNotExist(e) {
 					rest.Log.Fatalf("unexpected error during initialisation phase: %s", e.Error())
@@ -165,7 +185,7 @@ ENVIRONMENT
 		_ = cmd.Usage()
 	},
 	PersistentPostRun: func(cmd *cobra.Command, args []string) {
-		if sdkClient != nil {
+		if sdkClient != nil && !shellMode {
 			sdkClient.Teardown()
 		}
 	},
@@ -200,6 +220,9 @@ func init() {
 	flags.Bool("skip-verify", false, "By default the Cells Client verifies the validity of TLS certificates for each communication. This option skips TLS certificate verification")
 	flags.Bool("skip-keyring", false, "Explicitly tell the tool to *NOT* try to use a keyring, even if present. Warning: sensitive information will be stored in clear text")
 	flags.Bool("no-cache", false, "Force token refresh at each call. This might slow down scripts with many calls")
+	flags.Bool("meta-cache", false, "Cache the metadata of the remote nodes during the current run, to limit the number of calls to the server")
+	flags.Bool("no-meta-cache", false, "Disable the metadata cache, even if it is enabled by the environment, e.g. with CEC_META_CACHE=true")
+	flags.Duration("meta-cache-ttl", 0, "With --meta-cache, also store the cache on disk and reuse it for this duration in the next calls, e.g. '2m'. Changes made by others might then be missed")
 
 	// Keep backward compatibility until v5 for old flag names
 	replaceMap := map[string]string{}
@@ -266,9 +289,12 @@ func setUpEnvironment(ctx context.Context) error {
 	// Initialize an SDK Client
 	sdkClient, err = rest.NewSdkClient(ctx, c)
 	if err != nil {
-		log.Fatal(err)
+		rest.Log.Fatal(err)
 	}
 	sdkClient.Setup(ctx)
+	if useMetaCache {
+		sdkClient.EnableMetaCache(metaCacheTTL)
+	}
 
 	return nil
 }
@@ -0,0 +1,451 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/chzyer/readline"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/pydio/cells-client/v4/rest"
+)
+
+const shellHistoryFileName = "shell_history"
+
+var (
+	// shellMode is true while the interactive shell is running: commands are then executed in the same process.
+	shellMode bool
+	// shellCwd is the current remote folder of the shell, without leading slash. It is empty at the root level.
+	shellCwd string
+
+	// remoteArgsCommands are the commands whose positional arguments are remote paths, keyed by their path
+	// below the root command. Values are the number of leading positional arguments that are paths, 0 meaning all.
+	remoteArgsCommands = map[string]int{
+		"ls": 0, "rm": 0, "mv": 0, "cp": 0, "mkdir": 0, "lock": 0, "unlock": 0, "edit": 0, "presign": 0,
+		"share": 0, "share create": 0, "share show": 0, "share update": 0, "share rm": 0,
+		"versions ls": 1, "versions get": 1, "versions restore": 1,
+	}
+)
+
+// shellExit is used as panic value to stop the current command with an exit status without leaving the shell.
+type shellExit int
+
+var shellCmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Start an interactive shell",
+	Long: `
+DESCRIPTION
+
+  Start an interactive shell to run commands against your server, without re-initialising
+  the configuration and the connection at each call.
+
+  The shell has a remote current folder: use 'cd' to change it and 'pwd' to display it.
+  Remote paths that do not start with a '/' are then relative to this folder, e.g. with the ls, rm, mv, cp
+  or mkdir commands and for the 'cells://' arguments of the scp command. '..' refers to the parent folder.
+
+  Besides all the commands of the client, the shell provides the following built-in commands:
+   - cd [path]: change the remote current folder, go back to the root without argument
+   - pwd: print the remote current folder
+   - get <remote path> [local folder]: download a file or a folder, in the local current folder by default
+   - put <local path> [remote folder]: upload a file or a folder, in the remote current folder by default
+   - lcd <path> and lpwd: change and print the local current folder
+   - exit or quit: leave the shell, you can also use Ctrl-D
+
+  Use the Tab key to complete command names and remote paths, and the arrow keys to browse the history,
+  which is stored in a ` + shellHistoryFileName + ` file next to your configuration file.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` shell
+  cells:/> cd common-files/reports
+  cells:/common-files/reports> ls -d
+  cells:/common-files/reports> mv 2023.pdf ../archives/
+  cells:/common-files/reports> get summary.xlsx ~/Downloads
+  cells:/common-files/reports> put ./new-report.pdf
+  cells:/common-files/reports> exit
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+
+		rl, err := readline.NewEx(&readline.Config{
+			Prompt:          shellPrompt(),
+			HistoryFile:     filepath.Join(filepath.Dir(configFilePath), shellHistoryFileName),
+			AutoComplete:    &shellCompleter{ctx: ctx},
+			InterruptPrompt: "^C",
+			EOFPrompt:       "exit",
+		})
+		if err != nil {
+			rest.Log.Fatalln("could not start the shell:", err.Error())
+		}
+		defer rl.Close()
+
+		// From now on, fatal errors only abort the current command
+		shellMode = true
+		rest.PanicOnFatal = true
+		_, _ = configureLogger(viper.GetString("log"))
+		defer func() {
+			shellMode = false
+			rest.PanicOnFatal = false
+			_, _ = configureLogger(viper.GetString("log"))
+		}()
+
+		for {
+			line, err := rl.Readline()
+			if err == readline.ErrInterrupt {
+				continue
+			} else if err == io.EOF {
+				return
+			} else if err != nil {
+				rest.Log.Errorln("could not read input:", err.Error())
+				return
+			}
+
+			words, err := splitShellWords(line)
+			if err != nil {
+				fmt.Println("Error:", err.Error())
+				continue
+			}
+			if len(words) == 0 {
+				continue
+			}
+			if words[0] == "exit" || words[0] == "quit" {
+				return
+			}
+			runShellLine(ctx, words)
+			rl.SetPrompt(shellPrompt())
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(shellCmd)
+}
+
+// exit ends the current command with the passed status: it terminates the process, unless we are in the interactive shell.
+func exit(code int) {
+	if shellMode {
+		panic(shellExit(code))
+	}
+	os.Exit(code)
+}
+
+func shellPrompt() string {
+	return fmt.Sprintf("cells:/%s> ", shellCwd)
+}
+
+// runShellLine executes a built-in command or a command of the client and recovers from fatal errors.
+func runShellLine(ctx context.Context, words []string) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(shellExit); !ok {
+				// Fatal logs have already been written before the panic
+				rest.Log.Debugf("command aborted: %v", r)
+			}
+		}
+	}()
+
+	switch words[0] {
+	case "shell":
+		fmt.Println("You are already in the shell")
+	case "pwd":
+		fmt.Println("/" + shellCwd)
+	case "cd":
+		target := ""
+		if len(words) > 1 {
+			target = strings.TrimSuffix(resolveShellPath(words[1]), "/")
+		}
+		if target != "" {
+			if node, ok := sdkClient.StatNode(ctx, target); !ok {
+				fmt.Printf("cd: %s: no such folder\n", words[1])
+				return
+			} else if !rest.IsFolder(node) {
+				fmt.Printf("cd: %s: not a folder\n", words[1])
+				return
+			}
+		}
+		shellCwd = target
+	case "lpwd":
+		if wd, err := os.Getwd(); err == nil {
+			fmt.Println(wd)
+		}
+	case "lcd":
+		if len(words) < 2 {
+			fmt.Println("lcd: please provide the local path")
+		} else if err := os.Chdir(words[1]); err != nil {
+			fmt.Println("lcd:", err.Error())
+		}
+	case "get", "put":
+		executeInShell(ctx, transferWords(words))
+	default:
+		executeInShell(ctx, resolveShellArgs(words))
+	}
+}
+
+// executeInShell runs a command of the client, after resetting the flags that have been set by a previous command.
+func executeInShell(ctx context.Context, args []string) {
+	resetFlags(RootCmd)
+	// The cache is only valid for one command, the nodes might have been modified by others in the meantime
+	sdkClient.InvalidateMeta()
+	RootCmd.SetArgs(args)
+	_ = RootCmd.ExecuteContext(ctx)
+}
+
+// transferWords translates the get and put built-in commands to the corresponding scp command.
+func transferWords(words []string) []string {
+	var flags, paths []string
+	for _, w := range words[1:] {
+		if strings.HasPrefix(w, "-") {
+			flags = append(flags, w)
+		} else {
+			paths = append(paths, w)
+		}
+	}
+	args := []string{"scp"}
+	if words[0] == "get" {
+		local := "."
+		if len(paths) > 1 {
+			local = paths[1]
+		}
+		if len(paths) > 0 {
+			args = append(args, standardPrefix+resolveShellPath(paths[0]), local)
+		}
+	} else {
+		remote := shellCwd
+		if len(paths) > 1 {
+			remote = resolveShellPath(paths[1])
+		}
+		if len(paths) > 0 {
+			args = append(args, paths[0], standardPrefix+remote)
+		}
+	}
+	return append(args, flags...)
+}
+
+// resolveShellArgs converts the relative remote paths of a command to paths from the root of the server.
+func resolveShellArgs(words []string) []string {
+	cmd, _, err := RootCmd.Find(words)
+	if err != nil || cmd == RootCmd {
+		return words
+	}
+	cmdPath := strings.TrimPrefix(cmd.CommandPath(), RootCmd.Name()+" ")
+	maxPaths, isRemoteCmd := remoteArgsCommands[cmdPath]
+
+	var result []string
+	var positional int
+	skipNext := false
+	for i, w := range words {
+		// Skip the command names
+		if i < commandDepth(cmd) {
+			result = append(result, w)
+			continue
+		}
+		if skipNext {
+			skipNext = false
+			result = append(result, w)
+			continue
+		}
+		if strings.HasPrefix(w, "-") {
+			result = append(result, w)
+			skipNext = flagExpectsValue(cmd, w)
+			continue
+		}
+		positional++
+		if strings.HasPrefix(w, standardPrefix) || strings.HasPrefix(w, completionPrefix) {
+			result = append(result, standardPrefix+resolveShellPath(trimRemotePrefix(w)))
+		} else if isRemoteCmd && (maxPaths == 0 || positional <= maxPaths) && !uuidRegexp.MatchString(w) {
+			// Link UUIDs are also accepted by the share sub-commands
+			result = append(result, resolveShellPath(w))
+		} else {
+			result = append(result, w)
+		}
+	}
+	if positional == 0 && cmdPath == "ls" && shellCwd != "" {
+		result = append(result, shellCwd)
+	}
+	return result
+}
+
+// resolveShellPath returns the path from the root of the server. Paths starting with a slash are absolute,
+// other paths are relative to the current folder of the shell. A trailing slash is kept, as it is meaningful
+// for some commands, e.g. mv moves a node inside the target folder when its path ends with a slash.
+func resolveShellPath(p string) string {
+	resolved := path.Clean(p)
+	if !strings.HasPrefix(p, "/") {
+		resolved = path.Clean(path.Join("/", shellCwd, p))
+	}
+	resolved = strings.Trim(resolved, "/")
+	if strings.HasSuffix(p, "/") && resolved != "" {
+		resolved += "/"
+	}
+	return resolved
+}
+
+func commandDepth(cmd *cobra.Command) int {
+	depth := 0
+	for c := cmd; c.HasParent(); c = c.Parent() {
+		depth++
+	}
+	return depth
+}
+
+// flagExpectsValue returns true if the passed flag is not boolean and has no inline value, so that the next word is its value.
+func flagExpectsValue(cmd *cobra.Command, w string) bool {
+	if strings.Contains(w, "=") {
+		return false
+	}
+	var f *pflag.Flag
+	if strings.HasPrefix(w, "--") {
+		f = cmd.Flags().Lookup(strings.TrimPrefix(w, "--"))
+	} else if len(w) == 2 {
+		f = cmd.Flags().ShorthandLookup(w[1:])
+	}
+	return f != nil && f.NoOptDefVal == ""
+}
+
+// resetFlags restores the default values of all flags, as cobra keeps the values between two executions.
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if !f.Changed {
+			return
+		}
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			var values []string
+			if def := strings.Trim(f.DefValue, "[]"); def != "" {
+				values = strings.Split(def, ",")
+			}
+			_ = sv.Replace(values)
+		} else {
+			_ = f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, c := range cmd.Commands() {
+		resetFlags(c)
+	}
+}
+
+// splitShellWords splits a line in words, supporting single and double quotes and backslash escapes.
+func splitShellWords(line string) ([]string, error) {
+	var words []string
+	var current strings.Builder
+	var quote rune
+	inWord, escaped := false, false
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inWord = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	if inWord {
+		words = append(words, current.String())
+	}
+	return words, nil
+}
+
+// shellCompleter completes the command names and the remote paths.
+type shellCompleter struct {
+	ctx context.Context
+}
+
+// Do implements readline.AutoCompleter.
+func (c *shellCompleter) Do(line []rune, pos int) ([][]rune, int) {
+	input := string(line[:pos])
+	words := strings.Fields(input)
+	partial := ""
+	if len(words) > 0 && !strings.HasSuffix(input, " ") {
+		partial = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+
+	var candidates []string
+	if len(words) == 0 {
+		candidates = []string{"cd", "pwd", "get", "put", "lcd", "lpwd", "exit"}
+		for _, sub := range RootCmd.Commands() {
+			if !sub.Hidden && sub.Name() != "shell" {
+				candidates = append(candidates, sub.Name())
+			}
+		}
+		return completionSuffixes(candidates, partial, " "), len([]rune(partial))
+	}
+	if strings.HasPrefix(partial, "-") || words[0] == "lcd" || (words[0] == "put" && len(words) == 1) {
+		return nil, 0
+	}
+
+	// Remote path: list the content of the parent folder
+	prefix := ""
+	for _, p := range []string{standardPrefix, completionPrefix} {
+		if strings.HasPrefix(partial, p) {
+			prefix = p
+		}
+	}
+	typed := strings.TrimPrefix(partial, prefix)
+	dir, base := "", typed
+	if i := strings.LastIndex(typed, "/"); i >= 0 {
+		dir, base = typed[:i+1], typed[i+1:]
+	}
+	folder := resolveShellPath(dir)
+	pattern := "/*"
+	if folder != "" {
+		pattern = path.Join(folder, "*")
+	}
+	nodes, err := sdkClient.GetAllBulkMeta(c.ctx, pattern)
+	if err != nil {
+		return nil, 0
+	}
+	var result [][]rune
+	for _, n := range nodes {
+		name := path.Base(n.Path)
+		if !strings.HasPrefix(name, base) {
+			continue
+		}
+		suffix := " "
+		if rest.IsFolder(n) {
+			suffix = "/"
+		}
+		result = append(result, []rune(strings.TrimPrefix(name, base)+suffix))
+	}
+	return result, len([]rune(base))
+}
+
+func completionSuffixes(candidates []string, partial, suffix string) [][]rune {
+	var result [][]rune
+	for _, c := range candidates {
+		if strings.HasPrefix(c, partial) {
+			result = append(result, []rune(strings.TrimPrefix(c, partial)+suffix))
+		}
+	}
+	return result
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"testing"
+
+	// Silently import convey to ease implementation
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSplitShellWords(t *testing.T) {
+	Convey("Test splitting of shell lines", t, func() {
+		words, err := splitShellWords("  ls -d\tcommon-files  ")
+		So(err, ShouldBeNil)
+		So(words, ShouldResemble, []string{"ls", "-d", "common-files"})
+
+		words, err = splitShellWords(`mv "common-files/my report.pdf" 'personal/it''s here/' a\ b`)
+		So(err, ShouldBeNil)
+		So(words, ShouldResemble, []string{"mv", "common-files/my report.pdf", "personal/its here/", "a b"})
+
+		Convey("Quotes and escapes", func() {
+			words, err := splitShellWords(`echo "say \"hi\"" 'no \escape' "" x`)
+			So(err, ShouldBeNil)
+			So(words, ShouldResemble, []string{"echo", `say "hi"`, `no \escape`, "", "x"})
+
+			words, err = splitShellWords("")
+			So(err, ShouldBeNil)
+			So(words, ShouldBeEmpty)
+
+			_, err = splitShellWords(`ls "common-files`)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestResolveShellArgs(t *testing.T) {
+	Convey("Test resolution of remote paths in the shell", t, func() {
+		defer func(cwd string) { shellCwd = cwd }(shellCwd)
+		shellCwd = "common-files/reports"
+
+		So(resolveShellPath("2023.pdf"), ShouldEqual, "common-files/reports/2023.pdf")
+		So(resolveShellPath("../archives/"), ShouldEqual, "common-files/archives/")
+		So(resolveShellPath("/personal-files"), ShouldEqual, "personal-files")
+		So(resolveShellPath("/"), ShouldEqual, "")
+
+		So(resolveShellArgs([]string{"mv", "2023.pdf", "../archives/"}), ShouldResemble,
+			[]string{"mv", "common-files/reports/2023.pdf", "common-files/archives/"})
+
+		Convey("Paths of sub-commands are also resolved", func() {
+			So(resolveShellArgs([]string{"versions", "restore", "2023.pdf", "v2"}), ShouldResemble,
+				[]string{"versions", "restore", "common-files/reports/2023.pdf", "v2"})
+			id := "0c2d7a4e-6f3b-4f0e-9d1b-2a7e5c8b9f10"
+			So(resolveShellArgs([]string{"share", "rm", "--force", id, "2023.pdf"}), ShouldResemble,
+				[]string{"share", "rm", "--force", id, "common-files/reports/2023.pdf"})
+		})
+	})
+}
@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"log"
 	"os"
 
 	"github.com/spf13/cobra"
@@ -39,9 +38,9 @@ DESCRIPTION
 		result, err := apiClient.ConfigService.ListDataSources(params)
 		if err != nil {
 			if rest.IsForbiddenError(err) {
-				log.Fatalf("[Forbidden access] You do not have necessary permission to list the datasources at %s", sdkClient.GetConfig().Url)
+				rest.Log.Fatalf("[Forbidden access] You do not have necessary permission to list the datasources at %s", sdkClient.GetConfig().Url)
 			}
-			log.Fatalf("Could not list data sources of %s, cause: %s", sdkClient.GetConfig().Url, err.Error())
+			rest.Log.Fatalf("Could not list data sources of %s, cause: %s", sdkClient.GetConfig().Url, err.Error())
 		}
 
 		//prints the name of the datasources retrieved previously
@@ -71,7 +70,7 @@ var resyncDs = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 
 		if len(args) != 1 {
-			log.Fatal(fmt.Errorf("please provide the name of the datasource to resync"))
+			rest.Log.Fatal(fmt.Errorf("please provide the name of the datasource to resync"))
 		}
 		dsName := args[0]
 
@@ -81,7 +80,7 @@ var resyncDs = &cobra.Command{
 
 		_, err := sdkClient.GetApiClient().JobsService.UserCreateJob(params)
 		if err != nil {
-			log.Fatalf("could not start the sync job for ds %s, cause: %s", dsName, err.Error())
+			rest.Log.Fatalf("could not start the sync job for ds %s, cause: %s", dsName, err.Error())
 		}
 		fmt.Printf("Starting resync on %s \n", dsName)
 	},
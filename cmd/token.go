@@ -0,0 +1,271 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/manifoldco/promptui"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	cellsSdk "github.com/pydio/cells-sdk-go/v4"
+
+	eeclient "github.com/pydio/cells-enterprise-sdk-go/client"
+	"github.com/pydio/cells-enterprise-sdk-go/client/enterprise_token_service"
+	eemodels "github.com/pydio/cells-enterprise-sdk-go/models"
+
+	"github.com/pydio/cells-client/v4/rest"
+)
+
+var (
+	tokenLabel       string
+	tokenExpires     string
+	tokenScopes      []string
+	tokenUser        string
+	tokenSave        bool
+	tokenSaveForce   bool
+	tokenFormat      string
+	tokenRevokeForce bool
+)
+
+// maxTokenExpiration is the latest expiration date of a token, as the server stores it as a 32 bits timestamp.
+var maxTokenExpiration = time.Unix(math.MaxInt32, 0)
+
+var tokenCmd = &cobra.Command{
+	Use:     "token",
+	Aliases: []string{"tokens"},
+	Short:   "Manage Personal Access Tokens",
+	Long: `
+DESCRIPTION
+
+  Create, list and revoke the Personal Access Tokens (PAT) that are used to authenticate against the server,
+  typically from scripts or CI pipelines. The token service is only available with Cells Enterprise.
+  See the help of respective sub-commands for further details.
+`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cm *cobra.Command, args []string) {
+		_ = cm.Usage()
+	},
+}
+
+var tokenCreate = &cobra.Command{
+	Use:   "create",
+	Short: "Create a new Personal Access Token",
+	Long: `
+DESCRIPTION
+
+  Create a new Personal Access Token for the current user or, if you are an administrator, for another user.
+  The expiration is either a duration starting from now (e.g. 12h or 30d) or a date (2006-01-02).
+  Scopes can optionally restrict what the token gives access to.
+
+  The token is only displayed once. With the --save flag, it is also stored as a new profile in your local
+  configuration, so that it can be used right away. If a profile already exists for the same user on the same server,
+  the command refuses to replace it unless the --force flag is also set: this is typically used to automatically
+  rotate the credentials of a CI pipeline.
+
+  Tokens cannot expire after ` + maxTokenExpiration.Format("2006-01-02") + `.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` token create --label ci --expires 30d
+  $ ` + os.Args[0] + ` token create --label backup --expires 2030-01-01 --user alice --scopes rest:read
+  $ ` + os.Args[0] + ` token create --label ci --expires 30d --save --force
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if tokenLabel == "" {
+			rest.Log.Fatalln("The --label flag is required")
+		}
+		expiresAt, err := parseExpiration(tokenExpires)
+		if err != nil {
+			rest.Log.Fatal(err)
+		}
+		if !expiresAt.After(time.Now()) {
+			rest.Log.Fatalf("Expiration %s is in the past\n", tokenExpires)
+		}
+		if expiresAt.After(maxTokenExpiration) {
+			rest.Log.Fatalf("Expiration %s is too far in the future, tokens cannot expire after %s\n",
+				tokenExpires, maxTokenExpiration.Format("2006-01-02"))
+		}
+		login := tokenUser
+		if login == "" {
+			login = sdkClient.GetConfig().User
+		}
+
+		current := sdkClient.GetConfig()
+		newConf := rest.DefaultCecConfig()
+		if tokenSave {
+			newConf.AuthType = cellsSdk.AuthTypePat
+			newConf.Url = current.Url
+			newConf.SkipVerify = current.SkipVerify
+			newConf.User = login
+			newConf.SkipKeyring = skipKeyring
+			// Check before creating the token, so that no useless token is left on the server
+			if cl, e := rest.GetConfigList(); e == nil && !tokenSaveForce {
+				if _, exists := cl.Configs[rest.ConfigID(newConf)]; exists {
+					rest.Log.Fatalf("A profile already exists for %s on this server, use the --force flag to replace it\n", login)
+				}
+			}
+		}
+
+		params := enterprise_token_service.NewGeneratePersonalAccessTokenParams()
+		params.Body = &eemodels.EntPersonalAccessTokenRequest{
+			UserLogin: login,
+			Label:     tokenLabel,
+			ExpiresAt: int32(expiresAt.Unix()),
+			Scopes:    tokenScopes,
+		}
+		params.Context = cmd.Context()
+		result, err := tokenService().GeneratePersonalAccessToken(params)
+		if err != nil {
+			rest.Log.Fatalf("Could not create token for %s: %s\n", login, err.Error())
+		}
+		pat := result.Payload.AccessToken
+
+		if !tokenSave {
+			fmt.Printf("Token %s has been created for %s, it expires on %s.\n", tokenLabel, login, expiresAt.Format("2006-01-02 15:04"))
+			fmt.Println("Copy it now, it will not be displayed again:")
+			fmt.Println(pat)
+			return
+		}
+
+		newConf.IdToken = pat
+		id, err := rest.StoreConfig(newConf, false)
+		if err != nil {
+			// Do not lose the token that has just been created
+			fmt.Println(pat)
+			rest.Log.Fatalf("Token has been created but could not be stored: %s\n", err.Error())
+		}
+		fmt.Printf("%s Token %s has been created for %s and stored in profile %s, it expires on %s.\n",
+			promptui.IconGood, tokenLabel, login, id, expiresAt.Format("2006-01-02 15:04"))
+	},
+}
+
+var tokenLs = &cobra.Command{
+	Use:   "ls",
+	Short: "List Personal Access Tokens",
+	Long: `
+DESCRIPTION
+
+  List the Personal Access Tokens of the current user or, if you are an administrator, of another user.
+  Token values are never displayed.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` token ls
+  $ ` + os.Args[0] + ` token ls --user alice --format json
+`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		login := tokenUser
+		if login == "" {
+			login = sdkClient.GetConfig().User
+		}
+		params := enterprise_token_service.NewListPersonalAccessTokensParams()
+		params.Body = &eemodels.EntListAccessTokensRequest{ByUserLogin: login}
+		params.Context = cmd.Context()
+		result, err := tokenService().ListPersonalAccessTokens(params)
+		if err != nil {
+			rest.Log.Fatalf("Could not list tokens of %s: %s\n", login, err.Error())
+		}
+		tokens := result.Payload.Tokens
+
+		switch tokenFormat {
+		case "json":
+			if tokens == nil {
+				tokens = []*eemodels.AuthPersonalAccessToken{}
+			}
+			data, _ := json.MarshalIndent(tokens, "", "  ")
+			fmt.Printf("%s\n", data)
+		case "table":
+			if len(tokens) == 0 {
+				fmt.Printf("No token found for %s.\n", login)
+				return
+			}
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"ID", "Label", "User", "Scopes", "Created", "Expires"})
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetAutoWrapText(false)
+			for _, t := range tokens {
+				table.Append([]string{
+					t.UUID, t.Label, t.UserLogin, rest.ValueOr(strings.Join(t.Scopes, ", "), "-"),
+					formatTokenTime(t.CreatedAt), formatTokenTime(t.ExpiresAt),
+				})
+			}
+			table.Render()
+		default:
+			cmd.Println("invalid output format, it must be either json or table")
+		}
+	},
+}
+
+var tokenRevoke = &cobra.Command{
+	Use:   "revoke",
+	Short: "Revoke Personal Access Tokens",
+	Long: `
+DESCRIPTION
+
+  Revoke the Personal Access Tokens with the passed IDs, as listed by the ls command.
+  The tokens can then not be used anymore to authenticate.
+
+EXAMPLES
+
+  $ ` + os.Args[0] + ` token revoke 7c1b9ea4-8a64-4d1e-9a52-52e6d1b9a5f0
+  $ ` + os.Args[0] + ` token revoke -f 7c1b9ea4-8a64-4d1e-9a52-52e6d1b9a5f0 0e3bd7a1-3a4c-4f8e-8e5e-c5d0f3b72c1d
+`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Printf("About to revoke %d token(s): %s\n", len(args), strings.Join(args, ", "))
+		if !confirmOrAbort(tokenRevokeForce) {
+			return
+		}
+		for _, id := range args {
+			params := enterprise_token_service.NewRevokePersonalAccessTokenParams()
+			params.UUID = id
+			params.Context = cmd.Context()
+			if _, err := tokenService().RevokePersonalAccessToken(params); err != nil {
+				rest.Log.Fatalf("Could not revoke token %s: %s\n", id, err.Error())
+			}
+			fmt.Printf("Token %s has been revoked\n", id)
+		}
+	},
+}
+
+func init() {
+	createFlags := tokenCreate.Flags()
+	createFlags.StringVar(&tokenLabel, "label", "", "Label of the token, to recognize it afterwards")
+	createFlags.StringVar(&tokenExpires, "expires", "30d", "Expiration, either a duration (e.g. 12h or 30d) or a date (2006-01-02)")
+	createFlags.StringArrayVar(&tokenScopes, "scopes", []string{}, "Restrict the token to this scope, can be repeated")
+	createFlags.StringVar(&tokenUser, "user", "", "Login of the owner of the token, defaults to the current user (admin only)")
+	createFlags.BoolVar(&tokenSave, "save", false, "Store the token as a new profile in the local configuration")
+	createFlags.BoolVar(&tokenSaveForce, "force", false, "With --save, replace the existing profile of the same user on the same server")
+
+	tokenLs.Flags().StringVar(&tokenUser, "user", "", "Only list the tokens of this user, defaults to the current user (admin only)")
+	tokenLs.Flags().StringVar(&tokenFormat, "format", "table", "Output format table|json")
+
+	tokenRevoke.Flags().BoolVarP(&tokenRevokeForce, "force", "f", false, "Do not ask for user approval")
+
+	tokenCmd.AddCommand(tokenCreate, tokenLs, tokenRevoke)
+	RootCmd.AddCommand(tokenCmd)
+}
+
+// tokenService returns the token service of the enterprise API, using the transport of the current connection.
+func tokenService() enterprise_token_service.ClientService {
+	entClient := eeclient.Default
+	entClient.SetTransport(sdkClient.GetApiClient().Transport)
+	return entClient.EnterpriseTokenService
+}
+
+// formatTokenTime formats a timestamp in seconds as returned by the token service.
+func formatTokenTime(timestamp string) string {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || ts == 0 {
+		return "-"
+	}
+	return time.Unix(ts, 0).Format("2006-01-02 15:04")
+}
@@ -51,7 +51,7 @@ EXAMPLES
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) != 1 {
 			cmd.Printf("Please provide a version to parse\n")
-			os.Exit(1)
+			exit(1)
 		}
 		versionStr := args[0]
 		_, err := hashivers.NewVersion(versionStr)
@@ -61,11 +61,11 @@ EXAMPLES
 			} else {
 				fmt.Println("0")
 			}
-			os.Exit(0)
+			exit(0)
 		} else {
 			if err != nil {
 				cmd.Printf("[%s] is *not* a valid version\n", versionStr)
-				os.Exit(1)
+				exit(1)
 			}
 		}
 	},
@@ -103,7 +103,7 @@ EXAMPLES
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) != 1 {
 			cmd.Printf("Please provide a single version to be parsed\n")
-			os.Exit(1)
+			exit(1)
 		}
 		versionStr := args[0]
 
@@ -125,11 +125,11 @@ EXAMPLES
 			} else {
 				fmt.Println("0")
 			}
-			os.Exit(0)
+			exit(0)
 		} else {
 			if !resultOK {
 				cmd.Println(errMessage)
-				os.Exit(1)
+				exit(1)
 			}
 			// Valid release version and not in quiet mode, we simply do nothing.
 		}
@@ -169,7 +169,7 @@ EXAMPLE
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) != 2 {
 			cmd.Printf("Please provide two versions to be compared\n")
-			os.Exit(1)
+			exit(1)
 		}
 
 		v1Str := args[0]
@@ -199,11 +199,11 @@ EXAMPLE
 			} else {
 				fmt.Println("0")
 			}
-			os.Exit(0)
+			exit(0)
 		} else {
 			if !resultOK {
 				cmd.Println(errMessage)
-				os.Exit(1)
+				exit(1)
 			}
 			// Valid and ordered release versions, nothing to do.
 		}
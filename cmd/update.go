@@ -3,7 +3,6 @@ package cmd
 import (
 	"context"
 	"fmt"
-	"log"
 	"math"
 	"os"
 	"sync"
@@ -44,7 +43,7 @@ DESCRIPTION
 
 		binaries, e := rest.LoadUpdates(context.Background(), defaultChannel)
 		if e != nil {
-			log.Fatalf("Cannot list packages in the %s channel: %s", defaultChannel, e.Error())
+			rest.Log.Fatalf("Cannot list packages in the %s channel: %s", defaultChannel, e.Error())
 		}
 		if len(binaries) == 0 {
 			c := color.New(color.FgGreen)
@@ -98,7 +97,7 @@ DESCRIPTION
 				}
 			}
 			if apply == nil {
-				log.Fatal("Cannot find the requested version")
+				rest.Log.Fatal("Cannot find the requested version")
 			}
 
 			c := color.New(color.FgBlack)
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"log"
 	"os"
 	"runtime"
 	"runtime/debug"
@@ -11,6 +10,8 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/pydio/cells-client/v4/common"
+
+	"github.com/pydio/cells-client/v4/rest"
 )
 
 var cellsVersionTpl = `{{.PackageLabel}}
@@ -100,11 +101,11 @@ DESCRIPTION
 
 		tmpl, err := template.New("cells").Parse(runningTmpl)
 		if err != nil {
-			log.Fatalln("failed to parse template", err)
+			rest.Log.Fatalln("failed to parse template", err)
 		}
 
 		if err = tmpl.Execute(os.Stdout, cv); err != nil {
-			log.Fatalln("could not execute template", err)
+			rest.Log.Fatalln("could not execute template", err)
 		}
 	},
 }
@@ -0,0 +1,49 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pydio/cells-sdk-go/v4/client/acl_service"
+	"github.com/pydio/cells-sdk-go/v4/models"
+)
+
+// SearchNodeAcls lists the ACLs that are directly attached to the nodes with the passed UUIDs.
+// Note that the server only returns ACLs to users with sufficient permissions, typically administrators.
+func (client *SdkClient) SearchNodeAcls(ctx context.Context, nodeUuids ...string) ([]*models.IdmACL, error) {
+	return client.SearchAcls(ctx, &models.IdmACLSingleQuery{NodeIDs: nodeUuids})
+}
+
+// SearchRoleAcls lists the ACLs that are defined for the roles with the passed UUIDs.
+func (client *SdkClient) SearchRoleAcls(ctx context.Context, roleUuids ...string) ([]*models.IdmACL, error) {
+	return client.SearchAcls(ctx, &models.IdmACLSingleQuery{RoleIDs: roleUuids})
+}
+
+// SearchAcls lists the ACLs that match at least one of the passed queries.
+func (client *SdkClient) SearchAcls(ctx context.Context, queries ...*models.IdmACLSingleQuery) ([]*models.IdmACL, error) {
+	params := &acl_service.SearchAclsParams{
+		Body:    &models.RestSearchACLRequest{Queries: queries},
+		Context: ctx,
+	}
+	result, err := client.GetApiClient().ACLService.SearchAcls(params)
+	if err != nil {
+		return nil, err
+	}
+	return result.Payload.ACLs, nil
+}
+
+// PutAcl creates an ACL.
+func (client *SdkClient) PutAcl(ctx context.Context, acl *models.IdmACL) error {
+	if _, err := client.GetApiClient().ACLService.PutACL(&acl_service.PutACLParams{Body: acl, Context: ctx}); err != nil {
+		return fmt.Errorf("could not create ACL %s for role %s, cause: %s", acl.Action.Name, acl.RoleID, err.Error())
+	}
+	return nil
+}
+
+// DeleteAcl removes the ACLs that match the passed one.
+func (client *SdkClient) DeleteAcl(ctx context.Context, acl *models.IdmACL) error {
+	if _, err := client.GetApiClient().ACLService.DeleteACL(&acl_service.DeleteACLParams{Body: acl, Context: ctx}); err != nil {
+		return fmt.Errorf("could not delete ACL %s for role %s, cause: %s", acl.Action.Name, acl.RoleID, err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pydio/cells-sdk-go/v4/client/share_service"
+	"github.com/pydio/cells-sdk-go/v4/models"
+)
+
+// Known ACL action names for Cell members.
+const (
+	AclRead  = "read"
+	AclWrite = "write"
+)
+
+// ListCells lists the Cells that are owned by the current user.
+func (client *SdkClient) ListCells(ctx context.Context) ([]*models.RestCell, error) {
+	resources, err := client.listSharedResources(ctx, models.ListSharedResourcesRequestListShareTypeCELLS)
+	if err != nil {
+		return nil, err
+	}
+	// A Cell with more than one root node appears once per root
+	known := make(map[string]bool)
+	var cells []*models.RestCell
+	for _, r := range resources {
+		for _, c := range r.Cells {
+			if c == nil || known[c.UUID] {
+				continue
+			}
+			known[c.UUID] = true
+			cells = append(cells, c)
+		}
+	}
+	return cells, nil
+}
+
+// GetCell retrieves a Cell by its UUID.
+func (client *SdkClient) GetCell(ctx context.Context, cellUuid string) (*models.RestCell, error) {
+	params := share_service.NewGetCellParamsWithContext(ctx)
+	params.UUID = cellUuid
+	resp, err := client.GetApiClient().ShareService.GetCell(params)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve cell %s, cause: %s", cellUuid, err.Error())
+	}
+	return resp.Payload, nil
+}
+
+// PutCell creates or updates a Cell. When createEmptyRoot is true, the server creates a new empty folder
+// to be used as root of the Cell: it is typically used when no root nodes are defined.
+func (client *SdkClient) PutCell(ctx context.Context, cell *models.RestCell, createEmptyRoot bool) (*models.RestCell, error) {
+	params := share_service.NewPutCellParamsWithContext(ctx)
+	params.Body = &models.RestPutCellRequest{
+		Room:            cell,
+		CreateEmptyRoot: createEmptyRoot,
+	}
+	resp, err := client.GetApiClient().ShareService.PutCell(params)
+	if err != nil {
+		return nil, fmt.Errorf("call to PutCell for %s has failed, cause: %s", cell.Label, err.Error())
+	}
+	return resp.Payload, nil
+}
+
+// DeleteCell removes a Cell by its UUID. The content of the Cell root folders is not impacted.
+func (client *SdkClient) DeleteCell(ctx context.Context, cellUuid string) error {
+	params := share_service.NewDeleteCellParamsWithContext(ctx)
+	params.UUID = cellUuid
+	resp, err := client.GetApiClient().ShareService.DeleteCell(params)
+	if err != nil {
+		return fmt.Errorf("could not delete cell %s, cause: %s", cellUuid, err.Error())
+	}
+	if !resp.Payload.Success {
+		return fmt.Errorf("server could not delete cell %s", cellUuid)
+	}
+	return nil
+}
+
+// CellAclActions converts a short permission string (r, w or rw) to the corresponding ACL actions.
+func CellAclActions(rights string) ([]*models.IdmACLAction, error) {
+	var actions []*models.IdmACLAction
+	switch rights {
+	case "r":
+		actions = append(actions, &models.IdmACLAction{Name: AclRead, Value: "1"})
+	case "w":
+		actions = append(actions, &models.IdmACLAction{Name: AclWrite, Value: "1"})
+	case "rw", "wr":
+		actions = append(actions,
+			&models.IdmACLAction{Name: AclRead, Value: "1"},
+			&models.IdmACLAction{Name: AclWrite, Value: "1"},
+		)
+	default:
+		return nil, fmt.Errorf("invalid rights %s, known values are r, w or rw", rights)
+	}
+	return actions, nil
+}
+
+// CellAclRights returns the short permission string (r, w or rw) for the passed ACL actions.
+func CellAclRights(actions []*models.IdmACLAction) string {
+	var read, write bool
+	for _, a := range actions {
+		switch a.Name {
+		case AclRead:
+			read = true
+		case AclWrite:
+			write = true
+		}
+	}
+	rights := ""
+	if read {
+		rights += "r"
+	}
+	if write {
+		rights += "w"
+	}
+	return rights
+}
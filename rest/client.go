@@ -64,6 +64,7 @@ type SdkClient struct {
 	configStore   cellsSdk.ConfigRefresher
 	apiClient     *client.PydioCellsRestAPI
 	s3Client      *s3.Client
+	metaCache     *metaCache
 
 	// stopRefreshChan enable stopping the OAuth auto refresh mechanism at teardown
 	stopRefreshChan chan struct{}
@@ -149,6 +150,7 @@ func (client *SdkClient) Teardown() {
 	if client.stopRefreshChan != nil {
 		close(client.stopRefreshChan)
 	}
+	client.saveMetaCache()
 }
 
 // GetConfig simply exposes the current SdkConfig
@@ -350,12 +350,20 @@ func (store *CellsConfigStore) RefreshIfRequired(ctx context.Context, sdkConfig
 }
 
 func UpdateConfig(newConf *CecConfig) error {
+	_, err := StoreConfig(newConf, true)
+	return err
+}
+
+// StoreConfig checks the connection with the new configuration and adds it to the configuration list,
+// replacing the configuration of the same user on the same server if any. It returns the ID of the stored configuration,
+// that becomes the active one if activate is true or if there is no active configuration yet.
+func StoreConfig(newConf *CecConfig, activate bool) (string, error) {
 
 	var err error
 
 	uname, e := RetrieveSessionLogin(newConf)
 	if e != nil {
-		return fmt.Errorf("could not connect to distant server with provided parameters. Discarding change")
+		return "", fmt.Errorf("could not connect to distant server with provided parameters. Discarding change")
 	}
 	newConf.SdkConfig.User = uname
 	id := createID(newConf)
@@ -377,12 +385,20 @@ func UpdateConfig(newConf *CecConfig) error {
 
 	cl, err := GetConfigList()
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	cl.Configs[id] = persistedConf
-	cl.ActiveConfigID = id
-	return cl.SaveConfigFile()
+	if activate || cl.ActiveConfigID == "" {
+		cl.ActiveConfigID = id
+	}
+	return id, cl.SaveConfigFile()
+}
+
+// ConfigID returns the ID of the profile under which the passed configuration is stored,
+// a profile with the same ID is replaced by StoreConfig.
+func ConfigID(c *CecConfig) string {
+	return createID(c)
 }
 
 // Helpers
@@ -0,0 +1,201 @@
+package rest
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pydio/cells-client/v4/common/hasher"
+)
+
+// DiffType qualifies a difference between two trees.
+type DiffType string
+
+// Known types of differences.
+const (
+	DiffAdded       DiffType = "added"
+	DiffRemoved     DiffType = "removed"
+	DiffModified    DiffType = "modified"
+	DiffTypeChanged DiffType = "type-changed"
+)
+
+// DiffEntry describes a single difference between two trees. Path is relative to the root of the compared trees.
+type DiffEntry struct {
+	Path   string   `json:"path"`
+	Type   DiffType `json:"type"`
+	IsDir  bool     `json:"isDir"`
+	Reason string   `json:"reason,omitempty"`
+}
+
+// ignoredNames are the names of the system files that are never compared, on top of the hidden files.
+var ignoredNames = map[string]bool{
+	"Thumbs.db":    true,
+	"desktop.ini":  true,
+	"$RECYCLE.BIN": true,
+}
+
+// IsIgnoredName returns true for the files and folders that are skipped by the synchronization:
+// hidden items, whose name starts with a dot, Office lock files and system files.
+func IsIgnoredName(name string) bool {
+	return strings.HasPrefix(name, ".") || strings.HasPrefix(name, "~$") || ignoredNames[name]
+}
+
+// diffIndex walks the tree under the passed folder with the same walkers as the transfers and returns all its
+// descendants, keyed by their slash-separated path relative to the folder. The folder itself is not included
+// and the ignored items are skipped with their descendants.
+func diffIndex(ctx context.Context, root *CrawlNode) (map[string]*CrawlNode, error) {
+	// Without target, the walkers do not check anything and simply list all the nodes
+	toTransfer, toCreate, _, err := root.Walk(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	prefix := root.RelPath + "/"
+	index := make(map[string]*CrawlNode)
+	for _, n := range append(toTransfer, toCreate...) {
+		if n == root {
+			continue
+		}
+		rel := strings.TrimPrefix(n.RelPath, prefix)
+		ignored := false
+		for _, segment := range strings.Split(rel, "/") {
+			ignored = ignored || IsIgnoredName(segment)
+		}
+		if !ignored {
+			index[rel] = n
+		}
+	}
+	return index, nil
+}
+
+// Diff compares the trees under the from and to nodes, that must both be folders.
+// Added entries only exist under to, removed entries only exist under from.
+// By default, files are considered as modified when their size or modification time differ.
+// When checksum is true, the modification time is ignored and the block hashes of files with the same size are compared.
+// Hidden and system files are ignored on both sides, see IsIgnoredName.
+func Diff(ctx context.Context, from, to *CrawlNode, checksum bool) ([]*DiffEntry, error) {
+	if !from.IsDir || !to.IsDir {
+		return nil, fmt.Errorf("only folders can be compared")
+	}
+	fromIndex, err := diffIndex(ctx, from)
+	if err != nil {
+		return nil, fmt.Errorf("could not list %s: %s", from.FullPath, err.Error())
+	}
+	toIndex, err := diffIndex(ctx, to)
+	if err != nil {
+		return nil, fmt.Errorf("could not list %s: %s", to.FullPath, err.Error())
+	}
+
+	var entries []*DiffEntry
+	for p, f := range fromIndex {
+		t, ok := toIndex[p]
+		if !ok {
+			// Only report the top-most removed folder
+			if !reportedByParent(p, toIndex) {
+				entries = append(entries, &DiffEntry{Path: p, Type: DiffRemoved, IsDir: f.IsDir})
+			}
+			continue
+		}
+		if f.IsDir != t.IsDir {
+			entries = append(entries, &DiffEntry{Path: p, Type: DiffTypeChanged, IsDir: t.IsDir, Reason: fmt.Sprintf("%s -> %s", nodeKind(f), nodeKind(t))})
+			continue
+		}
+		if f.IsDir {
+			continue
+		}
+		reason, err := compareFiles(ctx, f, t, checksum)
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			entries = append(entries, &DiffEntry{Path: p, Type: DiffModified, Reason: reason})
+		}
+	}
+	for p, t := range toIndex {
+		if _, ok := fromIndex[p]; !ok && !reportedByParent(p, fromIndex) {
+			entries = append(entries, &DiffEntry{Path: p, Type: DiffAdded, IsDir: t.IsDir})
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+	return entries, nil
+}
+
+// reportedByParent returns true if the parent folder of p is missing or is not a folder in the other index:
+// in such case, the difference is already reported at the parent level.
+func reportedByParent(p string, other map[string]*CrawlNode) bool {
+	parent := path.Dir(p)
+	if parent == "." {
+		return false
+	}
+	o, ok := other[parent]
+	return !ok || !o.IsDir
+}
+
+func compareFiles(ctx context.Context, f, t *CrawlNode, checksum bool) (string, error) {
+	if f.Size != t.Size {
+		return fmt.Sprintf("size %d -> %d", f.Size, t.Size), nil
+	}
+	if !checksum {
+		// Remote modification times have a precision of one second
+		if f.MTime.Unix() != t.MTime.Unix() {
+			return fmt.Sprintf("mtime %s -> %s", f.MTime.Format("2006-01-02 15:04:05"), t.MTime.Format("2006-01-02 15:04:05")), nil
+		}
+		return "", nil
+	}
+	h1, err := f.blockHash(ctx)
+	if err != nil {
+		return "", err
+	}
+	h2, err := t.blockHash(ctx)
+	if err != nil {
+		return "", err
+	}
+	if h1 != h2 {
+		return fmt.Sprintf("hash %s -> %s", h1, h2), nil
+	}
+	return "", nil
+}
+
+// blockHash returns the hash of a file, computed with the same algorithm as the one used by the server.
+// For remote files, we rely on the hash that is stored by the server and only download the file when it is not known.
+func (c *CrawlNode) blockHash(ctx context.Context) (string, error) {
+	var reader io.Reader
+	if c.IsLocal {
+		file, err := os.Open(c.FullPath)
+		if err != nil {
+			return "", err
+		}
+		defer file.Close()
+		reader = file
+	} else {
+		if h, ok := c.TreeNode.MetaStore["x-cells-hash"]; ok && strings.Trim(h, "\"") != "" {
+			return strings.Trim(h, "\""), nil
+		}
+		r, _, err := c.sdkClient.GetFile(ctx, c.FullPath)
+		if err != nil {
+			return "", fmt.Errorf("could not download %s to compute its hash: %s", c.FullPath, err.Error())
+		}
+		if closer, ok := r.(io.Closer); ok {
+			defer closer.Close()
+		}
+		reader = r
+	}
+	bH := hasher.NewBlockHash(md5.New(), hasher.DefaultBlockSize)
+	if _, err := io.Copy(bH, reader); err != nil {
+		return "", fmt.Errorf("could not compute hash for %s: %s", c.FullPath, err.Error())
+	}
+	return hex.EncodeToString(bH.Sum(nil)), nil
+}
+
+func nodeKind(c *CrawlNode) string {
+	if c.IsDir {
+		return "folder"
+	}
+	return "file"
+}
@@ -2,6 +2,7 @@ package rest
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/pydio/cells-sdk-go/v4/client/tree_service"
@@ -10,7 +11,11 @@ import (
 
 const pageSize = 100
 
+// StatNode retrieves the node at the passed path, using the metadata cache when it is enabled.
 func (client *SdkClient) StatNode(ctx context.Context, pathToFile string) (*models.TreeNode, bool) {
+	if n, known := client.cachedNode(strings.Trim(pathToFile, "/")); known {
+		return n, n != nil
+	}
 	exists := false
 	var node *models.TreeNode
 	e := RetryCallback(func() error {
@@ -36,6 +41,9 @@ func (client *SdkClient) StatNode(ctx context.Context, pathToFile string) (*mode
 		Log.Debugf("Could not stat node at %s, cause: %s", pathToFile, e.Error())
 		return nil, false
 	}
+	if exists {
+		client.cacheNodes(node)
+	}
 	return node, exists
 }
 
@@ -80,6 +88,7 @@ func (client *SdkClient) DeleteNodes(ctx context.Context, paths []string, perman
 		RemovePermanently: perm,
 	}
 	res, err := client.GetApiClient().TreeService.DeleteNodes(params)
+	client.InvalidateMeta(paths...)
 	if err != nil {
 		e = err
 		return
@@ -116,5 +125,9 @@ func (client *SdkClient) GetAllBulkMeta(ctx context.Context, path string) (nodes
 			pg = res.Payload.Pagination
 		}
 	}
+	client.cacheNodes(nodes...)
+	if folder := strings.TrimSuffix(path, "*"); strings.HasSuffix(folder, "/") && !HasGlob(folder) {
+		client.cacheListed(folder)
+	}
 	return nodes, nil
 }
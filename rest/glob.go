@@ -0,0 +1,272 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pydio/cells-sdk-go/v4/models"
+)
+
+// RecycleBinName is the name of the special folder at the root of the workspaces where trashed items are stored.
+// Wildcards never match it: it must be explicitly named in a pattern.
+const RecycleBinName = "recycle_bin"
+
+// HasGlob returns true if the passed path contains some glob special characters.
+func HasGlob(p string) bool {
+	return strings.ContainsAny(p, "*?[{")
+}
+
+// IsPattern returns true if the passed remote path must be expanded as a glob pattern: it contains some glob
+// special characters and no node exists at this literal path, so that names like "report [v1].pdf" still work as is.
+func (client *SdkClient) IsPattern(ctx context.Context, p string) bool {
+	if !HasGlob(p) {
+		return false
+	}
+	_, exists := client.StatNode(ctx, strings.Trim(p, "/"))
+	return !exists
+}
+
+// ExpandGlob finds all the remote paths that match the passed pattern. Supported syntax is:
+//   - '*' matches any sequence of characters in a single path segment
+//   - '?' matches a single character
+//   - '[abc]' or '[a-z]' matches a character in the class
+//   - '{a,b}' matches either a or b, braces can be nested
+//   - '**' as a full segment matches any number of nested folders
+//
+// Malformed segments, e.g. with an unclosed class like "draft [old", only match literally.
+// Listings are paged so that large folders are fully expanded. The returned paths are sorted.
+func (client *SdkClient) ExpandGlob(ctx context.Context, pattern string) ([]string, error) {
+	e := newGlobExpander(client.listChildren, func(ctx context.Context, p string) bool {
+		_, ok := client.StatNode(ctx, p)
+		return ok
+	})
+	return e.expand(ctx, pattern)
+}
+
+// globExpander expands patterns segment by segment. Listings are memoized, as '**' reaches the same folders
+// through several branches.
+type globExpander struct {
+	list     func(ctx context.Context, folder string) ([]*models.TreeNode, error)
+	exists   func(ctx context.Context, p string) bool
+	listings map[string][]*models.TreeNode
+	found    map[string]bool
+}
+
+func newGlobExpander(list func(ctx context.Context, folder string) ([]*models.TreeNode, error), exists func(ctx context.Context, p string) bool) *globExpander {
+	return &globExpander{
+		list:     list,
+		exists:   exists,
+		listings: make(map[string][]*models.TreeNode),
+		found:    make(map[string]bool),
+	}
+}
+
+func (e *globExpander) expand(ctx context.Context, pattern string) ([]string, error) {
+	for _, p := range ExpandBraces(strings.Trim(pattern, "/")) {
+		segments := strings.Split(p, "/")
+		for _, s := range segments {
+			if s == "" {
+				return nil, fmt.Errorf("invalid pattern %s: it contains an empty path segment", pattern)
+			}
+		}
+		if err := e.expandSegments(ctx, "", segments); err != nil {
+			return nil, err
+		}
+	}
+	var result []string
+	for p := range e.found {
+		result = append(result, p)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// children lists the direct children of a folder only once.
+func (e *globExpander) children(ctx context.Context, folder string) ([]*models.TreeNode, error) {
+	if nodes, ok := e.listings[folder]; ok {
+		return nodes, nil
+	}
+	nodes, err := e.list(ctx, folder)
+	if err != nil {
+		return nil, err
+	}
+	e.listings[folder] = nodes
+	return nodes, nil
+}
+
+func (e *globExpander) expandSegments(ctx context.Context, base string, segments []string) error {
+	if len(segments) == 0 {
+		e.found[base] = true
+		return nil
+	}
+	segment, remaining := segments[0], segments[1:]
+
+	if !HasGlob(segment) {
+		next := path.Join(base, segment)
+		if len(remaining) == 0 {
+			if e.exists(ctx, next) {
+				e.found[next] = true
+			}
+			return nil
+		}
+		return e.expandSegments(ctx, next, remaining)
+	}
+
+	children, err := e.children(ctx, base)
+	if err != nil {
+		return err
+	}
+
+	if segment == "**" {
+		// Zero folder: only if there is something left to match, so that 'folder/**' does not match the folder itself
+		if len(remaining) > 0 {
+			if err = e.expandSegments(ctx, base, remaining); err != nil {
+				return err
+			}
+		}
+		for _, c := range children {
+			name := path.Base(c.Path)
+			if name == RecycleBinName {
+				continue
+			}
+			if len(remaining) == 0 {
+				e.found[strings.Trim(c.Path, "/")] = true
+			}
+			if IsFolder(c) {
+				if err = e.expandSegments(ctx, strings.Trim(c.Path, "/"), segments); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	for _, c := range children {
+		name := path.Base(c.Path)
+		if name == RecycleBinName {
+			continue
+		}
+		if !MatchSegment(segment, name) {
+			continue
+		}
+		if len(remaining) > 0 && !IsFolder(c) {
+			continue
+		}
+		if err = e.expandSegments(ctx, strings.Trim(c.Path, "/"), remaining); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MatchSegment returns true if the name matches the pattern of a path segment, or if it is literally equal to it:
+// a folder named "draft [old]" can then be traversed by a pattern like "draft [old]/*.txt".
+// Malformed patterns, e.g. "draft [old", only match literally.
+func MatchSegment(segment, name string) bool {
+	if name == segment {
+		return true
+	}
+	ok, _ := path.Match(segment, name)
+	return ok
+}
+
+// listChildren lists all the direct children of a remote folder, or the workspaces if the path is empty.
+func (client *SdkClient) listChildren(ctx context.Context, folder string) ([]*models.TreeNode, error) {
+	if folder == "" {
+		return client.GetAllBulkMeta(ctx, "/*")
+	}
+	if _, ok := client.StatNode(ctx, folder); !ok {
+		// Silently ignore missing folders: it simply does not match
+		return nil, nil
+	}
+	nodes, err := client.GetAllBulkMeta(ctx, path.Join(folder, "*"))
+	if err != nil {
+		return nil, fmt.Errorf("could not list content of %s: %s", folder, err.Error())
+	}
+	return nodes, nil
+}
+
+// ExpandBraces expands the {a,b} alternatives of a pattern, e.g. "img/{a,b{1,2}}.jpg" gives
+// "img/a.jpg", "img/b1.jpg" and "img/b2.jpg". Unbalanced braces are kept as is.
+func ExpandBraces(pattern string) []string {
+	start, end := -1, -1
+	depth := 0
+	for i, r := range pattern {
+		switch r {
+		case '{':
+			if depth == 0 {
+				start = i
+			}
+			depth++
+		case '}':
+			if depth == 0 {
+				continue
+			}
+			depth--
+			if depth == 0 {
+				end = i
+			}
+		}
+		if end >= 0 {
+			break
+		}
+	}
+	if start < 0 || end < 0 {
+		return []string{pattern}
+	}
+
+	// Split the content of the braces on top level commas
+	var alternatives []string
+	inner := pattern[start+1 : end]
+	depth, last := 0, 0
+	for i, r := range inner {
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				alternatives = append(alternatives, inner[last:i])
+				last = i + 1
+			}
+		}
+	}
+	alternatives = append(alternatives, inner[last:])
+
+	var result []string
+	for _, a := range alternatives {
+		result = append(result, ExpandBraces(pattern[:start]+a+pattern[end+1:])...)
+	}
+	return result
+}
+
+// RemoveNested removes from the passed list the paths that have an ancestor in the list.
+func RemoveNested(paths []string) []string {
+	known := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		known[p] = true
+	}
+	var result []string
+	for _, p := range paths {
+		nested := false
+		for parent := path.Dir(p); parent != "." && parent != "/"; parent = path.Dir(parent) {
+			if known[parent] {
+				nested = true
+				break
+			}
+		}
+		if !nested {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// IsFolder returns true if the passed node is a folder.
+func IsFolder(node *models.TreeNode) bool {
+	return node.Type != nil && *node.Type == models.TreeNodeTypeCOLLECTION
+}
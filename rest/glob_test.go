@@ -0,0 +1,95 @@
+package rest
+
+import (
+	"context"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/pydio/cells-sdk-go/v4/models"
+
+	// Silently import convey to ease implementation
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestExpandBraces(t *testing.T) {
+	Convey("Test brace expansion", t, func() {
+		So(ExpandBraces("ws/folder/*.jpg"), ShouldResemble, []string{"ws/folder/*.jpg"})
+		So(ExpandBraces("ws/{a,b}.txt"), ShouldResemble, []string{"ws/a.txt", "ws/b.txt"})
+		So(ExpandBraces("ws/{a,b{1,2}}.jpg"), ShouldResemble, []string{"ws/a.jpg", "ws/b1.jpg", "ws/b2.jpg"})
+		So(ExpandBraces("{ws1,ws2}/{x,y}"), ShouldResemble, []string{"ws1/x", "ws1/y", "ws2/x", "ws2/y"})
+		So(ExpandBraces("ws/{,old-}file"), ShouldResemble, []string{"ws/file", "ws/old-file"})
+		So(ExpandBraces("ws/unbalanced{a,b"), ShouldResemble, []string{"ws/unbalanced{a,b"})
+	})
+}
+
+func TestRemoveNested(t *testing.T) {
+	Convey("Test removal of nested paths", t, func() {
+		So(RemoveNested([]string{"ws/a", "ws/a b", "ws/a/c", "ws/a/c/d", "ws/b"}), ShouldResemble, []string{"ws/a", "ws/a b", "ws/b"})
+		So(RemoveNested([]string{"ws/x/y", "ws/z"}), ShouldResemble, []string{"ws/x/y", "ws/z"})
+	})
+}
+
+func TestMatchSegment(t *testing.T) {
+	Convey("Test matching of path segments", t, func() {
+		So(MatchSegment("*.pdf", "report.pdf"), ShouldBeTrue)
+		So(MatchSegment("report-?.pdf", "report-1.pdf"), ShouldBeTrue)
+		So(MatchSegment("*.pdf", "report.txt"), ShouldBeFalse)
+
+		Convey("Names with glob characters match themselves", func() {
+			So(HasGlob("report [v1].pdf"), ShouldBeTrue)
+			So(MatchSegment("report [v1].pdf", "report [v1].pdf"), ShouldBeTrue)
+			So(MatchSegment("draft [old", "draft [old"), ShouldBeTrue)
+			// The class still applies to other names
+			So(MatchSegment("report [v1].pdf", "report v.pdf"), ShouldBeTrue)
+			So(MatchSegment("report [v1].pdf", "report [v2].pdf"), ShouldBeFalse)
+		})
+	})
+}
+
+func TestGlobExpander(t *testing.T) {
+	Convey("Test expansion of patterns on a remote tree", t, func() {
+		tree := map[string][]string{
+			"":                {"ws/"},
+			"ws":              {"ws/a/", "ws/draft [old", "ws/report.pdf"},
+			"ws/a":            {"ws/a/b/", "ws/a/notes.pdf"},
+			"ws/a/b":          {"ws/a/b/deep.pdf"},
+			"ws/draft [old":   nil,
+			"ws/a/b/deep.pdf": nil,
+		}
+		listed := make(map[string]int)
+		list := func(_ context.Context, folder string) ([]*models.TreeNode, error) {
+			listed[folder]++
+			var nodes []*models.TreeNode
+			for _, c := range tree[folder] {
+				n := &models.TreeNode{Path: strings.TrimSuffix(c, "/"), Type: models.NewTreeNodeType(models.TreeNodeTypeLEAF)}
+				if strings.HasSuffix(c, "/") {
+					n.Type = models.NewTreeNodeType(models.TreeNodeTypeCOLLECTION)
+				}
+				nodes = append(nodes, n)
+			}
+			return nodes, nil
+		}
+		exists := func(_ context.Context, p string) bool {
+			for _, c := range tree[path.Dir(p)] {
+				if strings.TrimSuffix(c, "/") == p {
+					return true
+				}
+			}
+			return false
+		}
+
+		Convey("Each folder is only listed once with **", func() {
+			found, err := newGlobExpander(list, exists).expand(context.Background(), "ws/**/*.pdf")
+			So(err, ShouldBeNil)
+			So(found, ShouldResemble, []string{"ws/a/b/deep.pdf", "ws/a/notes.pdf", "ws/report.pdf"})
+			So(listed, ShouldResemble, map[string]int{"ws": 1, "ws/a": 1, "ws/a/b": 1})
+		})
+
+		Convey("Malformed segments match literally", func() {
+			found, err := newGlobExpander(list, exists).expand(context.Background(), "ws/draft [old")
+			So(err, ShouldBeNil)
+			So(found, ShouldResemble, []string{"ws/draft [old"})
+		})
+	})
+}
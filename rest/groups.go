@@ -0,0 +1,118 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/pydio/cells-sdk-go/v4/client/user_service"
+	"github.com/pydio/cells-sdk-go/v4/models"
+)
+
+// ListGroups retrieves the groups that are under the passed parent group, and their descendants if recursive is true.
+func (client *SdkClient) ListGroups(ctx context.Context, parentPath string, recursive bool) ([]*models.IdmUser, error) {
+	request := &models.RestSearchUserRequest{
+		Queries: []*models.IdmUserSingleQuery{{
+			GroupPath: "/" + strings.Trim(parentPath, "/"),
+			Recursive: recursive,
+			NodeType:  models.NewIdmNodeType(models.IdmNodeTypeGROUP),
+		}},
+		Limit: strconv.Itoa(pageSize),
+	}
+	var groups []*models.IdmUser
+	for {
+		result, err := client.GetApiClient().UserService.SearchUsers(&user_service.SearchUsersParams{Body: request, Context: ctx})
+		if err != nil {
+			return nil, fmt.Errorf("could not list groups of %s, cause: %s", parentPath, err.Error())
+		}
+		groups = append(groups, result.Payload.Groups...)
+		if len(result.Payload.Groups) < pageSize {
+			break
+		}
+		request.Offset = strconv.Itoa(len(groups))
+	}
+	return groups, nil
+}
+
+// CreateGroup creates a group at the passed full path, e.g. /org/team. Parent groups must already exist.
+func (client *SdkClient) CreateGroup(ctx context.Context, fullPath, displayName string) (*models.IdmUser, error) {
+	fullPath = "/" + strings.Trim(fullPath, "/")
+	label := path.Base(fullPath)
+	group := &models.IdmUser{
+		IsGroup:    true,
+		GroupLabel: label,
+		GroupPath:  path.Dir(fullPath),
+	}
+	if displayName != "" {
+		group.Attributes = map[string]string{UserAttrDisplayName: displayName}
+	}
+	return client.putGroup(ctx, group)
+}
+
+// MoveGroup renames and/or moves a group with all its members and sub-groups to the passed full path.
+// Passing the current full path simply stores the other modifications of the group, e.g. its attributes or roles.
+//
+// Groups are updated with the same call as for creation, with the parent path and the label of the group:
+// as the group was read from the server, its UUID is also sent and the server updates it in place,
+// moving its members and sub-groups along. This is checked on the returned group, so that a server
+// that would rather create a new sibling group is detected, reported, and the new group is removed.
+// The destination is checked first: an existing group is never overwritten.
+func (client *SdkClient) MoveGroup(ctx context.Context, group *models.IdmUser, newFullPath string) (*models.IdmUser, error) {
+	if group.UUID == "" {
+		return nil, fmt.Errorf("cannot update group %s: it has no UUID, it must first be retrieved from the server", GroupFullPath(group))
+	}
+	newFullPath = "/" + strings.Trim(newFullPath, "/")
+	if newFullPath != GroupFullPath(group) {
+		siblings, err := client.ListGroups(ctx, path.Dir(newFullPath), false)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range siblings {
+			if GroupFullPath(s) == newFullPath {
+				return nil, fmt.Errorf("cannot move group %s: a group already exists at %s", GroupFullPath(group), newFullPath)
+			}
+		}
+	}
+	group.GroupLabel = path.Base(newFullPath)
+	group.GroupPath = path.Dir(newFullPath)
+	stored, err := client.putGroup(ctx, group)
+	if err != nil {
+		return nil, err
+	}
+	if stored.UUID != group.UUID {
+		// Do not leave the unwanted copy behind, the destination was checked to be free
+		if e := client.DeleteGroup(ctx, stored); e != nil {
+			Log.Warnf("could not remove group %s that was created by mistake: %s", newFullPath, e.Error())
+		}
+		return nil, fmt.Errorf("group %s has been stored as a new group with UUID %s instead of updating group %s", newFullPath, stored.UUID, group.UUID)
+	}
+	return stored, nil
+}
+
+// DeleteGroup removes a group. Note that the server also removes all its members and sub-groups.
+func (client *SdkClient) DeleteGroup(ctx context.Context, group *models.IdmUser) error {
+	fullPath := GroupFullPath(group)
+	params := &user_service.DeleteUserParams{
+		FullPath: fullPath,
+		Context:  ctx,
+	}
+	if _, err := client.GetApiClient().UserService.DeleteUser(params); err != nil {
+		return fmt.Errorf("could not delete group %s, cause: %s", fullPath, err.Error())
+	}
+	return nil
+}
+
+func (client *SdkClient) putGroup(ctx context.Context, group *models.IdmUser) (*models.IdmUser, error) {
+	params := &user_service.PutUserParams{
+		Login:   group.GroupLabel,
+		Body:    group,
+		Context: ctx,
+	}
+	result, err := client.GetApiClient().UserService.PutUser(params)
+	if err != nil {
+		return nil, fmt.Errorf("could not store group %s, cause: %s", path.Join(group.GroupPath, group.GroupLabel), err.Error())
+	}
+	return result.Payload, nil
+}
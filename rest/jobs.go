@@ -92,6 +92,8 @@ func (client *SdkClient) GetCurrentJobStatus(ctx context.Context, jobID string)
 
 // MonitorJob monitors a job status every second.
 func (client *SdkClient) MonitorJob(ctx context.Context, jobID string) (err error) {
+	// Jobs can modify any node: we do not try to guess which ones and rather drop the cache once the job is done
+	defer client.InvalidateMeta()
 	i := 0
 	for {
 		status, _, _, e := client.GetCurrentJobStatus(ctx, jobID)
@@ -0,0 +1,70 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pydio/cells-sdk-go/v4/client/user_meta_service"
+	"github.com/pydio/cells-sdk-go/v4/models"
+)
+
+// MetaContentLock is the reserved namespace used by the server to flag a file as locked by a user.
+// Its value is the login of the lock owner.
+const MetaContentLock = "content_lock"
+
+// LockOwner returns the login of the user that currently holds a lock on the passed node,
+// or an empty string if the node is not locked.
+func LockOwner(node *models.TreeNode) string {
+	if node == nil || node.MetaStore == nil {
+		return ""
+	}
+	if v, ok := node.MetaStore[MetaContentLock]; ok {
+		return strings.Trim(v, "\"")
+	}
+	return ""
+}
+
+// IsLockedByOther returns true if the passed node is locked by another user than the current one.
+func (client *SdkClient) IsLockedByOther(node *models.TreeNode) bool {
+	owner := LockOwner(node)
+	return owner != "" && owner != client.GetConfig().User
+}
+
+// LockNode flags the passed node as locked by the current user.
+func (client *SdkClient) LockNode(ctx context.Context, node *models.TreeNode) error {
+	value, err := json.Marshal(client.GetConfig().User)
+	if err != nil {
+		return err
+	}
+	return client.updateContentLock(ctx, node, string(value), models.UpdateUserMetaRequestUserMetaOpPUT)
+}
+
+// UnlockNode removes the lock on the passed node. The server only accepts
+// this for the lock owner or for users with sufficient permissions.
+func (client *SdkClient) UnlockNode(ctx context.Context, node *models.TreeNode) error {
+	return client.updateContentLock(ctx, node, "", models.UpdateUserMetaRequestUserMetaOpDELETE)
+}
+
+func (client *SdkClient) updateContentLock(ctx context.Context, node *models.TreeNode, value string, op models.UpdateUserMetaRequestUserMetaOp) error {
+	params := &user_meta_service.UpdateUserMetaParams{
+		Body: &models.IdmUpdateUserMetaRequest{
+			MetaDatas: []*models.IdmUserMeta{
+				{
+					Namespace: MetaContentLock,
+					NodeUUID:  node.UUID,
+					JSONValue: value,
+				},
+			},
+			Operation: &op,
+		},
+		Context: ctx,
+	}
+	_, err := client.GetApiClient().UserMetaService.UpdateUserMeta(params)
+	client.InvalidateMeta(node.Path)
+	if err != nil {
+		return fmt.Errorf("could not update lock on %s, cause: %s", node.Path, err.Error())
+	}
+	return nil
+}
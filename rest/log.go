@@ -13,6 +13,10 @@ import (
 var (
 	atomicLevel zap.AtomicLevel
 	Log         *zap.SugaredLogger
+	// PanicOnFatal makes fatal logs panic rather than exit, so that the caller can recover, e.g. in the interactive shell.
+	PanicOnFatal bool
+	// logToStderr sends the console logs to the standard error rather than to the standard output, see LogToStderr.
+	logToStderr bool
 )
 
 func currentLogLevel() zapcore.Level {
@@ -23,10 +27,22 @@ func IsDebugEnabled() bool { return currentLogLevel() <= zapcore.DebugLevel }
 
 func IsInfoEnabled() bool { return currentLogLevel() <= zapcore.InfoLevel }
 
+// LogToStderr rebuilds the logger at the current level, so that it writes to the standard error when enabled.
+// This keeps the standard output parsable when a command prints a machine format, e.g. JSON.
+func LogToStderr(enabled bool) {
+	logToStderr = enabled
+	SetLogger(currentLogLevel())
+}
+
 func SetLogger(level zapcore.Level) (logger *zap.Logger) {
 
 	atomicLevel = zap.NewAtomicLevelAt(level) // Set initial level to debug
 
+	var opts []zap.Option
+	if PanicOnFatal {
+		opts = append(opts, zap.WithFatalHook(zapcore.WriteThenPanic))
+	}
+
 	if level == zapcore.DebugLevel {
 		config := zap.NewProductionConfig()
 		config.Encoding = "console" // plain text logs
@@ -36,8 +52,12 @@ func SetLogger(level zapcore.Level) (logger *zap.Logger) {
 		config.Level = atomicLevel
 
 		// Create the logger with the custom configuration
-		logger, _ = config.Build()
+		logger, _ = config.Build(opts...)
 	} else {
+		out := os.Stdout
+		if logToStderr {
+			out = os.Stderr
+		}
 		core := zapcore.NewCore(
 			zapcore.NewConsoleEncoder(zapcore.EncoderConfig{
 				MessageKey:     "message",
@@ -52,12 +72,12 @@ func SetLogger(level zapcore.Level) (logger *zap.Logger) {
 				EncodeDuration: nil,
 				EncodeCaller:   nil,
 			}),
-			zapcore.Lock(os.Stdout),
+			zapcore.Lock(out),
 			zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
 				return lvl >= zapcore.InfoLevel
 			}),
 		)
-		logger = zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
+		logger = zap.New(core, append(opts, zap.AddCaller(), zap.AddCallerSkip(1))...)
 	}
 
 	Log = logger.Sugar()
@@ -0,0 +1,239 @@
+package rest
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pydio/cells-sdk-go/v4/client/tree_service"
+	"github.com/pydio/cells-sdk-go/v4/models"
+)
+
+// metaCache keeps the nodes that have been retrieved from the server during the current run, keyed by their path.
+// When all children of a folder have been listed, the folder is flagged so that missing children are known without a request.
+// Entries can also be persisted on the disk, they are then reused by the next runs until they expire.
+type metaCache struct {
+	sync.RWMutex
+	nodes  map[string]*cachedNode
+	listed map[string]bool
+
+	ttl  time.Duration
+	file string
+}
+
+type cachedNode struct {
+	Node     *models.TreeNode `json:"node"`
+	CachedAt time.Time        `json:"cachedAt"`
+}
+
+// EnableMetaCache activates the metadata cache for this client: StatNode and GetAllBulkMeta results are then kept
+// in memory and invalidated by the writes performed by this client. When ttl is positive, the cache is also
+// stored on the disk for the current account, and entries that are younger than ttl are reused by the next runs.
+// Note that changes made by other clients are not seen until the persisted entries expire.
+func (client *SdkClient) EnableMetaCache(ttl time.Duration) {
+	c := &metaCache{
+		nodes:  make(map[string]*cachedNode),
+		listed: make(map[string]bool),
+		ttl:    ttl,
+	}
+	if ttl > 0 {
+		if dir, err := os.UserCacheDir(); err == nil {
+			h := sha1.Sum([]byte(id(client.GetConfig())))
+			c.file = filepath.Join(dir, "cells-client", "meta", hex.EncodeToString(h[:])+".json")
+			c.load()
+		}
+	}
+	client.metaCache = c
+}
+
+// MetaCacheEnabled returns true if the metadata cache has been enabled for this client.
+func (client *SdkClient) MetaCacheEnabled() bool {
+	return client.metaCache != nil
+}
+
+// InvalidateMeta removes the passed paths and their descendants from the metadata cache, or clears the whole cache
+// if no path is given. It must be called after each operation that modifies the nodes on the server.
+func (client *SdkClient) InvalidateMeta(paths ...string) {
+	c := client.metaCache
+	if c == nil {
+		return
+	}
+	c.Lock()
+	defer c.Unlock()
+	if len(paths) == 0 {
+		c.nodes = make(map[string]*cachedNode)
+		c.listed = make(map[string]bool)
+		return
+	}
+	for _, p := range paths {
+		p = strings.Trim(p, "/")
+		for k := range c.nodes {
+			if k == p || strings.HasPrefix(k, p+"/") {
+				delete(c.nodes, k)
+			}
+		}
+		for k := range c.listed {
+			if k == p || strings.HasPrefix(k, p+"/") {
+				delete(c.listed, k)
+			}
+		}
+		delete(c.listed, parentPath(p))
+	}
+}
+
+// PrefetchChildren lists the passed remote folder with paged BulkStatNodes requests, so that the following
+// StatNode calls on its children are answered from the cache. It does nothing if the cache is disabled.
+func (client *SdkClient) PrefetchChildren(ctx context.Context, folder string) error {
+	c := client.metaCache
+	if c == nil {
+		return nil
+	}
+	folder = strings.Trim(folder, "/")
+	c.RLock()
+	done := c.listed[folder]
+	c.RUnlock()
+	if done {
+		return nil
+	}
+	pattern := "/*"
+	if folder != "" {
+		pattern = folder + "/*"
+	}
+	_, err := client.GetAllBulkMeta(ctx, pattern)
+	return err
+}
+
+// StatNodes retrieves the nodes at the passed paths with as few BulkStatNodes requests as possible.
+// The returned map is keyed by path, without leading and trailing slashes, and only contains the nodes that exist.
+func (client *SdkClient) StatNodes(ctx context.Context, paths []string) (map[string]*models.TreeNode, error) {
+	found := make(map[string]*models.TreeNode, len(paths))
+	var missing []string
+	for _, p := range paths {
+		p = strings.Trim(p, "/")
+		if n, known := client.cachedNode(p); known {
+			if n != nil {
+				found[p] = n
+			}
+			continue
+		}
+		missing = append(missing, p)
+	}
+
+	for i := 0; i < len(missing); i += pageSize {
+		end := i + pageSize
+		if end > len(missing) {
+			end = len(missing)
+		}
+		params := tree_service.NewBulkStatNodesParamsWithContext(ctx)
+		params.Body = &models.RestGetBulkMetaRequest{
+			Limit:     int32(end - i),
+			NodePaths: missing[i:end],
+		}
+		res, err := client.GetApiClient().TreeService.BulkStatNodes(params)
+		if err != nil {
+			return nil, err
+		}
+		client.cacheNodes(res.Payload.Nodes...)
+		for _, n := range res.Payload.Nodes {
+			found[strings.Trim(n.Path, "/")] = n
+		}
+	}
+	return found, nil
+}
+
+// cachedNode returns the node at the passed path and true if the cache knows whether it exists.
+func (client *SdkClient) cachedNode(p string) (*models.TreeNode, bool) {
+	c := client.metaCache
+	if c == nil {
+		return nil, false
+	}
+	c.RLock()
+	defer c.RUnlock()
+	if n, ok := c.nodes[p]; ok {
+		return n.Node, true
+	}
+	if p != "" && c.listed[parentPath(p)] {
+		// The parent has been fully listed: the node does not exist
+		return nil, true
+	}
+	return nil, false
+}
+
+func (client *SdkClient) cacheNodes(nodes ...*models.TreeNode) {
+	c := client.metaCache
+	if c == nil {
+		return
+	}
+	c.Lock()
+	defer c.Unlock()
+	now := time.Now()
+	for _, n := range nodes {
+		if n != nil {
+			c.nodes[strings.Trim(n.Path, "/")] = &cachedNode{Node: n, CachedAt: now}
+		}
+	}
+}
+
+// cacheListed flags a folder whose children have all been cached.
+func (client *SdkClient) cacheListed(folder string) {
+	c := client.metaCache
+	if c == nil {
+		return
+	}
+	c.Lock()
+	defer c.Unlock()
+	c.listed[strings.Trim(folder, "/")] = true
+}
+
+// saveMetaCache persists the cache on the disk if a TTL has been defined.
+func (client *SdkClient) saveMetaCache() {
+	c := client.metaCache
+	if c == nil || c.file == "" {
+		return
+	}
+	c.RLock()
+	data, err := json.Marshal(c.nodes)
+	c.RUnlock()
+	if err != nil {
+		Log.Debugf("could not serialize metadata cache: %s", err.Error())
+		return
+	}
+	if err = os.MkdirAll(filepath.Dir(c.file), 0700); err == nil {
+		err = os.WriteFile(c.file, data, 0600)
+	}
+	if err != nil {
+		Log.Debugf("could not write metadata cache at %s: %s", c.file, err.Error())
+	}
+}
+
+func (c *metaCache) load() {
+	data, err := os.ReadFile(c.file)
+	if err != nil {
+		return
+	}
+	stored := make(map[string]*cachedNode)
+	if err = json.Unmarshal(data, &stored); err != nil {
+		Log.Debugf("ignoring invalid metadata cache at %s: %s", c.file, err.Error())
+		return
+	}
+	for k, n := range stored {
+		if n.Node != nil && time.Since(n.CachedAt) < c.ttl {
+			c.nodes[k] = n
+		}
+	}
+}
+
+func parentPath(p string) string {
+	parent := path.Dir(p)
+	if parent == "." || parent == "/" {
+		return ""
+	}
+	return parent
+}
@@ -0,0 +1,22 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pydio/cells-sdk-go/v4/client/policy_service"
+	"github.com/pydio/cells-sdk-go/v4/models"
+)
+
+// ListPolicyGroups retrieves all the groups of security policies that are defined on the server.
+func (client *SdkClient) ListPolicyGroups(ctx context.Context) ([]*models.IdmPolicyGroup, error) {
+	params := &policy_service.ListPoliciesParams{
+		Body:    &models.IdmListPolicyGroupsRequest{},
+		Context: ctx,
+	}
+	result, err := client.GetApiClient().PolicyService.ListPolicies(params)
+	if err != nil {
+		return nil, fmt.Errorf("could not list policies, cause: %s", err.Error())
+	}
+	return result.Payload.PolicyGroups, nil
+}
@@ -0,0 +1,41 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// PresignMaxExpiration is the longest validity that is accepted by the S3 signature V4 for presigned requests.
+const PresignMaxExpiration = 7 * 24 * time.Hour
+
+// PresignRequest generates a presigned request to download (GET) or upload (PUT) the file at the given path.
+// Note that the request is signed with the credentials of the current session: it also stops working
+// when the underlying authentication token expires or is revoked.
+func (client *SdkClient) PresignRequest(ctx context.Context, pathToFile, method string, expires time.Duration) (*v4.PresignedHTTPRequest, error) {
+	if expires <= 0 || expires > PresignMaxExpiration {
+		return nil, fmt.Errorf("invalid expiration %s, it must be positive and cannot exceed %s", expires, PresignMaxExpiration)
+	}
+	presignClient := s3.NewPresignClient(client.GetS3Client())
+	withExpires := s3.WithPresignExpires(expires)
+
+	switch method {
+	case http.MethodGet:
+		return presignClient.PresignGetObject(ctx, &s3.GetObjectInput{
+			Bucket: aws.String(client.GetBucketName()),
+			Key:    aws.String(pathToFile),
+		}, withExpires)
+	case http.MethodPut:
+		return presignClient.PresignPutObject(ctx, &s3.PutObjectInput{
+			Bucket: aws.String(client.GetBucketName()),
+			Key:    aws.String(pathToFile),
+		}, withExpires)
+	default:
+		return nil, fmt.Errorf("unsupported method %s, it must be either GET or PUT", method)
+	}
+}
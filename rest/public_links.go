@@ -0,0 +1,120 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	cellsSdk "github.com/pydio/cells-sdk-go/v4"
+	"github.com/pydio/cells-sdk-go/v4/transport"
+	sdkHttp "github.com/pydio/cells-sdk-go/v4/transport/http"
+)
+
+// Suffix that is appended by the server to the login of the hidden user of a public link
+// to compute its password when the link is not protected.
+const publicLinkPasswordSuffix = "#$!Az1"
+
+var (
+	preLogUserRegexp  = regexp.MustCompile(`"PRELOG_USER"\s*:\s*"([^"]+)"`)
+	presetLoginRegexp = regexp.MustCompile(`"PRESET_LOGIN"\s*:\s*"([^"]+)"`)
+)
+
+// PublicLink holds the information that is necessary to anonymously access to the content of a public link.
+type PublicLink struct {
+	ServerURL        string
+	Hash             string
+	Login            string
+	PasswordRequired bool
+}
+
+// ResolvePublicLink parses the passed public URL and retrieves the login of the hidden user
+// that is associated to the link from the public page that is served by the server.
+func ResolvePublicLink(ctx context.Context, publicURL string, skipVerify bool) (*PublicLink, error) {
+	u, err := url.Parse(publicURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("%s is not a valid public link", publicURL)
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 || parts[len(parts)-2] != "public" {
+		return nil, fmt.Errorf("%s is not a valid public link, expected format is https://<server>/public/<hash>", publicURL)
+	}
+	link := &PublicLink{
+		ServerURL: u.Scheme + "://" + u.Host,
+		Hash:      parts[len(parts)-1],
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", link.ServerURL+"/public/"+link.Hash, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(cellsSdk.UserAgentKey, UserAgent())
+	httpClient := &http.Client{Transport: transport.New(sdkHttp.WithSkipVerify(skipVerify))}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not reach %s, cause: %s", publicURL, err.Error())
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not retrieve public link %s, server returned: %s", link.Hash, resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if m := preLogUserRegexp.FindSubmatch(body); m != nil {
+		link.Login = string(m[1])
+	} else if m = presetLoginRegexp.FindSubmatch(body); m != nil {
+		link.Login = string(m[1])
+		link.PasswordRequired = true
+	} else {
+		return nil, fmt.Errorf("no valid public link found at %s, it might have expired", publicURL)
+	}
+	return link, nil
+}
+
+// NewPublicLinkClient creates an SDK client that is authenticated as the hidden user of the passed public link.
+// The password is only used for links that are protected. Nothing is stored in the local configuration.
+func NewPublicLinkClient(ctx context.Context, link *PublicLink, password string, skipVerify bool) (*SdkClient, error) {
+	if !link.PasswordRequired {
+		password = link.Login + publicLinkPasswordSuffix
+	} else if password == "" {
+		return nil, fmt.Errorf("link %s is protected by a password, please provide it", link.Hash)
+	}
+
+	conf := DefaultCecConfig()
+	conf.Url = link.ServerURL
+	conf.AuthType = cellsSdk.AuthTypeClientAuth
+	conf.User = link.Login
+	conf.Password = password
+	conf.SkipVerify = skipVerify
+	conf.UseTokenCache = false
+	conf.SkipKeyring = true
+	conf.CustomHeaders = map[string]string{cellsSdk.UserAgentKey: UserAgent()}
+
+	client, err := NewSdkClient(ctx, conf)
+	if err != nil {
+		return nil, err
+	}
+	client.Setup(ctx)
+	return client, nil
+}
+
+// PublicLinkRoot returns the path of the node that is shared via the public link, as seen by its hidden user.
+func (client *SdkClient) PublicLinkRoot(ctx context.Context) (string, error) {
+	roots, err := client.ListNodesPath(ctx, "/*")
+	if err != nil {
+		if IsForbiddenError(err) {
+			return "", fmt.Errorf("could not log in, please double check the password of the link")
+		}
+		return "", fmt.Errorf("could not list content of the link, cause: %s", err.Error())
+	}
+	if len(roots) == 0 {
+		return "", fmt.Errorf("no content found for this link")
+	}
+	return strings.Trim(roots[0], "/"), nil
+}
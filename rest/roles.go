@@ -0,0 +1,132 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/pydio/cells-sdk-go/v4/client/role_service"
+	"github.com/pydio/cells-sdk-go/v4/models"
+)
+
+// ListRoles retrieves the roles that are neither user nor group roles, that is the roles that can be assigned.
+func (client *SdkClient) ListRoles(ctx context.Context) ([]*models.IdmRole, error) {
+	request := &models.RestSearchRoleRequest{
+		Limit: strconv.Itoa(pageSize),
+	}
+	var roles []*models.IdmRole
+	fetched := 0
+	for {
+		result, err := client.GetApiClient().RoleService.SearchRoles(&role_service.SearchRolesParams{Body: request, Context: ctx})
+		if err != nil {
+			return nil, fmt.Errorf("could not list roles, cause: %s", err.Error())
+		}
+		fetched += len(result.Payload.Roles)
+		for _, r := range result.Payload.Roles {
+			if !r.UserRole && !r.GroupRole {
+				roles = append(roles, r)
+			}
+		}
+		if len(result.Payload.Roles) < pageSize {
+			break
+		}
+		request.Offset = strconv.Itoa(fetched)
+	}
+	return roles, nil
+}
+
+// FindRole retrieves an assignable role either by its UUID or by its label.
+func (client *SdkClient) FindRole(ctx context.Context, uuidOrLabel string) (*models.IdmRole, error) {
+	roles, err := client.ListRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return MatchRole(roles, uuidOrLabel)
+}
+
+// MatchRole finds a role in the passed list either by its UUID or by its label.
+// Matching by label fails if more than one role has the same label.
+func MatchRole(roles []*models.IdmRole, uuidOrLabel string) (*models.IdmRole, error) {
+	var found []*models.IdmRole
+	for _, r := range roles {
+		if r.UUID == uuidOrLabel {
+			return r, nil
+		}
+		if r.Label == uuidOrLabel {
+			found = append(found, r)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, NotFoundError(fmt.Sprintf("no role found with UUID or label %s", uuidOrLabel))
+	case 1:
+		return found[0], nil
+	default:
+		return nil, fmt.Errorf("found %d roles with label %s, please rather use the UUID of the role", len(found), uuidOrLabel)
+	}
+}
+
+// GetRole retrieves a role by its UUID, including technical user and group roles.
+func (client *SdkClient) GetRole(ctx context.Context, uuid string) (*models.IdmRole, error) {
+	result, err := client.GetApiClient().RoleService.GetRole(&role_service.GetRoleParams{UUID: uuid, Context: ctx})
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve role %s, cause: %s", uuid, err.Error())
+	}
+	return result.Payload, nil
+}
+
+// PutRole creates the role or updates it if it already exists.
+func (client *SdkClient) PutRole(ctx context.Context, role *models.IdmRole) (*models.IdmRole, error) {
+	result, err := client.GetApiClient().RoleService.SetRole(&role_service.SetRoleParams{UUID: role.UUID, Body: role, Context: ctx})
+	if err != nil {
+		return nil, fmt.Errorf("could not store role %s, cause: %s", role.Label, err.Error())
+	}
+	return result.Payload, nil
+}
+
+// DeleteRole removes a role, it is also unassigned from all users and groups.
+func (client *SdkClient) DeleteRole(ctx context.Context, uuid string) error {
+	if _, err := client.GetApiClient().RoleService.DeleteRole(&role_service.DeleteRoleParams{UUID: uuid, Context: ctx}); err != nil {
+		return fmt.Errorf("could not delete role %s, cause: %s", uuid, err.Error())
+	}
+	return nil
+}
+
+// SetAssignedRoles stores the principal, a user or a group, with the passed roles as its assigned roles.
+// Group roles and the own role of the principal are kept, in the order in which the server applies them.
+func (client *SdkClient) SetAssignedRoles(ctx context.Context, principal *models.IdmUser, assigned []*models.IdmRole) error {
+	principal.Roles = WithAssignedRoles(principal, assigned)
+	if principal.IsGroup {
+		_, err := client.MoveGroup(ctx, principal, GroupFullPath(principal))
+		return err
+	}
+	_, err := client.PutUser(ctx, principal)
+	return err
+}
+
+// WithAssignedRoles returns the roles of the principal where the assigned roles are replaced by the passed ones:
+// inherited group roles come first, then the assigned roles and the own role of the principal last.
+func WithAssignedRoles(principal *models.IdmUser, assigned []*models.IdmRole) []*models.IdmRole {
+	var roles, own []*models.IdmRole
+	for _, r := range principal.Roles {
+		switch {
+		case r.UUID == principal.UUID || r.UserRole:
+			own = append(own, r)
+		case r.GroupRole:
+			roles = append(roles, r)
+		}
+	}
+	roles = append(roles, assigned...)
+	return append(roles, own...)
+}
+
+// AssignedRoles returns the roles that have been explicitly assigned to a user or a group.
+func AssignedRoles(principal *models.IdmUser) []*models.IdmRole {
+	var roles []*models.IdmRole
+	for _, r := range principal.Roles {
+		if !r.UserRole && !r.GroupRole && r.UUID != principal.UUID {
+			roles = append(roles, r)
+		}
+	}
+	return roles
+}
@@ -3,11 +3,32 @@ package rest
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/pydio/cells-sdk-go/v4/client/share_service"
 	"github.com/pydio/cells-sdk-go/v4/models"
 )
 
+// Known share link permissions
+const (
+	SharePermPreview  = "preview"
+	SharePermDownload = "download"
+	SharePermUpload   = "upload"
+)
+
+// ShareLinkOptions gathers the optional parameters that can be set on a public link.
+// Zero values mean "not set", except for MaxDownloads where 0 means "no limit" and nil means "not set".
+type ShareLinkOptions struct {
+	Label        string
+	Description  string
+	Password     string
+	ExpireAt     time.Time
+	MaxDownloads *int64
+	Permissions  []string
+	CustomHash   string
+}
+
 func (client *SdkClient) CreateSimpleFolderLink(ctx context.Context, targetNodeUuid, label string) (*models.RestShareLink, error) {
 
 	perm := []*models.RestShareLinkAccessType{
@@ -35,3 +56,199 @@ func (client *SdkClient) CreateSimpleFolderLink(ctx context.Context, targetNodeU
 
 	return resp.Payload, nil
 }
+
+// CreateShareLink creates a new public link on the passed node with the given options.
+func (client *SdkClient) CreateShareLink(ctx context.Context, node *models.TreeNode, options *ShareLinkOptions) (*models.RestShareLink, error) {
+
+	isDir := node.Type != nil && *node.Type == models.TreeNodeTypeCOLLECTION
+	template := "pydio_unique_strip"
+	if isDir {
+		template = "pydio_shared_folder"
+	}
+	if len(options.Permissions) == 0 {
+		options.Permissions = []string{SharePermPreview, SharePermDownload}
+	}
+
+	link := &models.RestShareLink{
+		RootNodes:               []*models.TreeNode{{UUID: node.UUID}},
+		ViewTemplateName:        template,
+		PoliciesContextEditable: true,
+	}
+	if err := applyShareLinkOptions(link, options, isDir); err != nil {
+		return nil, err
+	}
+
+	request := &models.RestPutShareLinkRequest{
+		ShareLink:        link,
+		PasswordEnabled:  options.Password != "",
+		CreatePassword:   options.Password,
+		UpdateCustomHash: options.CustomHash,
+	}
+	return client.putShareLink(ctx, request)
+}
+
+// UpdateShareLink applies the passed options to an existing link. Only non-zero options are modified,
+// except when removePassword is set: in such case, the password protection is removed.
+func (client *SdkClient) UpdateShareLink(ctx context.Context, link *models.RestShareLink, options *ShareLinkOptions, removePassword bool) (*models.RestShareLink, error) {
+
+	isDir, err := client.shareLinkIsDir(ctx, link)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyShareLinkOptions(link, options, isDir); err != nil {
+		return nil, err
+	}
+
+	passwordEnabled := link.PasswordRequired
+	if removePassword {
+		passwordEnabled = false
+	} else if options.Password != "" {
+		passwordEnabled = true
+	}
+	request := &models.RestPutShareLinkRequest{
+		ShareLink:        link,
+		PasswordEnabled:  passwordEnabled,
+		UpdatePassword:   options.Password,
+		UpdateCustomHash: options.CustomHash,
+	}
+	return client.putShareLink(ctx, request)
+}
+
+// shareLinkIsDir tells whether the link targets a folder. The type of the root nodes that are returned
+// with the link is not always set: the node is rather retrieved from the server with its path.
+func (client *SdkClient) shareLinkIsDir(ctx context.Context, link *models.RestShareLink) (bool, error) {
+	if len(link.RootNodes) == 0 {
+		return false, fmt.Errorf("link %s has no shared node", link.UUID)
+	}
+	n := link.RootNodes[0]
+	if n.Path != "" {
+		node, exists := client.StatNode(ctx, n.Path)
+		if !exists {
+			return false, fmt.Errorf("could not find node %s that is shared by link %s", n.Path, link.UUID)
+		}
+		n = node
+	}
+	if n.Type == nil {
+		return false, fmt.Errorf("could not determine the type of the node that is shared by link %s", link.UUID)
+	}
+	return *n.Type == models.TreeNodeTypeCOLLECTION, nil
+}
+
+// GetShareLink retrieves a public link by its UUID.
+func (client *SdkClient) GetShareLink(ctx context.Context, linkUuid string) (*models.RestShareLink, error) {
+	params := share_service.NewGetShareLinkParamsWithContext(ctx)
+	params.UUID = linkUuid
+	resp, err := client.GetApiClient().ShareService.GetShareLink(params)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve link %s, cause: %s", linkUuid, err.Error())
+	}
+	return resp.Payload, nil
+}
+
+// DeleteShareLink removes a public link by its UUID.
+func (client *SdkClient) DeleteShareLink(ctx context.Context, linkUuid string) error {
+	params := share_service.NewDeleteShareLinkParamsWithContext(ctx)
+	params.UUID = linkUuid
+	resp, err := client.GetApiClient().ShareService.DeleteShareLink(params)
+	if err != nil {
+		return fmt.Errorf("could not delete link %s, cause: %s", linkUuid, err.Error())
+	}
+	if !resp.Payload.Success {
+		return fmt.Errorf("server could not delete link %s", linkUuid)
+	}
+	return nil
+}
+
+// ListShareLinks lists the public links that are owned by the current user.
+func (client *SdkClient) ListShareLinks(ctx context.Context) ([]*models.ListSharedResourcesResponseSharedResource, error) {
+	return client.listSharedResources(ctx, models.ListSharedResourcesRequestListShareTypeLINKS)
+}
+
+// FindShareLinksForNode lists the public links of the current user that point to the node with the given UUID.
+func (client *SdkClient) FindShareLinksForNode(ctx context.Context, nodeUuid string) ([]*models.RestShareLink, error) {
+	resources, err := client.ListShareLinks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var links []*models.RestShareLink
+	for _, r := range resources {
+		if r.Link == nil || r.Node == nil {
+			continue
+		}
+		if r.Node.UUID == nodeUuid {
+			links = append(links, r.Link)
+		}
+	}
+	return links, nil
+}
+
+func (client *SdkClient) listSharedResources(ctx context.Context, shareType models.ListSharedResourcesRequestListShareType) ([]*models.ListSharedResourcesResponseSharedResource, error) {
+	params := share_service.NewListSharedResourcesParamsWithContext(ctx)
+	params.Body = &models.RestListSharedResourcesRequest{
+		ShareType:   models.NewListSharedResourcesRequestListShareType(shareType),
+		OwnedBySelf: true,
+		Limit:       pageSize,
+	}
+
+	var resources []*models.ListSharedResourcesResponseSharedResource
+	for {
+		resp, err := client.GetApiClient().ShareService.ListSharedResources(params)
+		if err != nil {
+			return nil, fmt.Errorf("could not list shared resources, cause: %s", err.Error())
+		}
+		resources = append(resources, resp.Payload.Resources...)
+		if len(resp.Payload.Resources) < pageSize || int32(len(resources)) >= resp.Payload.Total {
+			break
+		}
+		params.Body.Offset = int32(len(resources))
+	}
+	return resources, nil
+}
+
+func (client *SdkClient) putShareLink(ctx context.Context, request *models.RestPutShareLinkRequest) (*models.RestShareLink, error) {
+	params := share_service.NewPutShareLinkParamsWithContext(ctx)
+	params.Body = request
+	resp, err := client.GetApiClient().ShareService.PutShareLink(params)
+	if err != nil {
+		return nil, fmt.Errorf("call to PutShareLink for %s has failed, cause: %s", request.ShareLink.Label, err.Error())
+	}
+	return resp.Payload, nil
+}
+
+func applyShareLinkOptions(link *models.RestShareLink, options *ShareLinkOptions, isDir bool) error {
+	if options.Label != "" {
+		link.Label = options.Label
+	}
+	if options.Description != "" {
+		link.Description = options.Description
+	}
+	if !options.ExpireAt.IsZero() {
+		if options.ExpireAt.Before(time.Now()) {
+			return fmt.Errorf("expiration date %s is in the past", options.ExpireAt.Format(time.RFC3339))
+		}
+		link.AccessEnd = strconv.FormatInt(options.ExpireAt.Unix(), 10)
+	}
+	if options.MaxDownloads != nil {
+		link.MaxDownloads = strconv.FormatInt(*options.MaxDownloads, 10)
+	}
+	if len(options.Permissions) > 0 {
+		var perms []*models.RestShareLinkAccessType
+		for _, p := range options.Permissions {
+			switch p {
+			case SharePermPreview:
+				perms = append(perms, models.NewRestShareLinkAccessType(models.RestShareLinkAccessTypePreview))
+			case SharePermDownload:
+				perms = append(perms, models.NewRestShareLinkAccessType(models.RestShareLinkAccessTypeDownload))
+			case SharePermUpload:
+				if !isDir {
+					return fmt.Errorf("upload permission can only be granted on folders")
+				}
+				perms = append(perms, models.NewRestShareLinkAccessType(models.RestShareLinkAccessTypeUpload))
+			default:
+				return fmt.Errorf("unknown permission %s, known values are: %s, %s and %s", p, SharePermPreview, SharePermDownload, SharePermUpload)
+			}
+		}
+		link.Permissions = perms
+	}
+	return nil
+}
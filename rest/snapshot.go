@@ -0,0 +1,471 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pydio/cells-sdk-go/v4/models"
+)
+
+// IdmSnapshotVersion is the version of the format of the identity snapshots, it is increased on breaking changes.
+const IdmSnapshotVersion = 1
+
+// IdmSnapshot is a point-in-time copy of the identities of a server and of the access they are given.
+// It never contains secrets such as passwords.
+type IdmSnapshot struct {
+	Version    int                      `json:"version"`
+	CreatedAt  time.Time                `json:"createdAt"`
+	Server     string                   `json:"server"`
+	Users      []*SnapshotUser          `json:"users"`
+	Groups     []*SnapshotGroup         `json:"groups"`
+	Roles      []*SnapshotRole          `json:"roles"`
+	Workspaces []*SnapshotWorkspace     `json:"workspaces"`
+	Acls       []*SnapshotAcl           `json:"acls"`
+	Policies   []*models.IdmPolicyGroup `json:"policies"`
+}
+
+// SnapshotUser describes a user, Roles are the labels of the roles that are explicitly assigned to the user.
+type SnapshotUser struct {
+	UUID        string            `json:"uuid"`
+	Login       string            `json:"login"`
+	GroupPath   string            `json:"groupPath"`
+	Profile     string            `json:"profile,omitempty"`
+	DisplayName string            `json:"displayName,omitempty"`
+	Email       string            `json:"email,omitempty"`
+	Roles       []string          `json:"roles,omitempty"`
+	Locks       []string          `json:"locks,omitempty"`
+	Attributes  map[string]string `json:"attributes,omitempty"`
+}
+
+// SnapshotGroup describes a group with its full path.
+type SnapshotGroup struct {
+	UUID        string   `json:"uuid"`
+	Path        string   `json:"path"`
+	DisplayName string   `json:"displayName,omitempty"`
+	Roles       []string `json:"roles,omitempty"`
+}
+
+// SnapshotRole describes an assignable role.
+type SnapshotRole struct {
+	UUID      string   `json:"uuid"`
+	Label     string   `json:"label"`
+	AutoApply []string `json:"autoApply,omitempty"`
+}
+
+// SnapshotWorkspace describes a workspace, Roots are the paths of its root folders in the datasources.
+type SnapshotWorkspace struct {
+	UUID string `json:"uuid"`
+	Slug string `json:"slug"`
+	// Scope is empty for the workspaces that are managed by the administrators,
+	// ROOM for the cells and LINK for the hidden workspaces of the public links.
+	Scope         string                 `json:"scope,omitempty"`
+	Label         string                 `json:"label"`
+	Description   string                 `json:"description,omitempty"`
+	Roots         []string               `json:"roots"`
+	DefaultRights string                 `json:"defaultRights,omitempty"`
+	Attributes    map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// SnapshotAcl gives the combined rights of a principal on a node through a workspace: r, w, rw or deny.
+// Path starts with the slug of the workspace, it is empty if the node cannot be found anymore.
+type SnapshotAcl struct {
+	Principal string `json:"principal"`
+	RoleID    string `json:"roleId"`
+	Workspace string `json:"workspace"`
+	NodeID    string `json:"nodeId"`
+	Path      string `json:"path,omitempty"`
+	Rights    string `json:"rights"`
+}
+
+// SnapshotChange describes a difference between two snapshots for a given object.
+type SnapshotChange struct {
+	Kind    string   `json:"kind"`
+	Name    string   `json:"name"`
+	Type    DiffType `json:"type"`
+	Details []string `json:"details,omitempty"`
+}
+
+// TakeIdmSnapshot retrieves the users, groups, roles, workspaces, ACLs and policies of the server.
+func (client *SdkClient) TakeIdmSnapshot(ctx context.Context) (*IdmSnapshot, error) {
+	snapshot := &IdmSnapshot{
+		Version:   IdmSnapshotVersion,
+		CreatedAt: time.Now().UTC(),
+		Server:    client.GetConfig().Url,
+	}
+	principals := map[string]string{RootGroupRole: "all users"}
+
+	groups, err := client.ListGroups(ctx, "/", true)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range groups {
+		snapshot.Groups = append(snapshot.Groups, &SnapshotGroup{
+			UUID:        g.UUID,
+			Path:        GroupFullPath(g),
+			DisplayName: g.Attributes[UserAttrDisplayName],
+			Roles:       roleLabels(AssignedRoles(g)),
+		})
+		principals[g.UUID] = "group " + GroupFullPath(g)
+	}
+
+	users, err := client.ListUsers(ctx, "/", true)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		attributes := make(map[string]string)
+		for k, v := range u.Attributes {
+			switch k {
+			case UserAttrProfile, UserAttrDisplayName, UserAttrEmail, UserAttrLocks:
+			default:
+				attributes[k] = v
+			}
+		}
+		snapshot.Users = append(snapshot.Users, &SnapshotUser{
+			UUID:        u.UUID,
+			Login:       u.Login,
+			GroupPath:   "/" + strings.Trim(u.GroupPath, "/"),
+			Profile:     u.Attributes[UserAttrProfile],
+			DisplayName: u.Attributes[UserAttrDisplayName],
+			Email:       u.Attributes[UserAttrEmail],
+			Roles:       roleLabels(AssignedRoles(u)),
+			Locks:       UserLocks(u),
+			Attributes:  attributes,
+		})
+		principals[u.UUID] = "user " + u.Login
+	}
+
+	roles, err := client.ListRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range roles {
+		snapshot.Roles = append(snapshot.Roles, &SnapshotRole{UUID: r.UUID, Label: r.Label, AutoApply: r.AutoApplies})
+		if _, ok := principals[r.UUID]; !ok {
+			principals[r.UUID] = "role " + r.Label
+		}
+	}
+
+	// Also capture the cells and the public links, so that all the access rights, including the shares, are known
+	workspaces, err := client.ListWorkspacesOfScopes(ctx, models.IdmWorkspaceScopeADMIN, models.IdmWorkspaceScopeROOM, models.IdmWorkspaceScopeLINK)
+	if err != nil {
+		return nil, err
+	}
+	var wsUuids []string
+	bySlug := make(map[string]*SnapshotWorkspace)
+	slugs := make(map[string]string)
+	for _, ws := range workspaces {
+		attributes := WorkspaceAttributes(ws)
+		rights, _ := attributes[WorkspaceAttrDefaultRights].(string)
+		delete(attributes, WorkspaceAttrDefaultRights)
+		sw := &SnapshotWorkspace{
+			UUID:          ws.UUID,
+			Slug:          ws.Slug,
+			Scope:         snapshotScope(ws),
+			Label:         ws.Label,
+			Description:   ws.Description,
+			Roots:         []string{},
+			DefaultRights: rights,
+			Attributes:    attributes,
+		}
+		for _, id := range WorkspaceRootUuids(ws) {
+			if n, ok := ws.RootNodes[id]; ok && n != nil {
+				sw.Roots = append(sw.Roots, strings.Trim(n.Path, "/"))
+			}
+		}
+		sort.Strings(sw.Roots)
+		snapshot.Workspaces = append(snapshot.Workspaces, sw)
+		wsUuids = append(wsUuids, ws.UUID)
+		bySlug[ws.Slug] = sw
+		slugs[ws.UUID] = ws.Slug
+	}
+
+	if len(wsUuids) > 0 {
+		acls, err := client.SearchAcls(ctx, &models.IdmACLSingleQuery{WorkspaceIDs: wsUuids})
+		if err != nil {
+			return nil, fmt.Errorf("could not list ACLs, cause: %s", err.Error())
+		}
+		paths := make(map[string]string)
+		byKey := make(map[string]*SnapshotAcl)
+		for _, a := range acls {
+			if a.Action == nil {
+				continue
+			}
+			var flag string
+			switch a.Action.Name {
+			case "read":
+				flag = "r"
+			case "write":
+				flag = "w"
+			case "deny":
+				flag = "deny"
+			default:
+				continue
+			}
+			key := a.RoleID + "/" + a.WorkspaceID + "/" + a.NodeID
+			sa, ok := byKey[key]
+			if !ok {
+				slug := ValueOr(slugs[a.WorkspaceID], a.WorkspaceID)
+				p, resolved := paths[slug+"/"+a.NodeID]
+				if !resolved {
+					p = client.snapshotAclPath(ctx, bySlug[slug], a.NodeID)
+					paths[slug+"/"+a.NodeID] = p
+				}
+				sa = &SnapshotAcl{
+					Principal: ValueOr(principals[a.RoleID], "role "+a.RoleID),
+					RoleID:    a.RoleID,
+					Workspace: slug,
+					NodeID:    a.NodeID,
+					Path:      p,
+				}
+				byKey[key] = sa
+				snapshot.Acls = append(snapshot.Acls, sa)
+			}
+			switch {
+			case flag == "deny" || sa.Rights == "deny":
+				sa.Rights = "deny"
+			case !strings.Contains(sa.Rights, flag):
+				if flag == "r" {
+					sa.Rights = "r" + sa.Rights
+				} else {
+					sa.Rights += flag
+				}
+			}
+		}
+	}
+
+	if snapshot.Policies, err = client.ListPolicyGroups(ctx); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(snapshot.Users, func(i, j int) bool { return snapshot.Users[i].Login < snapshot.Users[j].Login })
+	sort.Slice(snapshot.Groups, func(i, j int) bool { return snapshot.Groups[i].Path < snapshot.Groups[j].Path })
+	sort.Slice(snapshot.Roles, func(i, j int) bool { return snapshot.Roles[i].Label < snapshot.Roles[j].Label })
+	sort.Slice(snapshot.Workspaces, func(i, j int) bool { return snapshot.Workspaces[i].Slug < snapshot.Workspaces[j].Slug })
+	sort.Slice(snapshot.Acls, func(i, j int) bool { return snapshot.Acls[i].key() < snapshot.Acls[j].key() })
+	sort.Slice(snapshot.Policies, func(i, j int) bool { return snapshot.Policies[i].UUID < snapshot.Policies[j].UUID })
+	return snapshot, nil
+}
+
+// snapshotScope returns the scope of a workspace as stored in the snapshots, empty for ADMIN workspaces.
+func snapshotScope(ws *models.IdmWorkspace) string {
+	if ws.Scope == nil || *ws.Scope == models.IdmWorkspaceScopeADMIN {
+		return ""
+	}
+	return string(*ws.Scope)
+}
+
+// snapshotAclPath finds the path of the node relatively to the workspace, starting with its slug.
+func (client *SdkClient) snapshotAclPath(ctx context.Context, ws *SnapshotWorkspace, nodeUuid string) string {
+	node, err := client.StatAdminNodeByUuid(ctx, nodeUuid)
+	if err != nil || node == nil {
+		return ""
+	}
+	adminPath := strings.Trim(node.Path, "/")
+	if ws == nil {
+		return adminPath
+	}
+	for _, root := range ws.Roots {
+		if adminPath == root {
+			return ws.Slug
+		}
+		if strings.HasPrefix(adminPath, root+"/") {
+			return path.Join(ws.Slug, strings.TrimPrefix(adminPath, root+"/"))
+		}
+	}
+	return adminPath
+}
+
+func (a *SnapshotAcl) key() string {
+	return a.Principal + "|" + a.Workspace + "|" + ValueOr(a.Path, a.NodeID)
+}
+
+// DiffSnapshots lists the differences of access between two snapshots, from the first to the second one.
+func DiffSnapshots(from, to *IdmSnapshot) []*SnapshotChange {
+	var changes []*SnapshotChange
+
+	fromUsers := make(map[string]*SnapshotUser)
+	for _, u := range from.Users {
+		fromUsers[u.Login] = u
+	}
+	toUsers := make(map[string]*SnapshotUser)
+	for _, u := range to.Users {
+		toUsers[u.Login] = u
+	}
+	for _, u := range from.Users {
+		if _, ok := toUsers[u.Login]; !ok {
+			changes = append(changes, &SnapshotChange{Kind: "user", Name: u.Login, Type: DiffRemoved})
+		}
+	}
+	for _, u := range to.Users {
+		old, ok := fromUsers[u.Login]
+		if !ok {
+			changes = append(changes, &SnapshotChange{Kind: "user", Name: u.Login, Type: DiffAdded, Details: []string{
+				"group: " + u.GroupPath, "profile: " + ValueOr(u.Profile, "-"), "roles: [" + strings.Join(u.Roles, ", ") + "]",
+			}})
+			continue
+		}
+		var details []string
+		details = appendDiff(details, "group", old.GroupPath, u.GroupPath)
+		details = appendDiff(details, "profile", old.Profile, u.Profile)
+		details = appendDiff(details, "roles", "["+strings.Join(old.Roles, ", ")+"]", "["+strings.Join(u.Roles, ", ")+"]")
+		details = appendDiff(details, "locks", "["+strings.Join(old.Locks, ", ")+"]", "["+strings.Join(u.Locks, ", ")+"]")
+		if len(details) > 0 {
+			changes = append(changes, &SnapshotChange{Kind: "user", Name: u.Login, Type: DiffModified, Details: details})
+		}
+	}
+
+	fromGroups := make(map[string]*SnapshotGroup)
+	for _, g := range from.Groups {
+		fromGroups[g.Path] = g
+	}
+	toGroups := make(map[string]*SnapshotGroup)
+	for _, g := range to.Groups {
+		toGroups[g.Path] = g
+	}
+	for _, g := range from.Groups {
+		if _, ok := toGroups[g.Path]; !ok {
+			changes = append(changes, &SnapshotChange{Kind: "group", Name: g.Path, Type: DiffRemoved})
+		}
+	}
+	for _, g := range to.Groups {
+		old, ok := fromGroups[g.Path]
+		if !ok {
+			changes = append(changes, &SnapshotChange{Kind: "group", Name: g.Path, Type: DiffAdded})
+			continue
+		}
+		details := appendDiff(nil, "roles", "["+strings.Join(old.Roles, ", ")+"]", "["+strings.Join(g.Roles, ", ")+"]")
+		if len(details) > 0 {
+			changes = append(changes, &SnapshotChange{Kind: "group", Name: g.Path, Type: DiffModified, Details: details})
+		}
+	}
+
+	fromRoles := make(map[string]*SnapshotRole)
+	for _, r := range from.Roles {
+		fromRoles[r.UUID] = r
+	}
+	toRoles := make(map[string]*SnapshotRole)
+	for _, r := range to.Roles {
+		toRoles[r.UUID] = r
+	}
+	for _, r := range from.Roles {
+		if _, ok := toRoles[r.UUID]; !ok {
+			changes = append(changes, &SnapshotChange{Kind: "role", Name: r.Label, Type: DiffRemoved})
+		}
+	}
+	for _, r := range to.Roles {
+		old, ok := fromRoles[r.UUID]
+		if !ok {
+			changes = append(changes, &SnapshotChange{Kind: "role", Name: r.Label, Type: DiffAdded})
+			continue
+		}
+		details := appendDiff(nil, "label", old.Label, r.Label)
+		details = appendDiff(details, "autoApply", "["+strings.Join(old.AutoApply, ", ")+"]", "["+strings.Join(r.AutoApply, ", ")+"]")
+		if len(details) > 0 {
+			changes = append(changes, &SnapshotChange{Kind: "role", Name: r.Label, Type: DiffModified, Details: details})
+		}
+	}
+
+	fromWorkspaces := make(map[string]*SnapshotWorkspace)
+	for _, w := range from.Workspaces {
+		fromWorkspaces[w.Slug] = w
+	}
+	toWorkspaces := make(map[string]*SnapshotWorkspace)
+	for _, w := range to.Workspaces {
+		toWorkspaces[w.Slug] = w
+	}
+	for _, w := range from.Workspaces {
+		if _, ok := toWorkspaces[w.Slug]; !ok {
+			changes = append(changes, &SnapshotChange{Kind: "workspace", Name: w.Slug, Type: DiffRemoved})
+		}
+	}
+	for _, w := range to.Workspaces {
+		old, ok := fromWorkspaces[w.Slug]
+		if !ok {
+			details := []string{"roots: [" + strings.Join(w.Roots, ", ") + "]", "defaultRights: " + ValueOr(w.DefaultRights, "none")}
+			if w.Scope != "" {
+				details = append([]string{"scope: " + w.Scope}, details...)
+			}
+			changes = append(changes, &SnapshotChange{Kind: "workspace", Name: w.Slug, Type: DiffAdded, Details: details})
+			continue
+		}
+		details := appendDiff(nil, "roots", "["+strings.Join(old.Roots, ", ")+"]", "["+strings.Join(w.Roots, ", ")+"]")
+		details = appendDiff(details, "defaultRights", ValueOr(old.DefaultRights, "none"), ValueOr(w.DefaultRights, "none"))
+		if len(details) > 0 {
+			changes = append(changes, &SnapshotChange{Kind: "workspace", Name: w.Slug, Type: DiffModified, Details: details})
+		}
+	}
+
+	fromAcls := make(map[string]*SnapshotAcl)
+	for _, a := range from.Acls {
+		fromAcls[a.key()] = a
+	}
+	toAcls := make(map[string]*SnapshotAcl)
+	for _, a := range to.Acls {
+		toAcls[a.key()] = a
+	}
+	for _, a := range from.Acls {
+		if _, ok := toAcls[a.key()]; !ok {
+			changes = append(changes, &SnapshotChange{Kind: "acl", Name: a.name(), Type: DiffRemoved, Details: []string{"rights: " + a.Rights}})
+		}
+	}
+	for _, a := range to.Acls {
+		old, ok := fromAcls[a.key()]
+		if !ok {
+			changes = append(changes, &SnapshotChange{Kind: "acl", Name: a.name(), Type: DiffAdded, Details: []string{"rights: " + a.Rights}})
+		} else if old.Rights != a.Rights {
+			changes = append(changes, &SnapshotChange{Kind: "acl", Name: a.name(), Type: DiffModified, Details: appendDiff(nil, "rights", old.Rights, a.Rights)})
+		}
+	}
+
+	fromPolicies := make(map[string]*models.IdmPolicyGroup)
+	for _, p := range from.Policies {
+		fromPolicies[p.UUID] = p
+	}
+	toPolicies := make(map[string]*models.IdmPolicyGroup)
+	for _, p := range to.Policies {
+		toPolicies[p.UUID] = p
+	}
+	for _, p := range from.Policies {
+		if _, ok := toPolicies[p.UUID]; !ok {
+			changes = append(changes, &SnapshotChange{Kind: "policy", Name: ValueOr(p.Name, p.UUID), Type: DiffRemoved})
+		}
+	}
+	for _, p := range to.Policies {
+		old, ok := fromPolicies[p.UUID]
+		if !ok {
+			changes = append(changes, &SnapshotChange{Kind: "policy", Name: ValueOr(p.Name, p.UUID), Type: DiffAdded})
+			continue
+		}
+		before, _ := json.Marshal(old.Policies)
+		after, _ := json.Marshal(p.Policies)
+		if string(before) != string(after) {
+			changes = append(changes, &SnapshotChange{Kind: "policy", Name: ValueOr(p.Name, p.UUID), Type: DiffModified, Details: []string{"rules have changed"}})
+		}
+	}
+	return changes
+}
+
+func (a *SnapshotAcl) name() string {
+	return ValueOr(a.Path, a.Workspace+" node "+a.NodeID) + " for " + a.Principal
+}
+
+func appendDiff(details []string, field, before, after string) []string {
+	if before == after {
+		return details
+	}
+	return append(details, fmt.Sprintf("%s: %s -> %s", field, ValueOr(before, "-"), ValueOr(after, "-")))
+}
+
+func roleLabels(roles []*models.IdmRole) []string {
+	var labels []string
+	for _, r := range roles {
+		labels = append(labels, r.Label)
+	}
+	return labels
+}
@@ -0,0 +1,68 @@
+package rest
+
+import (
+	"testing"
+
+	// Silently import convey to ease implementation
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	Convey("Test diff of identity snapshots", t, func() {
+		from := &IdmSnapshot{
+			Users: []*SnapshotUser{
+				{Login: "alice", GroupPath: "/org/sales", Profile: "standard"},
+				{Login: "bob", GroupPath: "/org/sales", Profile: "standard"},
+			},
+			Groups:     []*SnapshotGroup{{Path: "/org/sales"}},
+			Workspaces: []*SnapshotWorkspace{{Slug: "finance", Roots: []string{"finance"}}},
+			Acls: []*SnapshotAcl{
+				{Principal: "group /org/sales", Workspace: "finance", Path: "finance/reports", Rights: "r"},
+			},
+		}
+
+		Convey("Identical snapshots have no difference", func() {
+			So(DiffSnapshots(from, from), ShouldBeEmpty)
+		})
+
+		Convey("Added, removed and modified objects are reported", func() {
+			to := &IdmSnapshot{
+				Users: []*SnapshotUser{
+					{Login: "bob", GroupPath: "/org/management", Profile: "standard"},
+					{Login: "carol", GroupPath: "/org/sales", Profile: "admin"},
+				},
+				Groups:     []*SnapshotGroup{{Path: "/org/sales"}, {Path: "/org/management"}},
+				Workspaces: []*SnapshotWorkspace{{Slug: "finance", Roots: []string{"finance"}, DefaultRights: "r"}},
+				Acls: []*SnapshotAcl{
+					{Principal: "group /org/sales", Workspace: "finance", Path: "finance/reports", Rights: "rw"},
+					{Principal: "group /org/management", Workspace: "finance", Path: "finance/reports", Rights: "rw"},
+				},
+			}
+			changes := DiffSnapshots(from, to)
+			So(changes, ShouldHaveLength, 7)
+
+			So(changes[0], ShouldResemble, &SnapshotChange{Kind: "user", Name: "alice", Type: DiffRemoved})
+			So(changes[1], ShouldResemble, &SnapshotChange{Kind: "user", Name: "bob", Type: DiffModified,
+				Details: []string{"group: /org/sales -> /org/management"}})
+			So(changes[2].Name, ShouldEqual, "carol")
+			So(changes[2].Type, ShouldEqual, DiffAdded)
+			So(changes[3], ShouldResemble, &SnapshotChange{Kind: "group", Name: "/org/management", Type: DiffAdded})
+			So(changes[4], ShouldResemble, &SnapshotChange{Kind: "workspace", Name: "finance", Type: DiffModified,
+				Details: []string{"defaultRights: none -> r"}})
+			So(changes[5], ShouldResemble, &SnapshotChange{Kind: "acl", Name: "finance/reports for group /org/sales", Type: DiffModified,
+				Details: []string{"rights: r -> rw"}})
+			So(changes[6], ShouldResemble, &SnapshotChange{Kind: "acl", Name: "finance/reports for group /org/management", Type: DiffAdded,
+				Details: []string{"rights: rw"}})
+		})
+
+		Convey("The scope of added cells and links is reported", func() {
+			to := &IdmSnapshot{Workspaces: append([]*SnapshotWorkspace{
+				{Slug: "project-x", Scope: "ROOM", Roots: []string{"project-x"}},
+			}, from.Workspaces...)}
+			changes := DiffSnapshots(&IdmSnapshot{Workspaces: from.Workspaces}, to)
+			So(changes, ShouldHaveLength, 1)
+			So(changes[0], ShouldResemble, &SnapshotChange{Kind: "workspace", Name: "project-x", Type: DiffAdded,
+				Details: []string{"scope: ROOM", "roots: [project-x]", "defaultRights: none"}})
+		})
+	})
+}
@@ -19,28 +19,7 @@ import (
 
 // GetFile retrieves a file from the server in one big download (**no** multipart download for the time being).
 func (client *SdkClient) GetFile(ctx context.Context, pathToFile string) (io.Reader, int, error) {
-	hO, err := client.GetS3Client().HeadObject(
-		ctx,
-		&s3.HeadObjectInput{
-			Bucket: aws.String(client.GetBucketName()),
-			Key:    aws.String(pathToFile),
-		},
-	)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	obj, err := client.GetS3Client().GetObject(
-		ctx,
-		&s3.GetObjectInput{
-			Bucket: aws.String(client.GetBucketName()),
-			Key:    aws.String(pathToFile),
-		},
-	)
-	if err != nil {
-		return nil, 0, err
-	}
-	return obj.Body, int(*hO.ContentLength), nil
+	return client.GetFileVersion(ctx, pathToFile, "")
 }
 
 // PutFile upload a local file to the server without using multipart upload.
@@ -73,6 +52,7 @@ func (client *SdkClient) PutFile(
 		}
 		return nil, errMsg
 	}
+	client.InvalidateMeta(pathToFile)
 
 	if checkExists {
 		fmt.Println(" ## Waiting for file to be indexed...")
@@ -129,6 +109,7 @@ func (client *SdkClient) s3Upload(ctx context.Context, path string,
 		Key:    aws.String(path),
 		Body:   content,
 	})
+	client.InvalidateMeta(path)
 
 	if err != nil {
 		var apiErr smithy.APIError
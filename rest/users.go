@@ -0,0 +1,200 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/pydio/cells-sdk-go/v4/client/user_service"
+	"github.com/pydio/cells-sdk-go/v4/models"
+)
+
+// FindUser retrieves a user by its login.
+func (client *SdkClient) FindUser(ctx context.Context, login string) (*models.IdmUser, error) {
+	result, err := client.searchUsers(ctx, &models.IdmUserSingleQuery{
+		Login:    login,
+		NodeType: models.NewIdmNodeType(models.IdmNodeTypeUSER),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not search user %s, cause: %s", login, err.Error())
+	}
+	for _, u := range result.Users {
+		if u.Login == login {
+			return u, nil
+		}
+	}
+	return nil, NotFoundError(fmt.Sprintf("no user found with login %s", login))
+}
+
+// FindUserByUuid retrieves a user by its UUID.
+func (client *SdkClient) FindUserByUuid(ctx context.Context, userUuid string) (*models.IdmUser, error) {
+	result, err := client.searchUsers(ctx, &models.IdmUserSingleQuery{
+		UUID:     userUuid,
+		NodeType: models.NewIdmNodeType(models.IdmNodeTypeUSER),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not search user %s, cause: %s", userUuid, err.Error())
+	}
+	for _, u := range result.Users {
+		if u.UUID == userUuid {
+			return u, nil
+		}
+	}
+	return nil, NotFoundError(fmt.Sprintf("no user found with UUID %s", userUuid))
+}
+
+// FindGroup retrieves a group either by its full path (when it starts with a '/') or by its label.
+// Searching by label fails if more than one group has the same label.
+func (client *SdkClient) FindGroup(ctx context.Context, pathOrLabel string) (*models.IdmUser, error) {
+	if strings.HasPrefix(pathOrLabel, "/") {
+		fullPath := "/" + strings.Trim(pathOrLabel, "/")
+		result, err := client.searchUsers(ctx, &models.IdmUserSingleQuery{
+			FullPath: fullPath,
+			NodeType: models.NewIdmNodeType(models.IdmNodeTypeGROUP),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not search group %s, cause: %s", fullPath, err.Error())
+		}
+		if len(result.Groups) == 0 {
+			return nil, NotFoundError(fmt.Sprintf("no group found at %s", fullPath))
+		}
+		return result.Groups[0], nil
+	}
+
+	result, err := client.searchUsers(ctx, &models.IdmUserSingleQuery{
+		GroupPath: "/",
+		Recursive: true,
+		NodeType:  models.NewIdmNodeType(models.IdmNodeTypeGROUP),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not search group %s, cause: %s", pathOrLabel, err.Error())
+	}
+	var found []*models.IdmUser
+	for _, g := range result.Groups {
+		if g.GroupLabel == pathOrLabel || path.Base(GroupFullPath(g)) == pathOrLabel {
+			found = append(found, g)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, NotFoundError(fmt.Sprintf("no group found with label %s", pathOrLabel))
+	case 1:
+		return found[0], nil
+	default:
+		return nil, fmt.Errorf("found %d groups with label %s, please rather use the full path of the group", len(found), pathOrLabel)
+	}
+}
+
+// Known attributes of the users, as they are stored by the server.
+const (
+	UserAttrDisplayName = "displayName"
+	UserAttrEmail       = "email"
+	UserAttrProfile     = "profile"
+	UserAttrLocks       = "locks"
+
+	// UserLockLogout is the lock that prevents a user from logging in.
+	UserLockLogout = "logout"
+)
+
+// PutUser creates the user or updates it if it already exists. Pass a non-empty Password to (re-)define it.
+func (client *SdkClient) PutUser(ctx context.Context, user *models.IdmUser) (*models.IdmUser, error) {
+	params := &user_service.PutUserParams{
+		Login:   user.Login,
+		Body:    user,
+		Context: ctx,
+	}
+	result, err := client.GetApiClient().UserService.PutUser(params)
+	if err != nil {
+		return nil, fmt.Errorf("could not store user %s, cause: %s", user.Login, err.Error())
+	}
+	return result.Payload, nil
+}
+
+// DeleteUser definitively removes a user from the server.
+func (client *SdkClient) DeleteUser(ctx context.Context, user *models.IdmUser) error {
+	params := &user_service.DeleteUserParams{
+		FullPath: path.Join(GroupFullPath(user), user.Login),
+		Context:  ctx,
+	}
+	if _, err := client.GetApiClient().UserService.DeleteUser(params); err != nil {
+		return fmt.Errorf("could not delete user %s, cause: %s", user.Login, err.Error())
+	}
+	return nil
+}
+
+// UserLocks returns the locks that are currently applied on the user.
+func UserLocks(user *models.IdmUser) []string {
+	var locks []string
+	if v, ok := user.Attributes[UserAttrLocks]; ok && v != "" {
+		_ = json.Unmarshal([]byte(v), &locks)
+	}
+	return locks
+}
+
+// SetUserLock adds or removes a lock in the attributes of the user. The user must then be stored with PutUser.
+func SetUserLock(user *models.IdmUser, lock string, locked bool) {
+	var locks []string
+	for _, l := range UserLocks(user) {
+		if l != lock {
+			locks = append(locks, l)
+		}
+	}
+	if locked {
+		locks = append(locks, lock)
+	}
+	if user.Attributes == nil {
+		user.Attributes = make(map[string]string)
+	}
+	if len(locks) == 0 {
+		delete(user.Attributes, UserAttrLocks)
+		return
+	}
+	data, _ := json.Marshal(locks)
+	user.Attributes[UserAttrLocks] = string(data)
+}
+
+// ListUsers retrieves all users of the passed group, and of its sub-groups if recursive is true.
+func (client *SdkClient) ListUsers(ctx context.Context, groupPath string, recursive bool) ([]*models.IdmUser, error) {
+	request := &models.RestSearchUserRequest{
+		Queries: []*models.IdmUserSingleQuery{{
+			GroupPath: "/" + strings.Trim(groupPath, "/"),
+			Recursive: recursive,
+			NodeType:  models.NewIdmNodeType(models.IdmNodeTypeUSER),
+		}},
+		Limit: strconv.Itoa(pageSize),
+	}
+	var users []*models.IdmUser
+	for {
+		result, err := client.GetApiClient().UserService.SearchUsers(&user_service.SearchUsersParams{Body: request, Context: ctx})
+		if err != nil {
+			return nil, fmt.Errorf("could not list users of %s, cause: %s", groupPath, err.Error())
+		}
+		users = append(users, result.Payload.Users...)
+		if len(result.Payload.Users) < pageSize {
+			break
+		}
+		request.Offset = strconv.Itoa(len(users))
+	}
+	return users, nil
+}
+
+func (client *SdkClient) searchUsers(ctx context.Context, queries ...*models.IdmUserSingleQuery) (*models.RestUsersCollection, error) {
+	params := &user_service.SearchUsersParams{
+		Body:    &models.RestSearchUserRequest{Queries: queries},
+		Context: ctx,
+	}
+	result, err := client.GetApiClient().UserService.SearchUsers(params)
+	if err != nil {
+		return nil, err
+	}
+	return result.Payload, nil
+}
+
+// GroupFullPath returns the normalized full path of a group, including its own identifier.
+// For groups, the server stores the full path in the GroupPath field.
+func GroupFullPath(group *models.IdmUser) string {
+	return "/" + strings.Trim(group.GroupPath, "/")
+}
@@ -112,6 +112,20 @@ func IsForbiddenError(err error) bool {
 	return false
 }
 
+// NotFoundError is returned when a searched object does not exist on the server,
+// as opposed to the errors that prevent the search itself.
+type NotFoundError string
+
+func (e NotFoundError) Error() string {
+	return string(e)
+}
+
+// IsNotFound returns true if the error, or one of the errors that it wraps, is a NotFoundError.
+func IsNotFound(err error) bool {
+	var e NotFoundError
+	return errors.As(err, &e)
+}
+
 func StandardizeLink(sdkConfig *cellsSdk.SdkConfig, old string) string {
 	if strings.HasPrefix(old, "/") && !strings.HasPrefix(old, "http") {
 		return sdkConfig.Url + old
@@ -119,6 +133,14 @@ func StandardizeLink(sdkConfig *cellsSdk.SdkConfig, old string) string {
 	return old
 }
 
+// ValueOr returns the value, or the fallback when the value is empty.
+func ValueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
 func Unique(length int) string {
 	pseudoRand := fmt.Sprintf("%d", time.Now().Nanosecond())
 	hash := md5.New()
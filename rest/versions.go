@@ -0,0 +1,102 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/pydio/cells-sdk-go/v4/client/meta_service"
+	"github.com/pydio/cells-sdk-go/v4/models"
+)
+
+// Meta keys that are set by the server on the nodes returned when listing versions.
+const (
+	MetaVersionId          = "versionId"
+	MetaVersionDescription = "versionDescription"
+	MetaVersionOwner       = "versionOwnerUuid"
+)
+
+// ListVersions retrieves the known versions of the file at the given path, most recent first.
+// It returns an empty list if the corresponding datasource is not versioned.
+func (client *SdkClient) ListVersions(ctx context.Context, pathToFile string) ([]*models.TreeNode, error) {
+	node, exists := client.StatNode(ctx, pathToFile)
+	if !exists {
+		return nil, fmt.Errorf("no node found at %s", pathToFile)
+	}
+	if node.Type != nil && *node.Type == models.TreeNodeTypeCOLLECTION {
+		return nil, fmt.Errorf("%s is a folder, only files have versions", pathToFile)
+	}
+
+	params := &meta_service.GetBulkMetaParams{
+		Body: &models.RestGetBulkMetaRequest{
+			NodePaths: []string{pathToFile},
+			Versions:  true,
+		},
+		Context: ctx,
+	}
+	result, err := client.GetApiClient().MetaService.GetBulkMeta(params)
+	if err != nil {
+		return nil, fmt.Errorf("could not list versions for %s, cause: %s", pathToFile, err.Error())
+	}
+	return result.Payload.Nodes, nil
+}
+
+// GetFileVersion retrieves a specific version of a file from the server in one big download.
+// An empty versionId means the current version.
+func (client *SdkClient) GetFileVersion(ctx context.Context, pathToFile, versionId string) (io.Reader, int, error) {
+	headInput := &s3.HeadObjectInput{
+		Bucket: aws.String(client.GetBucketName()),
+		Key:    aws.String(pathToFile),
+	}
+	getInput := &s3.GetObjectInput{
+		Bucket: aws.String(client.GetBucketName()),
+		Key:    aws.String(pathToFile),
+	}
+	if versionId != "" {
+		headInput.VersionId = aws.String(versionId)
+		getInput.VersionId = aws.String(versionId)
+	}
+
+	hO, err := client.GetS3Client().HeadObject(ctx, headInput)
+	if err != nil {
+		return nil, 0, err
+	}
+	obj, err := client.GetS3Client().GetObject(ctx, getInput)
+	if err != nil {
+		return nil, 0, err
+	}
+	return obj.Body, int(*hO.ContentLength), nil
+}
+
+// RestoreVersion promotes an older version of a file as the current one:
+// the server copies the version content over the file, creating a new version in the process.
+func (client *SdkClient) RestoreVersion(ctx context.Context, pathToFile, versionId string) error {
+	if versionId == "" {
+		return fmt.Errorf("a version ID is required to restore %s", pathToFile)
+	}
+	source := url.PathEscape(client.GetBucketName()) + "/" + escapeKey(pathToFile) + "?versionId=" + url.QueryEscape(versionId)
+	_, err := client.GetS3Client().CopyObject(ctx, &s3.CopyObjectInput{
+		Bucket:     aws.String(client.GetBucketName()),
+		Key:        aws.String(pathToFile),
+		CopySource: aws.String(source),
+	})
+	client.InvalidateMeta(pathToFile)
+	if err != nil {
+		return fmt.Errorf("could not restore version %s of %s, cause: %s", versionId, pathToFile, err.Error())
+	}
+	return nil
+}
+
+// escapeKey URL-encodes each segment of an object key, keeping the slashes.
+func escapeKey(key string) string {
+	parts := strings.Split(strings.Trim(key, "/"), "/")
+	for i, p := range parts {
+		parts[i] = url.PathEscape(p)
+	}
+	return strings.Join(parts, "/")
+}
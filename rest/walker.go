@@ -24,6 +24,9 @@ var (
 
 	DryRun   bool
 	PoolSize = 3
+	// ForceUnlock allows overwriting remote files locked by other users during an upload,
+	// their locks are released once the transfer has finished.
+	ForceUnlock bool
 )
 
 // CrawlNode enables processing the scp command step by step.
@@ -37,6 +40,11 @@ type CrawlNode struct {
 	MTime       time.Time
 	Size        int64
 	NewFileName string
+	// Overwrites is set during the walk when transferring this file replaces an existing file on the target side.
+	Overwrites bool
+	// Locked is the remote node locked by another user that transferring this node replaces.
+	// Its lock is only released by ReleaseLocks, once the transfer has finished.
+	Locked *models.TreeNode
 
 	needMerge bool
 
@@ -165,6 +173,9 @@ func (c *CrawlNode) localWalk(ctx context.Context, targetFolder *CrawlNode,
 
 	if len(givenRelPath) == 0 {
 		c.RelPath = c.base()
+		if targetFolder != nil {
+			_ = c.sdkClient.PrefetchChildren(ctx, targetFolder.FullPath)
+		}
 		currTargetFolder, err = c.checkRemoteTarget(ctx, c, targetFolder, tt, tc, td)
 		if err != nil {
 			return
@@ -192,6 +203,10 @@ func (c *CrawlNode) localWalk(ctx context.Context, targetFolder *CrawlNode,
 		err = err2
 		return
 	}
+	if currTargetFolder != nil {
+		// List the target folder at once rather than stat-ing each child
+		_ = c.sdkClient.PrefetchChildren(ctx, currTargetFolder.FullPath)
+	}
 
 	// Iterate over the files
 	for _, fileInfo := range files {
@@ -208,8 +223,7 @@ func (c *CrawlNode) localWalk(ctx context.Context, targetFolder *CrawlNode,
 
 		if fileInfo.IsDir() {
 			// walk recursively
-			err4 := currLocal.localWalk(ctx, targetChild, tt, tc, td, relPath)
-			if err4 != nil { // fail fast
+			if err = currLocal.localWalk(ctx, targetChild, tt, tc, td, relPath); err != nil { // fail fast
 				return
 			}
 		}
@@ -282,6 +296,7 @@ func (c *CrawlNode) checkLocalTarget(src *CrawlNode, targetFolder *CrawlNode, tt
 				*td = append(*td, targetChild)
 				*tt = append(*tt, src)
 			} else { // We erase the local file
+				src.Overwrites = true
 				*tt = append(*tt, src)
 			}
 		}
@@ -316,9 +331,13 @@ func (c *CrawlNode) checkRemoteTarget(ctx context.Context, src *CrawlNode, targe
 			if !found { // Nothing found at this path => we can DL
 				*toTransfer = append(*toTransfer, src)
 			} else if treeNode.Type != nil && *treeNode.Type == models.TreeNodeTypeCOLLECTION { // Got a directory, must be removed before trying to force DL
-				*toDelete = append(*toTransfer, targetChild)
+				*toDelete = append(*toDelete, targetChild)
 				*toTransfer = append(*toTransfer, src)
 			} else { // We overwrite the remote file
+				if err = c.checkLock(src, treeNode); err != nil {
+					return
+				}
+				src.Overwrites = true
 				*toTransfer = append(*toTransfer, src)
 			}
 		}
@@ -335,13 +354,49 @@ func (c *CrawlNode) checkRemoteTarget(ctx context.Context, src *CrawlNode, targe
 		} else if treeNode.Type != nil && *treeNode.Type == models.TreeNodeTypeCOLLECTION {
 			// Got a directory, and we are already merging: nothing to do.
 		} else { // We erase the remote file
-			*toDelete = append(*toTransfer, targetChild)
+			if err = c.checkLock(src, treeNode); err != nil {
+				return
+			}
+			*toDelete = append(*toDelete, targetChild)
 			*toTransfer = append(*toTransfer, src)
 		}
 	}
 	return
 }
 
+// checkLock prevents overwriting a remote file that is locked by another user, unless ForceUnlock is set.
+// In such case, the locked node is only recorded on the source: the walk never modifies the target side.
+func (c *CrawlNode) checkLock(src *CrawlNode, treeNode *models.TreeNode) error {
+	if !c.sdkClient.IsLockedByOther(treeNode) {
+		return nil
+	}
+	if !ForceUnlock {
+		return fmt.Errorf("%s is locked by %s, use the --force-unlock flag to overwrite it anyway", treeNode.Path, LockOwner(treeNode))
+	}
+	src.Locked = treeNode
+	return nil
+}
+
+// ReleaseLocks releases the locks that have been recorded during the walk on the passed nodes.
+// It must be called after the transfer has finished. In dry-run mode, it only lists the locks that would be released.
+func (c *CrawlNode) ReleaseLocks(ctx context.Context, dd []*CrawlNode, dryRun bool) error {
+	for _, d := range dd {
+		if d.Locked == nil {
+			continue
+		}
+		owner := LockOwner(d.Locked)
+		if dryRun {
+			Log.Infof("Would unlock %s (locked by %s)", d.Locked.Path, owner)
+			continue
+		}
+		Log.Infof("Releasing lock of %s on %s", owner, d.Locked.Path)
+		if e := c.sdkClient.UnlockNode(ctx, d.Locked); e != nil {
+			return fmt.Errorf("could not release lock on %s: %s", d.Locked.Path, e.Error())
+		}
+	}
+	return nil
+}
+
 func (c *CrawlNode) deleteLocalItems(dd []*CrawlNode, pool *BarsPool) error {
 	for _, d := range dd {
 		toDelete := c.join(c.FullPath, d.RelPath)
@@ -608,6 +663,9 @@ func (c *CrawlNode) createRemoteFolders(target *CrawlNode, toCreateDirs []*Crawl
 			Recursive: false,
 		}
 		_, err := c.sdkClient.GetApiClient().TreeService.CreateNodes(params)
+		for _, n := range mm {
+			c.sdkClient.InvalidateMeta(n.Path)
+		}
 		if err != nil {
 			if IsDebugEnabled() {
 				return errors.Errorf("could not create folders at %s, cause: %s", target.FullPath, err.Error())
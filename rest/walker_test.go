@@ -0,0 +1,39 @@
+package rest
+
+import (
+	"context"
+	"testing"
+
+	cellsSdk "github.com/pydio/cells-sdk-go/v4"
+	"github.com/pydio/cells-sdk-go/v4/models"
+
+	// Silently import convey to ease implementation
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLocksInDryRun(t *testing.T) {
+	Convey("Test that dry-runs never release locks", t, func() {
+		// No API client: any call to the server would panic
+		client := &SdkClient{currentConfig: &CecConfig{SdkConfig: &cellsSdk.SdkConfig{User: "alice"}}}
+		target := &CrawlNode{sdkClient: client, FullPath: "common-files"}
+		src := &CrawlNode{IsLocal: true, RelPath: "drawing.dwg"}
+		locked := &models.TreeNode{Path: "common-files/drawing.dwg", MetaStore: map[string]string{MetaContentLock: `"bob"`}}
+
+		defer func(force bool) { ForceUnlock = force }(ForceUnlock)
+
+		Convey("Locked files are refused without --force-unlock", func() {
+			ForceUnlock = false
+			So(target.checkLock(src, locked), ShouldNotBeNil)
+			So(src.Locked, ShouldBeNil)
+		})
+
+		Convey("With --force-unlock, the lock is only recorded during the walk", func() {
+			ForceUnlock = true
+			So(target.checkLock(src, locked), ShouldBeNil)
+			So(src.Locked, ShouldEqual, locked)
+
+			So(target.ReleaseLocks(context.Background(), []*CrawlNode{src}, true), ShouldBeNil)
+			So(LockOwner(locked), ShouldEqual, "bob")
+		})
+	})
+}
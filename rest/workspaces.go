@@ -0,0 +1,247 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pydio/cells-sdk-go/v4/client/admin_tree_service"
+	"github.com/pydio/cells-sdk-go/v4/client/workspace_service"
+	"github.com/pydio/cells-sdk-go/v4/models"
+)
+
+const (
+	// RootGroupRole is the UUID of the role of the root group, it is inherited by all users.
+	RootGroupRole = "ROOT_GROUP"
+	// WorkspaceAttrDefaultRights is the attribute that stores the rights that are given to all users on a workspace.
+	WorkspaceAttrDefaultRights = "DEFAULT_RIGHTS"
+)
+
+// ListWorkspaces retrieves all the workspaces that have been created by administrators.
+func (client *SdkClient) ListWorkspaces(ctx context.Context) ([]*models.IdmWorkspace, error) {
+	return client.searchWorkspaces(ctx, &models.IdmWorkspaceSingleQuery{
+		Scope: models.NewIdmWorkspaceScope(models.IdmWorkspaceScopeADMIN),
+	})
+}
+
+// ListWorkspacesOfScopes lists the workspaces of the passed scopes, e.g. the cells (ROOM scope)
+// and the hidden workspaces of the public links (LINK scope) on top of the ADMIN ones.
+func (client *SdkClient) ListWorkspacesOfScopes(ctx context.Context, scopes ...models.IdmWorkspaceScope) ([]*models.IdmWorkspace, error) {
+	var all []*models.IdmWorkspace
+	for _, scope := range scopes {
+		workspaces, err := client.searchWorkspaces(ctx, &models.IdmWorkspaceSingleQuery{
+			Scope: models.NewIdmWorkspaceScope(scope),
+		})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, workspaces...)
+	}
+	return all, nil
+}
+
+// FindWorkspace retrieves a workspace by its slug.
+func (client *SdkClient) FindWorkspace(ctx context.Context, slug string) (*models.IdmWorkspace, error) {
+	workspaces, err := client.searchWorkspaces(ctx, &models.IdmWorkspaceSingleQuery{Slug: slug})
+	if err != nil {
+		return nil, err
+	}
+	for _, ws := range workspaces {
+		if ws.Slug == slug {
+			return ws, nil
+		}
+	}
+	return nil, NotFoundError(fmt.Sprintf("no workspace found with slug %s", slug))
+}
+
+// PutWorkspace creates the workspace or updates it if it already exists.
+func (client *SdkClient) PutWorkspace(ctx context.Context, ws *models.IdmWorkspace) (*models.IdmWorkspace, error) {
+	params := &workspace_service.PutWorkspaceParams{
+		Slug:    ws.Slug,
+		Body:    ws,
+		Context: ctx,
+	}
+	result, err := client.GetApiClient().WorkspaceService.PutWorkspace(params)
+	if err != nil {
+		return nil, fmt.Errorf("could not store workspace %s, cause: %s", ws.Slug, err.Error())
+	}
+	return result.Payload, nil
+}
+
+// DeleteWorkspace removes a workspace. The files that are in its root folders are not impacted.
+func (client *SdkClient) DeleteWorkspace(ctx context.Context, slug string) error {
+	params := &workspace_service.DeleteWorkspaceParams{Slug: slug, Context: ctx}
+	if _, err := client.GetApiClient().WorkspaceService.DeleteWorkspace(params); err != nil {
+		return fmt.Errorf("could not delete workspace %s, cause: %s", slug, err.Error())
+	}
+	return nil
+}
+
+// StatAdminNode retrieves a node by its path in the administration tree, that starts with the name of the datasource.
+func (client *SdkClient) StatAdminNode(ctx context.Context, adminPath string) (*models.TreeNode, error) {
+	params := &admin_tree_service.StatAdminTreeParams{
+		Body:    &models.TreeReadNodeRequest{Node: &models.TreeNode{Path: strings.Trim(adminPath, "/")}},
+		Context: ctx,
+	}
+	result, err := client.GetApiClient().AdminTreeService.StatAdminTree(params)
+	if err != nil {
+		return nil, fmt.Errorf("could not find %s in datasources, cause: %s", adminPath, err.Error())
+	}
+	return result.Payload.Node, nil
+}
+
+// StatAdminNodeByUuid retrieves a node of the administration tree by its UUID.
+func (client *SdkClient) StatAdminNodeByUuid(ctx context.Context, nodeUuid string) (*models.TreeNode, error) {
+	params := &admin_tree_service.StatAdminTreeParams{
+		Body:    &models.TreeReadNodeRequest{Node: &models.TreeNode{UUID: nodeUuid}},
+		Context: ctx,
+	}
+	result, err := client.GetApiClient().AdminTreeService.StatAdminTree(params)
+	if err != nil {
+		return nil, fmt.Errorf("could not find node %s in datasources, cause: %s", nodeUuid, err.Error())
+	}
+	return result.Payload.Node, nil
+}
+
+// SetWorkspaceDefaultRights gives read ("r"), read and write ("rw") or no ("") access to all users on the root nodes of a workspace,
+// through ACLs on the role of the root group. The workspace attributes are not modified.
+func (client *SdkClient) SetWorkspaceDefaultRights(ctx context.Context, ws *models.IdmWorkspace, rights string) error {
+	existing, err := client.SearchAcls(ctx, &models.IdmACLSingleQuery{
+		RoleIDs:      []string{RootGroupRole},
+		WorkspaceIDs: []string{ws.UUID},
+	})
+	if err != nil {
+		return err
+	}
+	for _, acl := range existing {
+		if acl.Action != nil && (acl.Action.Name == "read" || acl.Action.Name == "write") {
+			if err = client.DeleteAcl(ctx, acl); err != nil {
+				return err
+			}
+		}
+	}
+
+	var actions []string
+	if strings.Contains(rights, "r") {
+		actions = append(actions, "read")
+	}
+	if strings.Contains(rights, "w") {
+		actions = append(actions, "write")
+	}
+	for _, root := range WorkspaceRootUuids(ws) {
+		for _, a := range actions {
+			acl := &models.IdmACL{
+				Action:      &models.IdmACLAction{Name: a, Value: "1"},
+				RoleID:      RootGroupRole,
+				WorkspaceID: ws.UUID,
+				NodeID:      root,
+			}
+			if err = client.PutAcl(ctx, acl); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// MoveWorkspaceRootAcls moves the ACLs that are set on the former roots of a workspace to its current roots,
+// so that the roles keep their rights when the roots of a workspace are replaced.
+func (client *SdkClient) MoveWorkspaceRootAcls(ctx context.Context, ws *models.IdmWorkspace, oldRoots []string) error {
+	if len(oldRoots) == 0 {
+		return nil
+	}
+	existing, err := client.SearchAcls(ctx, &models.IdmACLSingleQuery{
+		WorkspaceIDs: []string{ws.UUID},
+		NodeIDs:      oldRoots,
+	})
+	if err != nil {
+		return err
+	}
+	toPut, toDelete := rootAclsToMove(ws.UUID, existing, oldRoots, WorkspaceRootUuids(ws))
+	for _, acl := range toPut {
+		if err = client.PutAcl(ctx, acl); err != nil {
+			return err
+		}
+	}
+	for _, acl := range toDelete {
+		if err = client.DeleteAcl(ctx, acl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rootAclsToMove computes the ACLs to create on the added roots, with the union of the actions that each role has
+// on the removed roots, and the ACLs of the removed roots that must then be deleted. Roots that are kept are not modified.
+func rootAclsToMove(wsUuid string, acls []*models.IdmACL, oldRoots, newRoots []string) (toPut, toDelete []*models.IdmACL) {
+	isOld, isNew := make(map[string]bool), make(map[string]bool)
+	for _, r := range oldRoots {
+		isOld[r] = true
+	}
+	for _, r := range newRoots {
+		isNew[r] = true
+	}
+
+	type roleAction struct{ role, action, value string }
+	var actions []roleAction
+	seen := make(map[roleAction]bool)
+	for _, acl := range acls {
+		if acl.Action == nil || !isOld[acl.NodeID] || isNew[acl.NodeID] {
+			continue
+		}
+		toDelete = append(toDelete, acl)
+		ra := roleAction{role: acl.RoleID, action: acl.Action.Name, value: acl.Action.Value}
+		if !seen[ra] {
+			seen[ra] = true
+			actions = append(actions, ra)
+		}
+	}
+	for _, root := range newRoots {
+		if isOld[root] {
+			continue
+		}
+		for _, ra := range actions {
+			toPut = append(toPut, &models.IdmACL{
+				Action:      &models.IdmACLAction{Name: ra.action, Value: ra.value},
+				RoleID:      ra.role,
+				WorkspaceID: wsUuid,
+				NodeID:      root,
+			})
+		}
+	}
+	return
+}
+
+// WorkspaceAttributes decodes the JSON attributes of a workspace.
+func WorkspaceAttributes(ws *models.IdmWorkspace) map[string]interface{} {
+	attributes := make(map[string]interface{})
+	if ws.Attributes != "" {
+		_ = json.Unmarshal([]byte(ws.Attributes), &attributes)
+	}
+	return attributes
+}
+
+// WorkspaceRootUuids returns the UUIDs of the root nodes of a workspace.
+func WorkspaceRootUuids(ws *models.IdmWorkspace) []string {
+	if len(ws.RootUUIDs) > 0 {
+		return ws.RootUUIDs
+	}
+	var uuids []string
+	for id := range ws.RootNodes {
+		uuids = append(uuids, id)
+	}
+	return uuids
+}
+
+func (client *SdkClient) searchWorkspaces(ctx context.Context, queries ...*models.IdmWorkspaceSingleQuery) ([]*models.IdmWorkspace, error) {
+	params := &workspace_service.SearchWorkspacesParams{
+		Body:    &models.RestSearchWorkspaceRequest{Queries: queries},
+		Context: ctx,
+	}
+	result, err := client.GetApiClient().WorkspaceService.SearchWorkspaces(params)
+	if err != nil {
+		return nil, fmt.Errorf("could not search workspaces, cause: %s", err.Error())
+	}
+	return result.Payload.Workspaces, nil
+}
@@ -0,0 +1,42 @@
+package rest
+
+import (
+	"testing"
+
+	"github.com/pydio/cells-sdk-go/v4/models"
+
+	// Silently import convey to ease implementation
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func rootAcl(roleID, nodeID, action string) *models.IdmACL {
+	return &models.IdmACL{RoleID: roleID, NodeID: nodeID, WorkspaceID: "ws-acme", Action: &models.IdmACLAction{Name: action, Value: "1"}}
+}
+
+func TestRootAclsToMove(t *testing.T) {
+	Convey("Test moving of the ACLs when only the roots of a workspace are updated", t, func() {
+		acls := []*models.IdmACL{
+			rootAcl("group-sales", "root-old", "read"),
+			rootAcl("group-sales", "root-old", "write"),
+			rootAcl("user-alice", "root-old", "read"),
+			rootAcl("user-alice", "root-kept", "read"),
+		}
+		toPut, toDelete := rootAclsToMove("ws-acme", acls, []string{"root-old", "root-kept"}, []string{"root-kept", "root-new"})
+
+		// Without DEFAULT_RIGHTS attribute, no right is lost and ACLs of the kept root are left untouched
+		So(toPut, ShouldHaveLength, 3)
+		for i, acl := range toPut {
+			So(acl.NodeID, ShouldEqual, "root-new")
+			So(acl.WorkspaceID, ShouldEqual, "ws-acme")
+			So(acl.RoleID, ShouldEqual, acls[i].RoleID)
+			So(acl.Action.Name, ShouldEqual, acls[i].Action.Name)
+		}
+		So(toDelete, ShouldResemble, acls[:3])
+
+		Convey("Nothing is moved when the roots are unchanged", func() {
+			toPut, toDelete := rootAclsToMove("ws-acme", acls, []string{"root-old", "root-kept"}, []string{"root-old", "root-kept"})
+			So(toPut, ShouldBeEmpty)
+			So(toDelete, ShouldBeEmpty)
+		})
+	})
+}